@@ -0,0 +1,311 @@
+// Package buildinfo reads the build information the Go linker embeds in
+// binaries: the Go version, the main module, its dependencies and the build
+// settings.
+package buildinfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+// BuildInfo is the build information of a Go binary.
+type BuildInfo struct {
+	GoVersion string
+	// Path is the package path of the main package.
+	Path     string
+	Main     Module
+	Deps     []*Module
+	Settings []BuildSetting
+}
+
+// Module is a module the binary was built from.
+type Module struct {
+	Path    string
+	Version string
+	Sum     string
+	Replace *Module
+}
+
+// BuildSetting is a setting the binary was built with, such as -ldflags,
+// CGO_ENABLED or vcs.revision.
+type BuildSetting struct {
+	Key   string
+	Value string
+}
+
+// Setting returns the value of the build setting key.
+func (bi *BuildInfo) Setting(key string) (string, bool) {
+	for _, s := range bi.Settings {
+		if s.Key == key {
+			return s.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// The build information blob starts with a header of 32 bytes: the magic,
+// the pointer size and flags. Before Go 1.18 the header then holds pointers
+// to the version and module information strings; since then the strings
+// follow the header as varint-prefixed contents.
+var buildInfoMagic = []byte("\xff Go buildinf:")
+
+const (
+	buildInfoAlign      = 16
+	buildInfoHeaderSize = 32
+
+	flagsEndianBig = 0x1
+	flagsInline    = 0x2
+)
+
+// modinfoSentinelSize is the size of the sentinels that surround the module
+// information string.
+const modinfoSentinelSize = 16
+
+// Read returns the build information of the Go binary e. It is read from the
+// .go.buildinfo section, or without one from the writable PT_LOAD segments.
+// Read returns nil if the file has no build information.
+func Read(e *elf.File) (*BuildInfo, error) {
+	blob, addr, err := findBlob(e)
+	if blob == nil || err != nil {
+		return nil, err
+	}
+	if len(blob) < buildInfoHeaderSize {
+		return nil, fmt.Errorf("build information at %#x too short", addr)
+	}
+
+	var vers, mod string
+	flags := blob[len(buildInfoMagic)+1]
+	if flags&flagsInline != 0 {
+		rest := blob[buildInfoHeaderSize:]
+		if vers, rest, err = inlineString(rest); err != nil {
+			return nil, fmt.Errorf("failed to read go version: %w", err)
+		}
+		if mod, _, err = inlineString(rest); err != nil {
+			return nil, fmt.Errorf("failed to read module information: %w", err)
+		}
+	} else {
+		var order binary.ByteOrder = binary.LittleEndian
+		if flags&flagsEndianBig != 0 {
+			order = binary.BigEndian
+		}
+		ptrSize := int(blob[len(buildInfoMagic)])
+		if ptrSize != 4 && ptrSize != 8 {
+			return nil, fmt.Errorf("invalid build information pointer size: %d", ptrSize)
+		}
+
+		p := &pointers{e: e, order: order, size: ptrSize}
+		off := len(buildInfoMagic) + 2
+		if vers, err = p.string(p.word(blob[off:])); err != nil {
+			return nil, fmt.Errorf("failed to read go version: %w", err)
+		}
+		if mod, err = p.string(p.word(blob[off+ptrSize:])); err != nil {
+			return nil, fmt.Errorf("failed to read module information: %w", err)
+		}
+	}
+
+	// Like debug/buildinfo, a blob without a Go version is not build
+	// information.
+	if vers == "" {
+		return nil, nil
+	}
+
+	if len(mod) >= 2*modinfoSentinelSize+1 && mod[len(mod)-modinfoSentinelSize-1] == '\n' {
+		mod = mod[modinfoSentinelSize : len(mod)-modinfoSentinelSize]
+	} else {
+		mod = ""
+	}
+
+	bi, err := parseModInfo(mod)
+	if err != nil {
+		return nil, err
+	}
+	bi.GoVersion = vers
+
+	return bi, nil
+}
+
+// findBlob returns the build information blob, running to the end of its
+// section or segment, and its address. It returns nil if there is none.
+func findBlob(e *elf.File) ([]byte, uint64, error) {
+	if s := e.SectionByName(".go.buildinfo"); s != nil && s.Header.Type != elf.SHT_NOBITS {
+		data, err := s.Data()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read .go.buildinfo: %w", err)
+		}
+		if !bytes.HasPrefix(data, buildInfoMagic) {
+			return nil, 0, fmt.Errorf("invalid build information magic")
+		}
+		return data, s.Header.Addr, nil
+	}
+
+	for _, sg := range e.SegmentsByType(elf.PT_LOAD) {
+		if sg.Header.Flags&elf.PF_W == 0 {
+			continue
+		}
+		data, err := sg.Data()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read segment: %w", err)
+		}
+
+		// The blob is aligned in memory, which the file offset of the
+		// segment need not be.
+		start := (buildInfoAlign - sg.Header.Vaddr%buildInfoAlign) % buildInfoAlign
+		for off := start; off < uint64(len(data)); off += buildInfoAlign {
+			if bytes.HasPrefix(data[off:], buildInfoMagic) {
+				return data[off:], sg.Header.Vaddr + off, nil
+			}
+		}
+	}
+
+	return nil, 0, nil
+}
+
+// inlineString decodes a varint-prefixed string at the start of b and
+// returns it with the rest of b.
+func inlineString(b []byte) (string, []byte, error) {
+	n, size := binary.Uvarint(b)
+	if size <= 0 || n > uint64(len(b)-size) {
+		return "", nil, fmt.Errorf("invalid string length")
+	}
+
+	return string(b[size : size+int(n)]), b[size+int(n):], nil
+}
+
+// pointers reads the Go strings the pre-1.18 layout points at.
+type pointers struct {
+	e     *elf.File
+	order binary.ByteOrder
+	size  int
+}
+
+func (p *pointers) word(b []byte) uint64 {
+	if p.size == 4 {
+		return uint64(p.order.Uint32(b))
+	}
+
+	return p.order.Uint64(b)
+}
+
+// string reads the string whose header, a data pointer and a length, is at
+// addr.
+func (p *pointers) string(addr uint64) (string, error) {
+	hdr, err := p.e.ReadAddr(addr, uint64(2*p.size))
+	if err != nil {
+		return "", err
+	}
+
+	data, n := p.word(hdr), p.word(hdr[p.size:])
+	if n == 0 {
+		return "", nil
+	}
+	b, err := p.e.ReadAddr(data, n)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// parseModInfo decodes the module information the go command records, one
+// tab-separated entry per line.
+func parseModInfo(s string) (*BuildInfo, error) {
+	bi := &BuildInfo{}
+	var last *Module
+	for i, line := range strings.Split(s, "\n") {
+		kind, rest, _ := strings.Cut(line, "\t")
+		fields := strings.Split(rest, "\t")
+
+		var err error
+		switch kind {
+		case "path":
+			bi.Path = rest
+		case "mod":
+			last = &bi.Main
+			err = readModule(last, fields)
+		case "dep":
+			last = &Module{}
+			bi.Deps = append(bi.Deps, last)
+			err = readModule(last, fields)
+		case "=>":
+			if last == nil {
+				err = fmt.Errorf("replacement without a module")
+				break
+			}
+			last.Replace = &Module{}
+			err = readModule(last.Replace, fields)
+			last = nil
+		case "build":
+			var setting BuildSetting
+			setting, err = readSetting(rest)
+			bi.Settings = append(bi.Settings, setting)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid module information line %d: %w", i+1, err)
+		}
+	}
+
+	return bi, nil
+}
+
+func readModule(m *Module, fields []string) error {
+	if len(fields) != 2 && len(fields) != 3 {
+		return fmt.Errorf("expected 2 or 3 columns, have %d", len(fields))
+	}
+
+	m.Path, m.Version = fields[0], fields[1]
+	if len(fields) == 3 {
+		m.Sum = fields[2]
+	}
+
+	return nil
+}
+
+// readSetting decodes a key=value build setting. Keys and values containing
+// spaces, quotes, tabs or '=' are quoted.
+func readSetting(kv string) (BuildSetting, error) {
+	key, rest, err := unquotePrefix(kv, '=')
+	if err != nil {
+		return BuildSetting{}, err
+	}
+	if key == "" || !strings.HasPrefix(rest, "=") {
+		return BuildSetting{}, fmt.Errorf("invalid build setting %q", kv)
+	}
+
+	value, rest, err := unquotePrefix(rest[1:], 0)
+	if err != nil {
+		return BuildSetting{}, err
+	}
+	if rest != "" {
+		return BuildSetting{}, fmt.Errorf("unexpected text after build setting value: %q", rest)
+	}
+
+	return BuildSetting{Key: key, Value: value}, nil
+}
+
+// unquotePrefix returns the possibly quoted string at the start of s, ending
+// at sep if it is not quoted, and the rest of s.
+func unquotePrefix(s string, sep byte) (string, string, error) {
+	if s == "" || s[0] != '"' && s[0] != '`' {
+		if i := strings.IndexByte(s, sep); sep != 0 && i >= 0 {
+			return s[:i], s[i:], nil
+		}
+		return s, "", nil
+	}
+
+	q, err := strconv.QuotedPrefix(s)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid quoted string %q", s)
+	}
+	u, err := strconv.Unquote(q)
+	if err != nil {
+		return "", "", err
+	}
+
+	return u, s[len(q):], nil
+}
@@ -0,0 +1,244 @@
+package buildinfo_test
+
+import (
+	"bytes"
+	stdbuildinfo "debug/buildinfo"
+	"encoding/binary"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hnts/goelftools/buildinfo"
+	"github.com/hnts/goelftools/elf"
+)
+
+func TestRead(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		version string
+		path    string
+	}{
+		{"../testdata/elf_linux_amd64", "go1.16.6", "command-line-arguments"},
+		{"../testdata/gohello_linux_amd64", "go1.27.1", "example.com/hello"},
+	} {
+		bi, err := buildinfo.Read(mustNew(t, tt.name))
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if bi.GoVersion != tt.version || bi.Path != tt.path {
+			t.Errorf("%s: have version %q and path %q", tt.name, bi.GoVersion, bi.Path)
+		}
+
+		std, err := stdbuildinfo.ReadFile(tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &buildinfo.BuildInfo{
+			GoVersion: std.GoVersion,
+			Path:      std.Path,
+			Main:      buildinfo.Module{Path: std.Main.Path, Version: std.Main.Version, Sum: std.Main.Sum},
+		}
+		for _, d := range std.Deps {
+			want.Deps = append(want.Deps, &buildinfo.Module{Path: d.Path, Version: d.Version, Sum: d.Sum})
+		}
+		for _, s := range std.Settings {
+			want.Settings = append(want.Settings, buildinfo.BuildSetting{Key: s.Key, Value: s.Value})
+		}
+		if !reflect.DeepEqual(bi, want) {
+			t.Errorf("%s:\n\thave %#v\n\twant %#v\n", tt.name, bi, want)
+		}
+	}
+
+	bi, err := buildinfo.Read(mustNew(t, "../testdata/gohello_linux_amd64"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dep := &buildinfo.Module{Path: "golang.org/x/sync", Version: "v0.10.0", Sum: "h1:3NQrjDixjgGwUOCaF8w2+VYHv0Ve/vGYSbdkTa98gmQ="}
+	if len(bi.Deps) != 1 || !reflect.DeepEqual(bi.Deps[0], dep) {
+		t.Errorf("have dependencies %#v", bi.Deps)
+	}
+	if v, ok := bi.Setting("CGO_ENABLED"); v != "0" || !ok {
+		t.Errorf("have CGO_ENABLED %q", v)
+	}
+}
+
+const modinfo = "path\texample.com/cmd/tool\n" +
+	"mod\texample.com/cmd\tv1.2.3\th1:main=\n" +
+	"dep\texample.com/lib\tv0.1.0\th1:lib=\n" +
+	"=>\t../lib\t(devel)\t\n" +
+	"dep\texample.com/other\tv2.0.0+incompatible\th1:other=\n" +
+	"build\t-ldflags=\"-X main.version=1.0 -s\"\n" +
+	"build\tCGO_ENABLED=1\n" +
+	"build\tvcs.revision=0123456789abcdef\n" +
+	"build\t\"weird key\"=value\n"
+
+var wantSynthetic = &buildinfo.BuildInfo{
+	GoVersion: "go1.99",
+	Path:      "example.com/cmd/tool",
+	Main:      buildinfo.Module{Path: "example.com/cmd", Version: "v1.2.3", Sum: "h1:main="},
+	Deps: []*buildinfo.Module{
+		{Path: "example.com/lib", Version: "v0.1.0", Sum: "h1:lib=", Replace: &buildinfo.Module{Path: "../lib", Version: "(devel)"}},
+		{Path: "example.com/other", Version: "v2.0.0+incompatible", Sum: "h1:other="},
+	},
+	Settings: []buildinfo.BuildSetting{
+		{Key: "-ldflags", Value: "-X main.version=1.0 -s"},
+		{Key: "CGO_ENABLED", Value: "1"},
+		{Key: "vcs.revision", Value: "0123456789abcdef"},
+		{Key: "weird key", Value: "value"},
+	},
+}
+
+// sentinel stands for the markers the go command puts around the module
+// information.
+var sentinel = strings.Repeat("\xaa", 16)
+
+// buildInline builds a little-endian 64-bit file with the Go 1.18 layout.
+func buildInline(t *testing.T) []byte {
+	t.Helper()
+
+	blob := buildInfoHeader(8, 0x2)
+	for _, s := range []string{"go1.99", sentinel + modinfo + sentinel} {
+		blob = binary.AppendUvarint(blob, uint64(len(s)))
+		blob = append(blob, s...)
+	}
+
+	b := elf.NewBuilder(elf.ELFCLASS64, binary.LittleEndian, elf.EM_X86_64, elf.ET_EXEC)
+	data := b.AddSection(".go.buildinfo", elf.SectionHeader{Type: elf.SHT_PROGBITS, Flags: elf.SHF_ALLOC | elf.SHF_WRITE, Addr: 0x500000, Addralign: 16}, blob)
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_LOAD, Flags: elf.PF_R | elf.PF_W, Align: 0x1000}, data)
+
+	return mustBuild(t, b)
+}
+
+// buildPointers builds a big-endian 32-bit file with the layout before Go
+// 1.18, where the header points at the strings.
+func buildPointers(t *testing.T) []byte {
+	t.Helper()
+
+	const rodataAddr = 0x10000
+	rodata := []byte{}
+	var headers [2]uint32
+	for i, s := range []string{"go1.17", sentinel + modinfo + sentinel} {
+		addr := rodataAddr + uint32(len(rodata))
+		rodata = append(rodata, s...)
+		headers[i] = rodataAddr + uint32(len(rodata))
+		rodata = binary.BigEndian.AppendUint32(rodata, addr)
+		rodata = binary.BigEndian.AppendUint32(rodata, uint32(len(s)))
+	}
+
+	blob := buildInfoHeader(4, 0x1)[:16]
+	blob = binary.BigEndian.AppendUint32(blob, headers[0])
+	blob = binary.BigEndian.AppendUint32(blob, headers[1])
+	blob = append(blob, make([]byte, 8)...)
+
+	b := elf.NewBuilder(elf.ELFCLASS32, binary.BigEndian, elf.EM_PPC, elf.ET_EXEC)
+	ro := b.AddSection(".rodata", elf.SectionHeader{Type: elf.SHT_PROGBITS, Flags: elf.SHF_ALLOC, Addr: rodataAddr, Addralign: 4}, rodata)
+	data := b.AddSection(".go.buildinfo", elf.SectionHeader{Type: elf.SHT_PROGBITS, Flags: elf.SHF_ALLOC | elf.SHF_WRITE, Addr: 0x20010, Addralign: 16}, blob)
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_LOAD, Flags: elf.PF_R, Align: 0x1000}, ro)
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_LOAD, Flags: elf.PF_R | elf.PF_W, Align: 0x1000}, data)
+
+	return mustBuild(t, b)
+}
+
+func buildInfoHeader(ptrSize, flags byte) []byte {
+	hdr := make([]byte, 32)
+	copy(hdr, "\xff Go buildinf:")
+	hdr[14], hdr[15] = ptrSize, flags
+
+	return hdr
+}
+
+func TestReadLayouts(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		raw     []byte
+		version string
+	}{
+		{"inline", buildInline(t), "go1.99"},
+		{"pointers", buildPointers(t), "go1.17"},
+	} {
+		e, err := elf.New(tt.raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := *wantSynthetic
+		want.GoVersion = tt.version
+
+		bi, err := buildinfo.Read(e)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if !reflect.DeepEqual(bi, &want) {
+			t.Errorf("%s:\n\thave %#v\n\twant %#v\n", tt.name, bi, &want)
+		}
+
+		// Without section headers the blob is found in the data segment.
+		e.Sections = nil
+		var buf bytes.Buffer
+		if _, err := e.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		stripped, err := elf.New(buf.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		bi, err = buildinfo.Read(stripped)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if !reflect.DeepEqual(bi, &want) {
+			t.Errorf("%s (stripped):\n\thave %#v\n\twant %#v\n", tt.name, bi, &want)
+		}
+	}
+}
+
+func TestReadNotGo(t *testing.T) {
+	bi, err := buildinfo.Read(mustNew(t, "../testdata/hello_linux_amd64"))
+	if bi != nil || err != nil {
+		t.Errorf("have %#v (%v), want no build information", bi, err)
+	}
+}
+
+func TestReadMalformed(t *testing.T) {
+	e, err := elf.New(buildInline(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := e.SectionByName(".go.buildinfo")
+
+	s.Raw = s.Raw[:40]
+	if _, err := buildinfo.Read(e); err == nil {
+		t.Errorf("truncated module information should fail")
+	}
+
+	s.Raw = []byte("not build info")
+	if _, err := buildinfo.Read(e); err == nil {
+		t.Errorf("invalid magic should fail")
+	}
+}
+
+func mustNew(t *testing.T, name string) *elf.File {
+	t.Helper()
+
+	raw, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return e
+}
+
+func mustBuild(t *testing.T, b *elf.Builder) []byte {
+	t.Helper()
+
+	raw, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return raw
+}
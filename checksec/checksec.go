@@ -0,0 +1,295 @@
+// Package checksec reports the exploit mitigations an ELF binary was built
+// with, like the checksec.sh script: RELRO, a non-executable stack, position
+// independence, stack canaries, FORTIFY_SOURCE, the library search paths it
+// embeds, the control-flow protection of its GNU property note and text
+// relocations.
+package checksec
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+// RELRO is how much of the relocated data is made read-only after
+// relocation.
+type RELRO int
+
+const (
+	// NoRELRO means there is no PT_GNU_RELRO segment.
+	NoRELRO RELRO = iota
+	// PartialRELRO protects the segment, but leaves the GOT entries of
+	// lazily bound functions writable.
+	PartialRELRO
+	// FullRELRO binds every symbol at load time, so the whole GOT is
+	// protected.
+	FullRELRO
+)
+
+func (r RELRO) String() string {
+	switch r {
+	case NoRELRO:
+		return "No RELRO"
+	case PartialRELRO:
+		return "Partial RELRO"
+	case FullRELRO:
+		return "Full RELRO"
+	}
+	return fmt.Sprintf("RELRO(%d)", int(r))
+}
+
+// PIE is whether the binary can be loaded at a random address.
+type PIE int
+
+const (
+	// NoPIE is an executable linked at a fixed address.
+	NoPIE PIE = iota
+	// PIEEnabled is a position independent executable.
+	PIEEnabled
+	// DSO is a shared object, which is position independent too.
+	DSO
+	// Relocatable is an object file, which is not loaded as is.
+	Relocatable
+)
+
+func (p PIE) String() string {
+	switch p {
+	case NoPIE:
+		return "No PIE"
+	case PIEEnabled:
+		return "PIE enabled"
+	case DSO:
+		return "DSO"
+	case Relocatable:
+		return "REL"
+	}
+	return fmt.Sprintf("PIE(%d)", int(p))
+}
+
+// Report lists the mitigations of a binary.
+type Report struct {
+	RELRO RELRO
+	// NX is set when the PT_GNU_STACK segment makes the stack
+	// non-executable. Without the segment, the stack is executable.
+	NX  bool
+	PIE PIE
+	// Canary is set when the binary uses __stack_chk_fail or
+	// __stack_chk_guard, which the code checking stack canaries calls.
+	Canary bool
+	// Fortified lists the checked *_chk functions the binary uses in
+	// place of the functions FORTIFY_SOURCE protects, such as
+	// __memcpy_chk.
+	//
+	// A function is used when the binary imports it, or defines it and
+	// calls it: statically linked binaries carry the functions of the C
+	// library whether they call them or not. Calls are only found in x86
+	// and AArch64 code.
+	Fortified []string
+	// RPath and RunPath are the directories of DT_RPATH and DT_RUNPATH.
+	RPath   []string
+	RunPath []string
+	// IBT and SHSTK are the x86 control-flow enforcement features, indirect
+	// branch tracking and shadow stacks, every input object of the binary
+	// was built for.
+	IBT   bool
+	SHSTK bool
+	// BTI and PAC are the AArch64 branch target identification and
+	// pointer authentication features.
+	BTI bool
+	PAC bool
+	// TextRel is set when the binary has relocations against read-only
+	// segments, which make the loader write to its code.
+	TextRel bool
+}
+
+// Fortify reports whether the binary uses FORTIFY_SOURCE.
+func (r *Report) Fortify() bool {
+	return len(r.Fortified) > 0
+}
+
+// Analyze inspects e and returns its report.
+func Analyze(e *elf.File) (*Report, error) {
+	r := &Report{}
+
+	des, err := e.DynamicEntries()
+	if err != nil {
+		return nil, err
+	}
+	flags, err := e.DynFlags()
+	if err != nil {
+		return nil, err
+	}
+	flags1, err := e.DynFlags1()
+	if err != nil {
+		return nil, err
+	}
+	bindNow := flags&elf.DF_BIND_NOW != 0 || flags1&elf.DF_1_NOW != 0
+	for _, de := range des {
+		switch de.Tag {
+		case elf.DT_BIND_NOW:
+			bindNow = true
+		case elf.DT_TEXTREL:
+			r.TextRel = true
+		}
+	}
+	if flags&elf.DF_TEXTREL != 0 {
+		r.TextRel = true
+	}
+
+	if len(e.SegmentsByType(elf.PT_GNU_RELRO)) > 0 {
+		r.RELRO = PartialRELRO
+		if bindNow {
+			r.RELRO = FullRELRO
+		}
+	}
+	if sgs := e.SegmentsByType(elf.PT_GNU_STACK); len(sgs) > 0 {
+		r.NX = sgs[0].Header.Flags&elf.PF_X == 0
+	}
+
+	switch e.Header.Type {
+	case elf.ET_DYN:
+		// Executables linked before DF_1_PIE existed are told from shared
+		// objects by their interpreter.
+		r.PIE = DSO
+		if flags1&elf.DF_1_PIE != 0 || len(e.SegmentsByType(elf.PT_INTERP)) > 0 {
+			r.PIE = PIEEnabled
+		}
+	case elf.ET_REL:
+		r.PIE = Relocatable
+	}
+
+	if r.RPath, err = e.RPath(); err != nil {
+		return nil, err
+	}
+	if r.RunPath, err = e.RunPath(); err != nil {
+		return nil, err
+	}
+
+	if err := r.checkSymbols(e); err != nil {
+		return nil, err
+	}
+	if err := r.checkProperties(e); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// checkSymbols looks for the stack protector and FORTIFY_SOURCE functions.
+func (r *Report) checkSymbols(e *elf.File) error {
+	dynsyms, err := e.DynamicSymbols()
+	if err != nil {
+		return err
+	}
+	syms, err := e.Symbols()
+	if err != nil {
+		return err
+	}
+
+	defined := map[uint64][]string{}
+	for _, sym := range append(dynsyms, syms...) {
+		if sym.Name != "__stack_chk_fail" && sym.Name != "__stack_chk_guard" &&
+			!(strings.HasPrefix(sym.Name, "__") && strings.HasSuffix(sym.Name, "_chk")) {
+			continue
+		}
+		switch {
+		case sym.Section == elf.SHN_UNDEF:
+			r.use(sym.Name)
+		case sym.Type == elf.STT_FUNC:
+			defined[sym.Value] = append(defined[sym.Value], sym.Name)
+		}
+	}
+	if len(defined) > 0 {
+		if err := r.checkCalls(e, defined); err != nil {
+			return err
+		}
+	}
+	slices.Sort(r.Fortified)
+
+	return nil
+}
+
+// use records that the binary uses the function name.
+func (r *Report) use(name string) {
+	switch {
+	case name == "__stack_chk_fail" || name == "__stack_chk_guard":
+		r.Canary = true
+	case !slices.Contains(r.Fortified, name):
+		r.Fortified = append(r.Fortified, name)
+	}
+}
+
+// checkCalls records the functions of funcs, indexed by address, that the
+// executable sections of e call directly.
+func (r *Report) checkCalls(e *elf.File, funcs map[uint64][]string) error {
+	mask := ^uint64(0)
+	if e.Header.Class() == elf.ELFCLASS32 {
+		mask = 0xffffffff
+	}
+	call := func(target uint64) {
+		for _, name := range funcs[target&mask] {
+			r.use(name)
+		}
+	}
+
+	for _, s := range e.Sections {
+		h := s.Header
+		if h.Type != elf.SHT_PROGBITS || h.Flags&elf.SHF_EXECINSTR == 0 {
+			continue
+		}
+		code, err := s.Data()
+		if err != nil {
+			return err
+		}
+
+		switch e.Header.Machine {
+		case elf.EM_386, elf.EM_X86_64:
+			// call rel32
+			for i := 0; i+5 <= len(code); i++ {
+				if code[i] == 0xe8 {
+					rel := int32(e.Endianness.Uint32(code[i+1:]))
+					call(h.Addr + uint64(i+5) + uint64(int64(rel)))
+				}
+			}
+		case elf.EM_AARCH64:
+			// bl imm26, counted in instructions
+			for i := 0; i+4 <= len(code); i += 4 {
+				if insn := e.Endianness.Uint32(code[i:]); insn&0xfc000000 == 0x94000000 {
+					call(h.Addr + uint64(i) + uint64(int64(int32(insn<<6)>>4)))
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkProperties reads the control-flow protection features of the GNU
+// property note.
+func (r *Report) checkProperties(e *elf.File) error {
+	ps, err := e.GNUProperties()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range ps {
+		switch {
+		case p.Type == elf.GNU_PROPERTY_X86_FEATURE_1_AND && isX86(e.Header.Machine):
+			f := elf.X86Feature1(p.Value)
+			r.IBT = f&elf.GNU_PROPERTY_X86_FEATURE_1_IBT != 0
+			r.SHSTK = f&elf.GNU_PROPERTY_X86_FEATURE_1_SHSTK != 0
+		case p.Type == elf.GNU_PROPERTY_AARCH64_FEATURE_1_AND && e.Header.Machine == elf.EM_AARCH64:
+			f := elf.AArch64Feature1(p.Value)
+			r.BTI = f&elf.GNU_PROPERTY_AARCH64_FEATURE_1_BTI != 0
+			r.PAC = f&elf.GNU_PROPERTY_AARCH64_FEATURE_1_PAC != 0
+		}
+	}
+
+	return nil
+}
+
+func isX86(m elf.Machine) bool {
+	return m == elf.EM_X86_64 || m == elf.EM_386
+}
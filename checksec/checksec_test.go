@@ -0,0 +1,177 @@
+package checksec_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hnts/goelftools/checksec"
+	"github.com/hnts/goelftools/elf"
+)
+
+func TestAnalyze(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		want *checksec.Report
+	}{
+		{"../testdata/hello_linux_amd64", &checksec.Report{
+			RELRO: checksec.FullRELRO, NX: true, PIE: checksec.PIEEnabled, Canary: true,
+			Fortified: []string{"__printf_chk", "__strcpy_chk"}, IBT: true, SHSTK: true,
+		}},
+		{"../testdata/crash_linux_amd64", &checksec.Report{
+			RELRO: checksec.PartialRELRO, NX: true, PIE: checksec.PIEEnabled,
+		}},
+		{"../testdata/libsample_linux_amd64.so", &checksec.Report{
+			RELRO: checksec.FullRELRO, NX: true, PIE: checksec.DSO, RunPath: []string{"$ORIGIN/../lib"},
+		}},
+		{"../testdata/elf_linux_amd64", &checksec.Report{
+			RELRO: checksec.NoRELRO, NX: true, PIE: checksec.NoPIE,
+		}},
+		{"../testdata/libsample_linux_amd64.o", &checksec.Report{
+			PIE: checksec.Relocatable,
+		}},
+	} {
+		e, err := elf.Open(tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		have, err := checksec.Analyze(e)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		normalize(have)
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("%s:\n\thave %#v\n\twant %#v\n", tt.name, have, tt.want)
+		}
+	}
+}
+
+// normalize makes empty lists nil so that reports compare equal.
+func normalize(r *checksec.Report) {
+	for _, l := range []*[]string{&r.Fortified, &r.RPath, &r.RunPath} {
+		if len(*l) == 0 {
+			*l = nil
+		}
+	}
+}
+
+func TestAnalyzeSynthetic(t *testing.T) {
+	bo := binary.LittleEndian
+	b := elf.NewBuilder(elf.ELFCLASS64, bo, elf.EM_AARCH64, elf.ET_EXEC)
+
+	dynstr := elf.NewStringTable()
+	rpath := dynstr.Add("/opt/lib:/usr/local/lib")
+	strtab := b.AddStringTable(".dynstr", dynstr)
+	var dynamic []byte
+	for _, de := range [][2]uint64{
+		{uint64(elf.DT_RPATH), uint64(rpath)},
+		{uint64(elf.DT_TEXTREL), 0},
+		{uint64(elf.DT_NULL), 0},
+	} {
+		dynamic = bo.AppendUint64(bo.AppendUint64(dynamic, de[0]), de[1])
+	}
+	dyn := b.AddSection(".dynamic", elf.SectionHeader{Type: elf.SHT_DYNAMIC, Flags: elf.SHF_ALLOC | elf.SHF_WRITE, Addr: 0x10000, Addralign: 8, EntSize: 16}, dynamic)
+	b.SectionHeader(dyn).Link = strtab
+
+	var desc []byte
+	desc = bo.AppendUint32(desc, uint32(elf.GNU_PROPERTY_AARCH64_FEATURE_1_AND))
+	desc = bo.AppendUint32(desc, 4)
+	desc = bo.AppendUint32(desc, uint32(elf.GNU_PROPERTY_AARCH64_FEATURE_1_BTI|elf.GNU_PROPERTY_AARCH64_FEATURE_1_PAC))
+	desc = bo.AppendUint32(desc, 0)
+	note := b.AddNotes(".note.gnu.property", &elf.Note{Name: elf.ELF_NOTE_GNU, Type: elf.NT_GNU_PROPERTY_TYPE_0, Desc: desc})
+	b.SectionHeader(note).Addr = 0x20000
+
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_LOAD, Flags: elf.PF_R | elf.PF_W, Align: 0x10000}, dyn)
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_GNU_RELRO, Flags: elf.PF_R, Align: 1}, dyn)
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_GNU_STACK, Flags: elf.PF_R | elf.PF_W | elf.PF_X, Align: 16})
+
+	raw, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, err := checksec.Analyze(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	normalize(have)
+
+	want := &checksec.Report{
+		RELRO:   checksec.PartialRELRO,
+		PIE:     checksec.NoPIE,
+		RPath:   []string{"/opt/lib", "/usr/local/lib"},
+		BTI:     true,
+		PAC:     true,
+		TextRel: true,
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("have %#v\n\twant %#v\n", have, want)
+	}
+}
+
+func TestAnalyzeStatic(t *testing.T) {
+	bo := binary.LittleEndian
+	// The code at 0x401000 calls __stack_chk_fail at 0x401010 and
+	// __printf_chk at 0x401030 but not __memcpy_chk at 0x401020.
+	x86 := make([]byte, 0x40)
+	x86[0] = 0xe8
+	bo.PutUint32(x86[1:], 0x401010-0x401005)
+	x86[5] = 0xe8
+	bo.PutUint32(x86[6:], 0x401030-0x40100a)
+	arm64 := make([]byte, 0x40)
+	bo.PutUint32(arm64[0:], 0x94000000|0x10/4)
+	bo.PutUint32(arm64[4:], 0x94000000|(0x30-4)/4)
+
+	for _, tt := range []struct {
+		machine elf.Machine
+		code    []byte
+	}{
+		{elf.EM_X86_64, x86},
+		{elf.EM_AARCH64, arm64},
+	} {
+		b := elf.NewBuilder(elf.ELFCLASS64, bo, tt.machine, elf.ET_EXEC)
+		text := b.AddSection(".text", elf.SectionHeader{Type: elf.SHT_PROGBITS, Flags: elf.SHF_ALLOC | elf.SHF_EXECINSTR, Addr: 0x401000, Addralign: 16}, tt.code)
+		for name, addr := range map[string]uint64{"__stack_chk_fail": 0x401010, "__memcpy_chk": 0x401020, "__printf_chk": 0x401030} {
+			b.AddSymbol(elf.Symbol{Name: name, Value: addr, Size: 16, Type: elf.STT_FUNC, Bind: elf.STB_GLOBAL, Section: text})
+		}
+		b.AddSegment(elf.ProgramHeader{Type: elf.PT_LOAD, Flags: elf.PF_R | elf.PF_X, Align: 0x1000}, text)
+
+		raw, err := b.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		e, err := elf.New(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := checksec.Analyze(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !r.Canary || !reflect.DeepEqual(r.Fortified, []string{"__printf_chk"}) {
+			t.Errorf("%v: have canary %v and fortified %v", tt.machine, r.Canary, r.Fortified)
+		}
+	}
+}
+
+func TestStrings(t *testing.T) {
+	for _, tt := range []struct {
+		have fmt.Stringer
+		want string
+	}{
+		{checksec.FullRELRO, "Full RELRO"},
+		{checksec.PartialRELRO, "Partial RELRO"},
+		{checksec.NoRELRO, "No RELRO"},
+		{checksec.PIEEnabled, "PIE enabled"},
+		{checksec.DSO, "DSO"},
+		{checksec.PIE(9), "PIE(9)"},
+	} {
+		if s := tt.have.String(); s != tt.want {
+			t.Errorf("have %q, want %q", s, tt.want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+func (d *dumper) dynamicSection() error {
+	e := d.e
+	var off uint64
+	if sgs := e.SegmentsByType(elf.PT_DYNAMIC); len(sgs) > 0 {
+		off = sgs[0].Header.Offset
+	} else if ss := e.SectionsByType(elf.SHT_DYNAMIC); len(ss) > 0 {
+		off = ss[0].Header.Offset
+	} else {
+		d.printf("\nThere is no dynamic section in this file.\n")
+		return nil
+	}
+
+	des, err := e.DynamicEntries()
+	if err != nil {
+		return err
+	}
+	// DynamicEntries leaves out the terminating DT_NULL, which readelf
+	// lists.
+	des = append(des, &elf.DynamicEntry{Tag: elf.DT_NULL})
+
+	var interp string
+	if sgs := e.SegmentsByType(elf.PT_INTERP); len(sgs) > 0 {
+		if data, err := sgs[0].Data(); err == nil {
+			interp = cString(data)
+		}
+	}
+
+	// The strings of the entries are resolved with DynStrings, which
+	// returns them in the order of the entries.
+	strs := map[elf.DynTag][]string{}
+	for _, tag := range []elf.DynTag{elf.DT_NEEDED, elf.DT_SONAME, elf.DT_RPATH, elf.DT_RUNPATH} {
+		if strs[tag], err = e.DynStrings(tag); err != nil {
+			return err
+		}
+	}
+
+	n := len(des)
+	d.printf("\nDynamic section at offset %#x contains %d %s:\n", off, n, plural(n, "entry", "entries"))
+	d.printf("  Tag        Type                         Name/Value\n")
+	for _, de := range des {
+		name := dynTagName(de.Tag, e.Header.Machine)
+		if d.is32() {
+			d.printf(" 0x%08x (%s)%*s", uint32(de.Tag), name, 27-len(name), " ")
+		} else {
+			d.printf(" 0x%016x (%s)%*s", uint64(de.Tag), name, 19-len(name), " ")
+		}
+		var str string
+		if ss := strs[de.Tag]; len(ss) > 0 {
+			str, strs[de.Tag] = ss[0], ss[1:]
+		}
+		d.printf("%s\n", d.dynValue(de, str, interp))
+	}
+
+	return nil
+}
+
+// dynValue formats the value of de, whose string is name for the entries
+// referencing the dynamic string table.
+func (d *dumper) dynValue(de *elf.DynamicEntry, name, interp string) string {
+	v := de.Value
+	switch de.Tag {
+	case elf.DT_NEEDED, elf.DT_SONAME, elf.DT_RPATH, elf.DT_RUNPATH:
+		switch de.Tag {
+		case elf.DT_NEEDED:
+			if name == interp {
+				return fmt.Sprintf("Shared library: [%s] program interpreter", name)
+			}
+			return fmt.Sprintf("Shared library: [%s]", name)
+		case elf.DT_SONAME:
+			return fmt.Sprintf("Library soname: [%s]", name)
+		case elf.DT_RPATH:
+			return fmt.Sprintf("Library rpath: [%s]", name)
+		default:
+			return fmt.Sprintf("Library runpath: [%s]", name)
+		}
+	case elf.DT_PLTRELSZ, elf.DT_RELASZ, elf.DT_STRSZ, elf.DT_RELSZ, elf.DT_RELAENT, elf.DT_SYMENT,
+		elf.DT_RELENT, elf.DT_PLTPADSZ, elf.DT_MOVEENT, elf.DT_MOVESZ, elf.DT_PREINIT_ARRAYSZ,
+		elf.DT_INIT_ARRAYSZ, elf.DT_FINI_ARRAYSZ, elf.DT_GNU_CONFLICTSZ, elf.DT_GNU_LIBLISTSZ,
+		elf.DT_RELRSZ, elf.DT_RELRENT, elf.DT_SYMINSZ, elf.DT_SYMINENT:
+		return fmt.Sprintf("%d (bytes)", v)
+	case elf.DT_VERDEFNUM, elf.DT_VERNEEDNUM, elf.DT_RELACOUNT, elf.DT_RELCOUNT:
+		return fmt.Sprint(v)
+	case elf.DT_PLTREL:
+		return dynTagName(elf.DynTag(v), d.e.Header.Machine)
+	case elf.DT_FLAGS:
+		if v == 0 {
+			return "None"
+		}
+		return strings.Join(flagNames(v, dynFlagNames), " ")
+	case elf.DT_FLAGS_1:
+		var b strings.Builder
+		b.WriteString("Flags:")
+		for _, s := range flagNames(v, dynFlag1Names) {
+			b.WriteString(" " + s)
+		}
+		return b.String()
+	}
+
+	return fmt.Sprintf("%#x", v)
+}
+
+var dynFlagNames = []string{"ORIGIN", "SYMBOLIC", "TEXTREL", "BIND_NOW", "STATIC_TLS"}
+
+var dynFlag1Names = []string{
+	"NOW", "GLOBAL", "GROUP", "NODELETE", "LOADFLTR", "INITFIRST", "NOOPEN", "ORIGIN",
+	"DIRECT", "TRANS", "INTERPOSE", "NODEFLIB", "NODUMP", "CONFALT", "ENDFILTEE", "DISPRELDNE",
+	"DISPRELPND", "NODIRECT", "IGNMULDEF", "NOKSYMS", "NOHDR", "EDITED", "NORELOC", "SYMINTPOSE",
+	"GLOBAUDIT", "SINGLETON", "STUB", "PIE", "KMOD", "WEAKFILTER", "NOCOMMON",
+}
+
+// flagNames returns the names of the bits set in v, names giving the bits
+// from the lowest up. Bits without a name are listed once as unknown.
+func flagNames(v uint64, names []string) []string {
+	var ss []string
+	unknown := false
+	for i := 0; i < 64; i++ {
+		switch {
+		case v&(1<<i) == 0:
+		case i < len(names):
+			ss = append(ss, names[i])
+		default:
+			unknown = true
+		}
+	}
+	if unknown {
+		ss = append(ss, "unknown")
+	}
+
+	return ss
+}
+
+var dynTagNames = map[elf.DynTag]string{
+	elf.DT_NULL:            "NULL",
+	elf.DT_NEEDED:          "NEEDED",
+	elf.DT_PLTRELSZ:        "PLTRELSZ",
+	elf.DT_PLTGOT:          "PLTGOT",
+	elf.DT_HASH:            "HASH",
+	elf.DT_STRTAB:          "STRTAB",
+	elf.DT_SYMTAB:          "SYMTAB",
+	elf.DT_RELA:            "RELA",
+	elf.DT_RELASZ:          "RELASZ",
+	elf.DT_RELAENT:         "RELAENT",
+	elf.DT_STRSZ:           "STRSZ",
+	elf.DT_SYMENT:          "SYMENT",
+	elf.DT_INIT:            "INIT",
+	elf.DT_FINI:            "FINI",
+	elf.DT_SONAME:          "SONAME",
+	elf.DT_RPATH:           "RPATH",
+	elf.DT_SYMBOLIC:        "SYMBOLIC",
+	elf.DT_REL:             "REL",
+	elf.DT_RELSZ:           "RELSZ",
+	elf.DT_RELENT:          "RELENT",
+	elf.DT_PLTREL:          "PLTREL",
+	elf.DT_DEBUG:           "DEBUG",
+	elf.DT_TEXTREL:         "TEXTREL",
+	elf.DT_JMPREL:          "JMPREL",
+	elf.DT_BIND_NOW:        "BIND_NOW",
+	elf.DT_INIT_ARRAY:      "INIT_ARRAY",
+	elf.DT_FINI_ARRAY:      "FINI_ARRAY",
+	elf.DT_INIT_ARRAYSZ:    "INIT_ARRAYSZ",
+	elf.DT_FINI_ARRAYSZ:    "FINI_ARRAYSZ",
+	elf.DT_RUNPATH:         "RUNPATH",
+	elf.DT_FLAGS:           "FLAGS",
+	elf.DT_PREINIT_ARRAY:   "PREINIT_ARRAY",
+	elf.DT_PREINIT_ARRAYSZ: "PREINIT_ARRAYSZ",
+	elf.DT_SYMTAB_SHNDX:    "SYMTAB_SHNDX",
+	elf.DT_RELRSZ:          "RELRSZ",
+	elf.DT_RELR:            "RELR",
+	elf.DT_RELRENT:         "RELRENT",
+	elf.DT_GNU_PRELINKED:   "GNU_PRELINKED",
+	elf.DT_GNU_CONFLICTSZ:  "GNU_CONFLICTSZ",
+	elf.DT_GNU_LIBLISTSZ:   "GNU_LIBLISTSZ",
+	elf.DT_CHECKSUM:        "CHECKSUM",
+	elf.DT_PLTPADSZ:        "PLTPADSZ",
+	elf.DT_MOVEENT:         "MOVEENT",
+	elf.DT_MOVESZ:          "MOVESZ",
+	elf.DT_FEATURE_1:       "FEATURE_1",
+	elf.DT_POSFLAG_1:       "POSFLAG_1",
+	elf.DT_SYMINSZ:         "SYMINSZ",
+	elf.DT_SYMINENT:        "SYMINENT",
+	elf.DT_GNU_HASH:        "GNU_HASH",
+	elf.DT_TLSDESC_PLT:     "TLSDESC_PLT",
+	elf.DT_TLSDESC_GOT:     "TLSDESC_GOT",
+	elf.DT_GNU_CONFLICT:    "GNU_CONFLICT",
+	elf.DT_GNU_LIBLIST:     "GNU_LIBLIST",
+	elf.DT_CONFIG:          "CONFIG",
+	elf.DT_DEPAUDIT:        "DEPAUDIT",
+	elf.DT_AUDIT:           "AUDIT",
+	elf.DT_PLTPAD:          "PLTPAD",
+	elf.DT_MOVETAB:         "MOVETAB",
+	elf.DT_SYMINFO:         "SYMINFO",
+	elf.DT_VERSYM:          "VERSYM",
+	elf.DT_RELACOUNT:       "RELACOUNT",
+	elf.DT_RELCOUNT:        "RELCOUNT",
+	elf.DT_FLAGS_1:         "FLAGS_1",
+	elf.DT_VERDEF:          "VERDEF",
+	elf.DT_VERDEFNUM:       "VERDEFNUM",
+	elf.DT_VERNEED:         "VERNEED",
+	elf.DT_VERNEEDNUM:      "VERNEEDNUM",
+	elf.DT_AUXILIARY:       "AUXILIARY",
+	elf.DT_FILTER:          "FILTER",
+}
+
+var machineDynTags = map[elf.Machine]map[elf.DynTag]string{
+	elf.EM_AARCH64: {0x70000001: "AARCH64_BTI_PLT", 0x70000003: "AARCH64_PAC_PLT", 0x70000005: "AARCH64_VARIANT_PCS"},
+	elf.EM_RISCV:   {0x70000001: "RISCV_VARIANT_CC"},
+	elf.EM_PPC64:   {0x70000000: "PPC64_GLINK", 0x70000001: "PPC64_OPD", 0x70000002: "PPC64_OPDSZ", 0x70000003: "PPC64_OPT"},
+}
+
+func dynTagName(t elf.DynTag, m elf.Machine) string {
+	if s, ok := dynTagNames[t]; ok {
+		return s
+	}
+	if s, ok := machineDynTags[m][t]; ok {
+		return s
+	}
+
+	switch {
+	case t >= elf.DT_LOPROC && t <= elf.DT_HIPROC:
+		return fmt.Sprintf("Processor Specific: %x", uint64(t))
+	case t >= elf.DT_LOOS && t <= elf.DT_HIOS:
+		return fmt.Sprintf("Operating System specific: %x", uint64(t))
+	}
+	return fmt.Sprintf("<unknown>: %x", uint64(t))
+}
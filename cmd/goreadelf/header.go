@@ -0,0 +1,369 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+func (d *dumper) fileHeader() error {
+	h := d.e.Header
+
+	d.printf("ELF Header:\n  Magic:   ")
+	for _, b := range h.Ident {
+		d.printf("%02x ", b)
+	}
+	d.printf("\n")
+
+	field := func(name, format string, args ...any) {
+		d.printf("  %-35s%s\n", name+":", fmt.Sprintf(format, args...))
+	}
+	field("Class", "%s", className(h.Class()))
+	field("Data", "%s", dataName(h.Data()))
+	version := ""
+	switch h.Ident[elf.EI_VERSION] {
+	case elf.EV_NONE:
+	case elf.EV_CURRENT:
+		version = " (current)"
+	default:
+		version = " <unknown>"
+	}
+	field("Version", "%d%s", h.Ident[elf.EI_VERSION], version)
+	field("OS/ABI", "%s", osABIName(h.OSABI(), h.Machine))
+	field("ABI Version", "%d", h.ABIVersion())
+	field("Type", "%s", d.typeName())
+	field("Machine", "%s", machineName(h.Machine))
+	field("Version", "%#x", h.Version)
+	field("Entry point address", "%#x", h.Entry)
+	field("Start of program headers", "%d (bytes into file)", h.Phoff)
+	field("Start of section headers", "%d (bytes into file)", h.Shoff)
+	field("Flags", "%#x%s", h.Flags, machineFlags(h.Machine, h.Flags))
+	field("Size of this header", "%d (bytes)", h.Ehsize)
+	field("Size of program headers", "%d (bytes)", h.Phentsize)
+
+	// The real counts of files with extended numbering are stored in
+	// section 0 and shown next to the header fields.
+	phnum := fmt.Sprint(h.Phnum)
+	if h.Phnum == elf.PN_XNUM {
+		phnum += fmt.Sprintf(" (%d)", len(d.e.Segments))
+	}
+	field("Number of program headers", "%s", phnum)
+	field("Size of section headers", "%d (bytes)", h.Shentsize)
+	shnum := fmt.Sprint(h.Shnum)
+	if h.Shnum == 0 && len(d.e.Sections) > 0 {
+		shnum += fmt.Sprintf(" (%d)", len(d.e.Sections))
+	}
+	field("Number of section headers", "%s", shnum)
+	shstrndx := fmt.Sprint(h.Shstrndx)
+	if h.Shstrndx == elf.SHN_XINDEX && len(d.e.Sections) > 0 {
+		shstrndx += fmt.Sprintf(" (%d)", d.e.Sections[0].Header.Link)
+	}
+	field("Section header string table index", "%s", shstrndx)
+
+	return nil
+}
+
+func className(c elf.Class) string {
+	switch c {
+	case elf.ELFCLASSNONE:
+		return "none"
+	case elf.ELFCLASS32:
+		return "ELF32"
+	case elf.ELFCLASS64:
+		return "ELF64"
+	}
+	return fmt.Sprintf("<unknown: %x>", c)
+}
+
+func dataName(data elf.Data) string {
+	switch data {
+	case elf.ELFDATANONE:
+		return "none"
+	case elf.ELFDATA2LSB:
+		return "2's complement, little endian"
+	case elf.ELFDATA2MSB:
+		return "2's complement, big endian"
+	}
+	return fmt.Sprintf("<unknown: %x>", data)
+}
+
+var osABINames = map[elf.OSABI]string{
+	elf.ELFOSABI_NONE:     "UNIX - System V",
+	elf.ELFOSABI_HPUX:     "UNIX - HP-UX",
+	elf.ELFOSABI_NETBSD:   "UNIX - NetBSD",
+	elf.ELFOSABI_GNU:      "UNIX - GNU",
+	elf.ELFOSABI_SOLARIS:  "UNIX - Solaris",
+	elf.ELFOSABI_AIX:      "UNIX - AIX",
+	elf.ELFOSABI_IRIX:     "UNIX - IRIX",
+	elf.ELFOSABI_FREEBSD:  "UNIX - FreeBSD",
+	elf.ELFOSABI_TRU64:    "UNIX - TRU64",
+	elf.ELFOSABI_MODESTO:  "Novell - Modesto",
+	elf.ELFOSABI_OPENBSD:  "UNIX - OpenBSD",
+	elf.ELFOSABI_OPENVMS:  "VMS - OpenVMS",
+	elf.ELFOSABI_NSK:      "HP - Non-Stop Kernel",
+	elf.ELFOSABI_AROS:     "AROS",
+	elf.ELFOSABI_FENIXOS:  "FenixOS",
+	elf.ELFOSABI_CLOUDABI: "Nuxi CloudABI",
+	elf.ELFOSABI_OPENVOS:  "Stratus Technologies OpenVOS",
+}
+
+func osABIName(abi elf.OSABI, m elf.Machine) string {
+	if s, ok := osABINames[abi]; ok {
+		return s
+	}
+	// Values from 64 up are specific to the machine.
+	switch {
+	case abi == elf.ELFOSABI_ARM && m == elf.EM_ARM:
+		return "ARM"
+	case abi == elf.ELFOSABI_STANDALONE:
+		return "Standalone App"
+	}
+	return fmt.Sprintf("<unknown: %x>", abi)
+}
+
+func (d *dumper) typeName() string {
+	switch t := d.e.Header.Type; {
+	case t == elf.ET_NONE:
+		return "NONE (None)"
+	case t == elf.ET_REL:
+		return "REL (Relocatable file)"
+	case t == elf.ET_EXEC:
+		return "EXEC (Executable file)"
+	case t == elf.ET_DYN:
+		if flags1, err := d.e.DynFlags1(); err == nil && flags1&elf.DF_1_PIE != 0 {
+			return "DYN (Position-Independent Executable file)"
+		}
+		return "DYN (Shared object file)"
+	case t == elf.ET_CORE:
+		return "CORE (Core file)"
+	case t >= elf.ET_LPROC:
+		return fmt.Sprintf("Processor Specific: (%x)", uint16(t))
+	case t >= elf.ET_LOOS:
+		return fmt.Sprintf("OS Specific: (%x)", uint16(t))
+	default:
+		return fmt.Sprintf("<unknown>: %x", uint16(t))
+	}
+}
+
+// machineNames are the descriptions readelf gives machines.
+var machineNames = map[elf.Machine]string{
+	elf.EM_NONE:         "None",
+	elf.EM_M32:          "WE32100",
+	elf.EM_SPARC:        "Sparc",
+	elf.EM_386:          "Intel 80386",
+	elf.EM_68K:          "MC68000",
+	elf.EM_88K:          "MC88000",
+	elf.EM_IAMCU:        "Intel MCU",
+	elf.EM_860:          "Intel 80860",
+	elf.EM_MIPS:         "MIPS R3000",
+	elf.EM_S370:         "IBM System/370",
+	elf.EM_MIPS_RS3_LE:  "MIPS R4000 big-endian",
+	elf.EM_PARISC:       "HPPA",
+	elf.EM_SPARC32PLUS:  "Sparc v8+",
+	elf.EM_960:          "Intel 80960",
+	elf.EM_PPC:          "PowerPC",
+	elf.EM_PPC64:        "PowerPC64",
+	elf.EM_S390:         "IBM S/390",
+	elf.EM_SPU:          "SPU",
+	elf.EM_ARM:          "ARM",
+	elf.EM_ALPHA_STD:    "Digital Alpha (old)",
+	elf.EM_SH:           "Renesas / SuperH SH",
+	elf.EM_SPARCV9:      "Sparc v9",
+	elf.EM_IA_64:        "Intel IA-64",
+	elf.EM_X86_64:       "Advanced Micro Devices X86-64",
+	elf.EM_VAX:          "Digital VAX",
+	elf.EM_AVR:          "Atmel AVR 8-bit microcontroller",
+	elf.EM_OPENRISC:     "OpenRISC 1000",
+	elf.EM_XTENSA:       "Tensilica Xtensa Processor",
+	elf.EM_MSP430:       "Texas Instruments msp430 microcontroller",
+	elf.EM_ALTERA_NIOS2: "Altera Nios II",
+	elf.EM_QDSP6:        "QUALCOMM DSP6 Processor",
+	elf.EM_AARCH64:      "AArch64",
+	elf.EM_TILEPRO:      "Tilera TILEPro multicore architecture family",
+	elf.EM_CUDA:         "NVIDIA CUDA architecture",
+	elf.EM_TILEGX:       "Tilera TILE-Gx multicore architecture family",
+	elf.EM_AMDGPU:       "AMD GPU",
+	elf.EM_RISCV:        "RISC-V",
+	elf.EM_BPF:          "Linux BPF",
+	elf.EM_CSKY:         "C-SKY",
+	elf.EM_LOONGARCH:    "LoongArch",
+}
+
+func machineName(m elf.Machine) string {
+	if s, ok := machineNames[m]; ok {
+		return s
+	}
+	return fmt.Sprintf("<unknown>: 0x%x", uint16(m))
+}
+
+// flagName names a bit of e_flags.
+type flagName struct {
+	bit  uint32
+	name string
+}
+
+// machineFlags decodes the e_flags of the machines whose flags readelf
+// describes most often, in the words readelf uses. The flags of other
+// machines are only shown in hex.
+func machineFlags(m elf.Machine, flags uint32) string {
+	if flags == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	add := func(s string) { b.WriteString(", " + s) }
+	addBits := func(names []flagName) {
+		for _, f := range names {
+			if flags&f.bit != 0 {
+				add(f.name)
+			}
+		}
+	}
+	switch m {
+	case elf.EM_ARM:
+		armFlags(flags, add)
+	case elf.EM_LOONGARCH:
+		switch flags & 7 {
+		case 1:
+			add("SOFT-FLOAT")
+		case 2:
+			add("SINGLE-FLOAT")
+		case 3:
+			add("DOUBLE-FLOAT")
+		}
+		switch flags & 0xc0 {
+		case 0x00:
+			add("OBJ-v0")
+		case 0x40:
+			add("OBJ-v1")
+		}
+	case elf.EM_MIPS:
+		mipsFlags(flags, add, addBits)
+	case elf.EM_PPC:
+		addBits([]flagName{{0x80000000, "emb"}, {0x00010000, "relocatable"}, {0x00008000, "relocatable-lib"}})
+	case elf.EM_PPC64:
+		if abi := flags & 3; abi != 0 {
+			add(fmt.Sprintf("abiv%d", abi))
+		}
+	case elf.EM_RISCV:
+		addBits([]flagName{{0x1, "RVC"}, {0x8, "RVE"}, {0x10, "TSO"}})
+		add([]string{"soft-float ABI", "single-float ABI", "double-float ABI", "quad-float ABI"}[(flags&6)>>1])
+	case elf.EM_S390:
+		addBits([]flagName{{0x1, "highgprs"}})
+	}
+	return b.String()
+}
+
+// armFlags decodes the flags of ARM files, which depend on the version of
+// the EABI in their top byte.
+func armFlags(flags uint32, add func(string)) {
+	eabi := flags & 0xff000000
+	flags &^= 0xff000000
+	for _, f := range []flagName{{0x01, "relocatable executable"}, {0x20, "position independent"}} {
+		if flags&f.bit != 0 {
+			add(f.name)
+			flags &^= f.bit
+		}
+	}
+
+	var names []flagName
+	switch eabi {
+	case 0:
+		add("GNU EABI")
+		names = []flagName{
+			{0x004, "interworking enabled"}, {0x008, "uses APCS/26"}, {0x010, "uses APCS/float"},
+			{0x040, "8 bit structure alignment"}, {0x080, "uses new ABI"}, {0x100, "uses old ABI"},
+			{0x200, "software FP"}, {0x400, "VFP"}, {0x800, "Maverick FP"},
+		}
+	case 0x01000000:
+		add("Version1 EABI")
+		names = []flagName{{0x04, "sorted symbol tables"}}
+	case 0x02000000:
+		add("Version2 EABI")
+		names = []flagName{
+			{0x04, "sorted symbol tables"}, {0x08, "dynamic symbols use segment index"},
+			{0x10, "mapping symbols precede others"},
+		}
+	case 0x03000000:
+		add("Version3 EABI")
+	case 0x04000000:
+		add("Version4 EABI")
+		names = []flagName{{0x00800000, "BE8"}, {0x00400000, "LE8"}}
+	case 0x05000000:
+		add("Version5 EABI")
+		names = []flagName{{0x00800000, "BE8"}, {0x00400000, "LE8"}, {0x200, "soft-float ABI"}, {0x400, "hard-float ABI"}}
+	default:
+		add("<unrecognized EABI>")
+	}
+
+	// Flags are listed from the lowest bit up, with any left over noted
+	// once at the end.
+	unknown := false
+	for flags != 0 {
+		bit := flags & -flags
+		flags &^= bit
+		known := false
+		for _, f := range names {
+			if f.bit == bit {
+				add(f.name)
+				known = true
+			}
+		}
+		unknown = unknown || !known
+	}
+	if unknown {
+		add("<unknown>")
+	}
+}
+
+var mipsMachs = map[uint32]string{
+	0x00810000: "3900", 0x00820000: "4010", 0x00830000: "4100", 0x00840000: "allegrex",
+	0x00850000: "4650", 0x00870000: "4120", 0x00880000: "4111", 0x00890000: "interaptiv-mr2",
+	0x008a0000: "sb1", 0x008b0000: "octeon", 0x008c0000: "xlr", 0x008d0000: "octeon2",
+	0x008e0000: "octeon3", 0x00910000: "5400", 0x00920000: "5900", 0x00980000: "5500",
+	0x00990000: "9000", 0x00a00000: "loongson-2e", 0x00a10000: "loongson-2f",
+	0x00a20000: "gs464", 0x00a30000: "gs464e", 0x00a40000: "gs264e",
+}
+
+var mipsArchs = map[uint32]string{
+	0x00000000: "mips1", 0x10000000: "mips2", 0x20000000: "mips3", 0x30000000: "mips4",
+	0x40000000: "mips5", 0x50000000: "mips32", 0x60000000: "mips64", 0x70000000: "mips32r2",
+	0x80000000: "mips64r2", 0x90000000: "mips32r6", 0xa0000000: "mips64r6",
+}
+
+// mipsFlags decodes the flags of MIPS files: the options, the CPU, the
+// ABI, the extensions and the ISA.
+func mipsFlags(flags uint32, add func(string), addBits func([]flagName)) {
+	addBits([]flagName{
+		{0x001, "noreorder"}, {0x002, "pic"}, {0x004, "cpic"}, {0x010, "ugen_reserved"},
+		{0x020, "abi2"}, {0x080, "odk first"}, {0x100, "32bitmode"}, {0x400, "nan2008"},
+		{0x200, "fp64"},
+	})
+	if mach := flags & 0x00ff0000; mach != 0 {
+		name, ok := mipsMachs[mach]
+		if !ok {
+			name = "unknown CPU"
+		}
+		add(name)
+	}
+	switch flags & 0x0000f000 {
+	case 0:
+	case 0x1000:
+		add("o32")
+	case 0x2000:
+		add("o64")
+	case 0x3000:
+		add("eabi32")
+	case 0x4000:
+		add("eabi64")
+	default:
+		add("unknown ABI")
+	}
+	addBits([]flagName{{0x08000000, "mdmx"}, {0x04000000, "mips16"}, {0x02000000, "micromips"}})
+	name, ok := mipsArchs[flags&0xf0000000]
+	if !ok {
+		name = "unknown ISA"
+	}
+	add(name)
+}
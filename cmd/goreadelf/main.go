@@ -0,0 +1,237 @@
+// Goreadelf displays information about ELF files like readelf of GNU
+// binutils, without depending on it. It reproduces the output of
+//
+//	readelf -h -l -S -s -d -r -n -V --wide
+//
+// for the options it supports, so that scripts parsing that output can use
+// it instead.
+//
+// Usage:
+//
+//	goreadelf [options] elffile...
+//
+// The options are:
+//
+//	-a, --all              equivalent to -h -l -S -s -d -r -n -V
+//	-e, --headers          equivalent to -h -l -S
+//	-h, --file-header      display the ELF file header
+//	-l, --program-headers  display the program headers (also --segments)
+//	-S, --section-headers  display the section headers (also --sections)
+//	-s, --syms             display the symbol tables (also --symbols)
+//	    --dyn-syms         display the dynamic symbol table
+//	-d, --dynamic          display the dynamic section
+//	-r, --relocs           display the relocations
+//	-n, --notes            display the notes
+//	-V, --version-info     display the symbol versioning sections
+//	-W, --wide             accepted for compatibility, output is always wide
+//	    --json             write the file as JSON instead, one document per
+//	                       file, in the schema of elf.File.MarshalJSON
+//
+// Short options may be combined, as in -lW. Goreadelf exits with status 1
+// when a file cannot be parsed, printing the error of the parser.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+// options are the displays selected on the command line.
+type options struct {
+	header, segments, sections, symbols bool
+	dynamic, relocs, notes, versions    bool
+	dynSyms, json                       bool
+}
+
+func (o *options) any() bool {
+	return o.header || o.segments || o.sections || o.symbols || o.dynSyms || o.dynamic || o.relocs || o.notes || o.versions || o.json
+}
+
+const usage = "Usage: goreadelf <option(s)> elf-file(s)\n" +
+	" Display information about the contents of ELF format files\n" +
+	" Options are:\n" +
+	"  -a --all               Equivalent to: -h -l -S -s -d -r -n -V\n" +
+	"  -e --headers           Equivalent to: -h -l -S\n" +
+	"  -h --file-header       Display the ELF file header\n" +
+	"  -l --program-headers   Display the program headers\n" +
+	"     --segments          An alias for --program-headers\n" +
+	"  -S --section-headers   Display the sections' header\n" +
+	"     --sections          An alias for --section-headers\n" +
+	"  -s --syms              Display the symbol table\n" +
+	"     --symbols           An alias for --syms\n" +
+	"     --dyn-syms          Display the dynamic symbol table\n" +
+	"  -d --dynamic           Display the dynamic section (if present)\n" +
+	"  -r --relocs            Display the relocations (if present)\n" +
+	"  -n --notes             Display the core notes (if present)\n" +
+	"  -V --version-info      Display the version sections (if present)\n" +
+	"  -W --wide              Allow output width to exceed 80 characters\n" +
+	"     --json              Display the file as JSON instead\n"
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs goreadelf with the arguments args and returns its exit status.
+func run(args []string, stdout, stderr io.Writer) int {
+	opts, files, err := parseArgs(args)
+	if err != nil {
+		fmt.Fprintf(stderr, "goreadelf: %s\n%s", err, usage)
+		return 1
+	}
+	if !opts.any() || len(files) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 1
+	}
+
+	w := bufio.NewWriter(stdout)
+	defer w.Flush()
+
+	status := 0
+	for _, name := range files {
+		if len(files) > 1 && !opts.json {
+			fmt.Fprintf(w, "\nFile: %s\n", name)
+		}
+		if err := dumpFile(w, name, opts); err != nil {
+			w.Flush()
+			fmt.Fprintf(stderr, "goreadelf: %s: %s\n", name, err)
+			status = 1
+		}
+	}
+
+	return status
+}
+
+func parseArgs(args []string) (*options, []string, error) {
+	opts := &options{}
+	var files []string
+
+	long := map[string]func(){
+		"all": func() {
+			opts.header, opts.segments, opts.sections, opts.symbols = true, true, true, true
+			opts.dynamic, opts.relocs, opts.notes, opts.versions = true, true, true, true
+		},
+		"headers":         func() { opts.header, opts.segments, opts.sections = true, true, true },
+		"file-header":     func() { opts.header = true },
+		"program-headers": func() { opts.segments = true },
+		"segments":        func() { opts.segments = true },
+		"section-headers": func() { opts.sections = true },
+		"sections":        func() { opts.sections = true },
+		"syms":            func() { opts.symbols = true },
+		"symbols":         func() { opts.symbols = true },
+		"dyn-syms":        func() { opts.dynSyms = true },
+		"dynamic":         func() { opts.dynamic = true },
+		"relocs":          func() { opts.relocs = true },
+		"notes":           func() { opts.notes = true },
+		"version-info":    func() { opts.versions = true },
+		"wide":            func() {},
+		"json":            func() { opts.json = true },
+	}
+	short := map[rune]string{
+		'a': "all", 'e': "headers", 'h': "file-header", 'l': "program-headers",
+		'S': "section-headers", 's': "syms", 'd': "dynamic", 'r': "relocs",
+		'n': "notes", 'V': "version-info", 'W': "wide",
+	}
+
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return opts, append(files, args[i+1:]...), nil
+		case strings.HasPrefix(arg, "--"):
+			set, ok := long[arg[2:]]
+			if !ok {
+				return nil, nil, fmt.Errorf("unrecognized option '%s'", arg)
+			}
+			set()
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			for _, c := range arg[1:] {
+				name, ok := short[c]
+				if !ok {
+					return nil, nil, fmt.Errorf("invalid option -- '%c'", c)
+				}
+				long[name]()
+			}
+		default:
+			files = append(files, arg)
+		}
+	}
+
+	return opts, files, nil
+}
+
+// dumpFile parses the file name and writes the displays of opts. Like
+// readelf, the displays written before a malformed part of the file is
+// reached are kept.
+func dumpFile(w io.Writer, name string, opts *options) error {
+	raw, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	e, err := elf.New(raw)
+	if err != nil {
+		return err
+	}
+
+	if opts.json {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(e)
+	}
+
+	d := &dumper{w: w, e: e, opts: opts}
+	steps := []struct {
+		on   bool
+		dump func() error
+	}{
+		{opts.header, d.fileHeader},
+		{opts.sections, d.sectionHeaders},
+		{opts.segments, d.programHeaders},
+		{opts.dynamic, d.dynamicSection},
+		{opts.relocs, d.relocations},
+		{opts.symbols || opts.dynSyms, d.symbolTables},
+		{opts.versions, d.versionSections},
+		{opts.notes, d.noteSections},
+	}
+	for _, s := range steps {
+		if !s.on {
+			continue
+		}
+		if err := s.dump(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dumper writes the displays of a file.
+type dumper struct {
+	w    io.Writer
+	e    *elf.File
+	opts *options
+
+	// versions caches the symbol versioning sections, which the symbol
+	// tables and relocations print the versions of dynamic symbols from.
+	versions *versionInfo
+}
+
+func (d *dumper) printf(format string, args ...any) {
+	fmt.Fprintf(d.w, format, args...)
+}
+
+func (d *dumper) is32() bool {
+	return d.e.Header.Class() == elf.ELFCLASS32
+}
+
+// plural returns one if n is 1 and many otherwise.
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+// The golden files were written by GNU readelf 2.40 with
+//
+//	readelf -h -l -S -s -d -r -n -V --wide <file>
+func TestGolden(t *testing.T) {
+	for _, name := range []string{
+		"hello_linux_amd64",
+		"libsample_linux_amd64.so",
+		"libsample_linux_amd64.o",
+		"libversioned_linux_amd64.so",
+		"gohello_linux_amd64",
+	} {
+		want, err := os.ReadFile(filepath.Join("../../testdata/readelf", name+".txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var stdout, stderr bytes.Buffer
+		args := []string{"-h", "-l", "-S", "-s", "-d", "-r", "-n", "-V", "--wide", "../../testdata/" + name}
+		if status := run(args, &stdout, &stderr); status != 0 {
+			t.Fatalf("%s: exit status %d: %s", name, status, stderr.String())
+		}
+
+		have := strings.Split(stdout.String(), "\n")
+		lines := strings.Split(string(want), "\n")
+		for i := 0; i < len(have) || i < len(lines); i++ {
+			var h, w string
+			if i < len(have) {
+				h = have[i]
+			}
+			if i < len(lines) {
+				w = lines[i]
+			}
+			if h != w {
+				t.Errorf("%s: line %d:\n\thave %q\n\twant %q\n", name, i+1, h, w)
+				break
+			}
+		}
+	}
+}
+
+func TestCombinedOptions(t *testing.T) {
+	var long, short, stderr bytes.Buffer
+	name := "../../testdata/libversioned_linux_amd64.so"
+	if status := run([]string{"--dynamic", "--version-info", "--wide", name}, &long, &stderr); status != 0 {
+		t.Fatalf("exit status %d: %s", status, stderr.String())
+	}
+	if status := run([]string{"-dVW", name}, &short, &stderr); status != 0 {
+		t.Fatalf("exit status %d: %s", status, stderr.String())
+	}
+	if long.String() != short.String() {
+		t.Errorf("-dVW differs from --dynamic --version-info --wide:\n%s\n%s", short.String(), long.String())
+	}
+	if !strings.Contains(short.String(), "Library soname: [libversioned.so.1]") {
+		t.Errorf("soname missing from:\n%s", short.String())
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	files := []string{"../../testdata/hello_linux_amd64", "../../testdata/libsample_linux_amd64.o"}
+	if status := run(append([]string{"--json"}, files...), &stdout, &stderr); status != 0 {
+		t.Fatalf("exit status %d: %s", status, stderr.String())
+	}
+
+	// Every file is written as a document of its own.
+	var types []string
+	dec := json.NewDecoder(&stdout)
+	for dec.More() {
+		var f struct{ Header struct{ Type string } }
+		if err := dec.Decode(&f); err != nil {
+			t.Fatal(err)
+		}
+		types = append(types, f.Header.Type)
+	}
+	if want := []string{"ET_DYN", "ET_REL"}; !reflect.DeepEqual(types, want) {
+		t.Errorf("have %v, want %v", types, want)
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	raw, err := os.ReadFile("../../testdata/hello_linux_amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(t.TempDir(), "truncated")
+	if err := os.WriteFile(name, raw[:100], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-h", name}, "goreadelf: " + name + ": invalid number of section headers"},
+		{[]string{"-h", "../../testdata/hello.c"}, "goreadelf: ../../testdata/hello.c: "},
+		{[]string{"-x", name}, "goreadelf: invalid option -- 'x'"},
+		{[]string{"--bogus", name}, "goreadelf: unrecognized option '--bogus'"},
+		{[]string{name}, "Usage: goreadelf"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if status := run(tt.args, &stdout, &stderr); status != 1 {
+			t.Errorf("%v: exit status %d, want 1", tt.args, status)
+		}
+		if !strings.HasPrefix(stderr.String(), tt.want) {
+			t.Errorf("%v:\n\thave %q\n\twant prefix %q\n", tt.args, stderr.String(), tt.want)
+		}
+	}
+}
+
+func TestMachineFlags(t *testing.T) {
+	// The flags of the Go toolchain's binaries for these machines, as
+	// readelf 2.40 describes them.
+	for _, tt := range []struct {
+		m     elf.Machine
+		flags uint32
+		want  string
+	}{
+		{elf.EM_X86_64, 0, ""},
+		{elf.EM_ARM, 0x5000400, ", Version5 EABI, hard-float ABI"},
+		{elf.EM_ARM, 0x5000002, ", Version5 EABI, <unknown>"},
+		{elf.EM_MIPS, 0x50001004, ", cpic, o32, mips32"},
+		{elf.EM_MIPS, 0x20000004, ", cpic, mips3"},
+		{elf.EM_PPC64, 0x2, ", abiv2"},
+		{elf.EM_RISCV, 0x4, ", double-float ABI"},
+		{elf.EM_S390, 0x1, ", highgprs"},
+		{elf.EM_LOONGARCH, 0x43, ", DOUBLE-FLOAT, OBJ-v1"},
+	} {
+		if s := machineFlags(tt.m, tt.flags); s != tt.want {
+			t.Errorf("%v %#x:\n\thave %q\n\twant %q\n", tt.m, tt.flags, s, tt.want)
+		}
+	}
+}
+
+func TestCoreSymbols(t *testing.T) {
+	f, err := os.Open("../../testdata/core_linux_amd64.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(t.TempDir(), "core")
+	if err := os.WriteFile(name, raw, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Core files have no section headers, and so no symbol tables.
+	for _, opt := range []string{"-s", "--dyn-syms"} {
+		var stdout, stderr bytes.Buffer
+		if status := run([]string{opt, name}, &stdout, &stderr); status != 0 {
+			t.Fatalf("%s: exit status %d: %s", opt, status, stderr.String())
+		}
+		if want := "\nDynamic symbol information is not available for displaying symbols.\n"; stdout.String() != want {
+			t.Errorf("%s:\n\thave %q\n\twant %q\n", opt, stdout.String(), want)
+		}
+	}
+}
+
+func TestSymbolSize(t *testing.T) {
+	for size, want := range map[uint64]string{0: "    0", 99999: "99999", 100000: "0x186a0", 33554432: "0x2000000"} {
+		if s := symbolSize(size); s != want {
+			t.Errorf("%d:\n\thave %q\n\twant %q\n", size, s, want)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+func (d *dumper) noteSections() error {
+	e := d.e
+	if len(e.Sections) == 0 {
+		for _, sg := range e.SegmentsByType(elf.PT_NOTE) {
+			ns, err := e.SegmentNotes(sg)
+			if err != nil {
+				return err
+			}
+			d.printf("\nDisplaying notes found at file offset 0x%08x with length 0x%08x:\n", sg.Header.Offset, sg.Header.Filesz)
+			d.notes(ns)
+		}
+		return nil
+	}
+
+	for _, s := range e.SectionsByType(elf.SHT_NOTE) {
+		ns, err := e.SectionNotes(s)
+		if err != nil {
+			return err
+		}
+		d.printf("\nDisplaying notes found in: %s\n", s.Name)
+		d.notes(ns)
+	}
+
+	return nil
+}
+
+func (d *dumper) notes(ns []*elf.Note) {
+	d.printf("  Owner                Data size \tDescription\n")
+	for _, n := range ns {
+		d.printf("  %-20s 0x%08x\t%s\t", n.Name, len(n.Desc), d.noteTypeName(n))
+		d.printf("%s\n", d.noteDescription(n))
+	}
+}
+
+var gnuNoteTypes = map[elf.NoteType]string{
+	elf.NT_GNU_ABI_TAG:         "NT_GNU_ABI_TAG (ABI version tag)",
+	elf.NT_GNU_HWCAP:           "NT_GNU_HWCAP (DSO-supplied software HWCAP info)",
+	elf.NT_GNU_BUILD_ID:        "NT_GNU_BUILD_ID (unique build ID bitstring)",
+	elf.NT_GNU_GOLD_VERSION:    "NT_GNU_GOLD_VERSION (gold version)",
+	elf.NT_GNU_PROPERTY_TYPE_0: "NT_GNU_PROPERTY_TYPE_0",
+	0x100:                      "NT_GNU_BUILD_ATTRIBUTE_OPEN",
+	0x101:                      "NT_GNU_BUILD_ATTRIBUTE_FUNC",
+}
+
+var coreNoteTypes = map[elf.NoteType]string{
+	elf.NT_PRSTATUS:   "NT_PRSTATUS (prstatus structure)",
+	elf.NT_FPREGSET:   "NT_FPREGSET (floating point registers)",
+	elf.NT_PRPSINFO:   "NT_PRPSINFO (prpsinfo structure)",
+	elf.NT_TASKSTRUCT: "NT_TASKSTRUCT (task structure)",
+	elf.NT_AUXV:       "NT_AUXV (auxiliary vector)",
+	elf.NT_X86_XSTATE: "NT_X86_XSTATE (x86 XSAVE extended state)",
+	elf.NT_ARM_VFP:    "NT_ARM_VFP (arm VFP registers)",
+	elf.NT_SIGINFO:    "NT_SIGINFO (siginfo_t data)",
+	elf.NT_FILE:       "NT_FILE (mapped files)",
+	elf.NT_PRXFPREG:   "NT_PRXFPREG (user_xfpregs structure)",
+}
+
+func (d *dumper) noteTypeName(n *elf.Note) string {
+	var names map[elf.NoteType]string
+	switch {
+	case n.Name == elf.ELF_NOTE_GNU:
+		names = gnuNoteTypes
+	case n.Name == elf.ELF_NOTE_GO && n.Type == elf.NT_GO_BUILDID:
+		return "GO BUILDID"
+	case n.Name == elf.ELF_NOTE_FDO && n.Type == elf.NT_FDO_PACKAGING_METADATA:
+		return "FDO_PACKAGING_METADATA"
+	case n.Name == "stapsdt" && n.Type == 3:
+		return "NT_STAPSDT (SystemTap probe descriptors)"
+	case d.e.Header.Type == elf.ET_CORE:
+		names = coreNoteTypes
+	default:
+		names = map[elf.NoteType]string{1: "NT_VERSION (version)", 2: "NT_ARCH (architecture)"}
+	}
+
+	if s, ok := names[n.Type]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown note type: (0x%08x)", uint32(n.Type))
+}
+
+// noteDescription decodes the descriptor of n the way readelf does for the
+// notes it knows, and dumps it in hex otherwise.
+func (d *dumper) noteDescription(n *elf.Note) string {
+	bo := d.e.Endianness
+	switch n.Name {
+	case elf.ELF_NOTE_GNU:
+		switch n.Type {
+		case elf.NT_GNU_BUILD_ID:
+			return "    Build ID: " + hex.EncodeToString(n.Desc)
+		case elf.NT_GNU_ABI_TAG:
+			if len(n.Desc) < 16 {
+				return "    <corrupt GNU_ABI_TAG>"
+			}
+			os := "Unknown"
+			switch elf.GNUABIOS(bo.Uint32(n.Desc)) {
+			case elf.ELF_NOTE_OS_LINUX:
+				os = "Linux"
+			case elf.ELF_NOTE_OS_GNU:
+				os = "Hurd"
+			case elf.ELF_NOTE_OS_SOLARIS2:
+				os = "Solaris"
+			case elf.ELF_NOTE_OS_FREEBSD:
+				os = "FreeBSD"
+			}
+			return fmt.Sprintf("    OS: %s, ABI: %d.%d.%d", os, bo.Uint32(n.Desc[4:]), bo.Uint32(n.Desc[8:]), bo.Uint32(n.Desc[12:]))
+		case elf.NT_GNU_GOLD_VERSION:
+			return "    Version: " + string(n.Desc)
+		case elf.NT_GNU_PROPERTY_TYPE_0:
+			return "      Properties: " + d.gnuProperties(n.Desc)
+		}
+	case elf.ELF_NOTE_FDO:
+		if n.Type == elf.NT_FDO_PACKAGING_METADATA {
+			return "    Packaging Metadata: " + cString(n.Desc)
+		}
+	case elf.ELF_NOTE_CORE:
+		if n.Type == elf.NT_FILE {
+			return d.fileNote(n.Desc)
+		}
+		return ""
+	}
+
+	if len(n.Desc) == 0 {
+		return ""
+	}
+	return "   description data: " + hexBytes(n.Desc)
+}
+
+// hexBytes formats b as hex bytes each followed by a space.
+func hexBytes(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		fmt.Fprintf(&sb, "%02x ", c)
+	}
+	return sb.String()
+}
+
+// gnuProperties formats the properties of a NT_GNU_PROPERTY_TYPE_0 note.
+func (d *dumper) gnuProperties(desc []byte) string {
+	bo := d.e.Endianness
+	align := 8
+	if d.is32() {
+		align = 4
+	}
+
+	var ss []string
+	for off := 0; off+8 <= len(desc); {
+		typ := elf.GNUPropertyType(bo.Uint32(desc[off:]))
+		size := int(bo.Uint32(desc[off+4:]))
+		off += 8
+		if size > len(desc)-off {
+			ss = append(ss, fmt.Sprintf("<corrupt type (%#x) datasz: %#x>", uint32(typ), size))
+			break
+		}
+		data := desc[off : off+size]
+		ss = append(ss, d.gnuProperty(typ, data))
+		off += (size + align - 1) &^ (align - 1)
+	}
+
+	return strings.Join(ss, ", ")
+}
+
+var (
+	x86Feature1Names     = []string{"IBT", "SHSTK", "LAM_U48", "LAM_U57"}
+	x86Feature2Names     = []string{"x86", "x87", "MMX", "XMM", "YMM", "ZMM", "FXSR", "XSAVE", "XSAVEOPT", "XSAVEC", "TMM", "MASK"}
+	x86ISANames          = []string{"x86-64-baseline", "x86-64-v2", "x86-64-v3", "x86-64-v4"}
+	aarch64Feature1Names = []string{"BTI", "PAC", "GCS"}
+)
+
+func (d *dumper) gnuProperty(typ elf.GNUPropertyType, data []byte) string {
+	bo := d.e.Endianness
+	bits := func(prefix string, names []string) string {
+		if len(data) != 4 {
+			return fmt.Sprintf("%s<corrupt length: %#x> ", prefix, len(data))
+		}
+		v := bo.Uint32(data)
+		if v == 0 {
+			return prefix + "<None>"
+		}
+		var ss []string
+		for i := 0; i < 32; i++ {
+			switch {
+			case v&(1<<i) == 0:
+			case i < len(names):
+				ss = append(ss, names[i])
+			default:
+				ss = append(ss, fmt.Sprintf("<unknown: %x>", uint32(1)<<i))
+			}
+		}
+		return prefix + strings.Join(ss, ", ")
+	}
+
+	m := d.e.Header.Machine
+	switch {
+	case m == elf.EM_X86_64 || m == elf.EM_386:
+		switch typ {
+		case elf.GNU_PROPERTY_X86_FEATURE_1_AND:
+			return bits("x86 feature: ", x86Feature1Names)
+		case elf.GNU_PROPERTY_X86_ISA_1_NEEDED:
+			return bits("x86 ISA needed: ", x86ISANames)
+		case elf.GNU_PROPERTY_X86_ISA_1_USED:
+			return bits("x86 ISA used: ", x86ISANames)
+		case elf.GNU_PROPERTY_X86_FEATURE_2_NEEDED:
+			return bits("x86 feature needed: ", x86Feature2Names)
+		case elf.GNU_PROPERTY_X86_FEATURE_2_USED:
+			return bits("x86 feature used: ", x86Feature2Names)
+		}
+	case m == elf.EM_AARCH64 && typ == elf.GNU_PROPERTY_AARCH64_FEATURE_1_AND:
+		return bits("AArch64 feature: ", aarch64Feature1Names)
+	}
+
+	switch typ {
+	case elf.GNU_PROPERTY_STACK_SIZE:
+		if len(data) == 4 {
+			return fmt.Sprintf("stack size: %#x", bo.Uint32(data))
+		}
+		if len(data) == 8 && !d.is32() {
+			return fmt.Sprintf("stack size: %#x", bo.Uint64(data))
+		}
+		return fmt.Sprintf("stack size: <corrupt length: %#x> ", len(data))
+	case elf.GNU_PROPERTY_NO_COPY_ON_PROTECTED:
+		if len(data) != 0 {
+			return fmt.Sprintf("no copy on protected <corrupt length: %#x> ", len(data))
+		}
+		return "no copy on protected "
+	case elf.GNU_PROPERTY_1_NEEDED:
+		return bits("1_needed: ", []string{"indirect external access"})
+	}
+
+	kind := "unknown"
+	switch {
+	case typ >= 0xe0000000:
+		kind = "application-specific"
+	case typ >= elf.GNU_PROPERTY_LOPROC:
+		kind = "processor-specific"
+	}
+	return fmt.Sprintf("<%s type %#x data: %s>", kind, uint32(typ), hexBytes(data))
+}
+
+// fileNote formats the files mapped into a process, from the descriptor of
+// a NT_FILE note.
+func (d *dumper) fileNote(desc []byte) string {
+	bo := d.e.Endianness
+	size := 8
+	word := func(off int) uint64 { return bo.Uint64(desc[off:]) }
+	if d.is32() {
+		size = 4
+		word = func(off int) uint64 { return uint64(bo.Uint32(desc[off:])) }
+	}
+	if len(desc) < 2*size {
+		return "    <malformed NT_FILE note>"
+	}
+	count, pageSize := word(0), word(size)
+	if count > uint64((len(desc)-2*size)/(3*size)) {
+		return "    <malformed NT_FILE note>"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "    Page size: %d\n", pageSize)
+	fmt.Fprintf(&b, "    %*s%*s%*s", 2+2*size, "Start", 4+2*size, "End", 3+2*size, "Page Offset")
+	names := desc[2*size+int(count)*3*size:]
+	for i := 0; i < int(count); i++ {
+		off := 2*size + i*3*size
+		name := names
+		if j := bytes.IndexByte(names, 0); j >= 0 {
+			name, names = names[:j], names[j+1:]
+		} else {
+			names = nil
+		}
+		fmt.Fprintf(&b, "\n    0x%0*x  0x%0*x  0x%0*x\n        %s",
+			2*size, word(off), 2*size, word(off+size), 2*size, word(off+2*size), name)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+func (d *dumper) sectionHeaders() error {
+	e := d.e
+	if len(e.Sections) == 0 {
+		d.printf("\nThere are no sections in this file.\n")
+		return nil
+	}
+	if !d.opts.header {
+		n := len(e.Sections)
+		d.printf("There %s %d section %s, starting at offset %#x:\n",
+			plural(n, "is", "are"), n, plural(n, "header", "headers"), e.Header.Shoff)
+	}
+
+	d.printf("\nSection %s:\n", plural(len(e.Sections), "Header", "Headers"))
+	if d.is32() {
+		d.printf("  [Nr] Name              Type            Addr     Off    Size   ES Flg Lk Inf Al\n")
+	} else {
+		d.printf("  [Nr] Name              Type            Address          Off    Size   ES Flg Lk Inf Al\n")
+	}
+	for i, s := range e.Sections {
+		h := &s.Header
+		addr := fmt.Sprintf("%016x", h.Addr)
+		if d.is32() {
+			addr = fmt.Sprintf("%08x", h.Addr)
+		}
+		d.printf("  [%2d] %-17s %-15s %s %06x %06x %02x %3s %2d %3d %2d\n",
+			i, s.Name, d.sectionTypeName(h.Type), addr, h.Offset, h.Size, h.EntSize,
+			d.sectionFlags(h.Flags), h.Link, h.Info, h.Addralign)
+	}
+
+	d.printf("Key to Flags:\n" +
+		"  W (write), A (alloc), X (execute), M (merge), S (strings), I (info),\n" +
+		"  L (link order), O (extra OS processing required), G (group), T (TLS),\n" +
+		"  C (compressed), x (unknown), o (OS specific), E (exclude),\n  ")
+	osabi := e.Header.OSABI()
+	if osabi == elf.ELFOSABI_GNU || osabi == elf.ELFOSABI_FREEBSD {
+		d.printf("R (retain), ")
+	}
+	if osabi == elf.ELFOSABI_NONE || osabi == elf.ELFOSABI_GNU {
+		d.printf("D (mbind), ")
+	}
+	switch e.Header.Machine {
+	case elf.EM_X86_64:
+		d.printf("l (large), ")
+	case elf.EM_ARM:
+		d.printf("y (purecode), ")
+	case elf.EM_PPC:
+		d.printf("v (VLE), ")
+	}
+	d.printf("p (processor specific)\n")
+
+	return nil
+}
+
+var sectionTypeNames = map[elf.SectionHeaderType]string{
+	elf.SHT_NULL:                   "NULL",
+	elf.SHT_PROGBITS:               "PROGBITS",
+	elf.SHT_SYMTAB:                 "SYMTAB",
+	elf.SHT_STRTAB:                 "STRTAB",
+	elf.SHT_RELA:                   "RELA",
+	elf.SHT_HASH:                   "HASH",
+	elf.SHT_DYNAMIC:                "DYNAMIC",
+	elf.SHT_NOTE:                   "NOTE",
+	elf.SHT_NOBITS:                 "NOBITS",
+	elf.SHT_REL:                    "REL",
+	elf.SHT_SHLIB:                  "SHLIB",
+	elf.SHT_DYNSYM:                 "DYNSYM",
+	elf.SHT_INIT_ARRAY:             "INIT_ARRAY",
+	elf.SHT_FINI_ARRAY:             "FINI_ARRAY",
+	elf.SHT_PREINIT_ARRAY:          "PREINIT_ARRAY",
+	elf.SHT_GROUP:                  "GROUP",
+	elf.SHT_SYMTAB_SHNDX:           "SYMTAB SECTION INDICES",
+	elf.SHT_RELR:                   "RELR",
+	elf.SHT_GNU_HASH:               "GNU_HASH",
+	elf.SHT_GNU_verdef:             "VERDEF",
+	elf.SHT_GNU_verneed:            "VERNEED",
+	elf.SHT_GNU_versym:             "VERSYM",
+	elf.SHT_GNU_ATTRIBUTES:         "GNU_ATTRIBUTES",
+	elf.SHT_GNU_LIBLIST:            "GNU_LIBLIST",
+	elf.SHT_GNU_INCREMENTAL_INPUTS: "GNU_INCREMENTAL_INPUTS",
+	0x6ffffff0:                     "VERSYM",
+	0x6ffffffc:                     "VERDEF",
+	0x7ffffffd:                     "AUXILIARY",
+	0x7fffffff:                     "FILTER",
+}
+
+// machineSectionTypes are the processor specific section types readelf
+// names.
+var machineSectionTypes = map[elf.Machine]map[elf.SectionHeaderType]string{
+	elf.EM_X86_64:  {0x70000001: "X86_64_UNWIND"},
+	elf.EM_ARM:     {0x70000001: "ARM_EXIDX", 0x70000002: "ARM_PREEMPTMAP", 0x70000003: "ARM_ATTRIBUTES", 0x70000004: "ARM_DEBUGOVERLAY", 0x70000005: "ARM_OVERLAYSECTION"},
+	elf.EM_AARCH64: {0x70000003: "AARCH64_ATTRIBUTES"},
+	elf.EM_RISCV:   {0x70000003: "RISCV_ATTRIBUTES"},
+	elf.EM_MIPS:    {0x70000006: "MIPS_REGINFO", 0x7000000d: "MIPS_OPTIONS", 0x7000001e: "MIPS_DWARF", 0x7000002a: "MIPS_ABIFLAGS"},
+}
+
+func (d *dumper) sectionTypeName(t elf.SectionHeaderType) string {
+	if s, ok := sectionTypeNames[t]; ok {
+		return s
+	}
+	if s, ok := machineSectionTypes[d.e.Header.Machine][t]; ok {
+		return s
+	}
+
+	switch {
+	case t >= elf.SHT_LOPROC && t <= elf.SHT_HIPROC:
+		return fmt.Sprintf("LOPROC+%#x", uint32(t-elf.SHT_LOPROC))
+	case t >= elf.SHT_LOOS && t <= elf.SHT_HIOS:
+		return fmt.Sprintf("LOOS+%#x", uint32(t-elf.SHT_LOOS))
+	case t >= elf.SHT_LOUSER:
+		return fmt.Sprintf("LOUSER+%#x", uint32(t-elf.SHT_LOUSER))
+	}
+	return fmt.Sprintf("%08x: <unknown>", uint32(t))
+}
+
+// sectionFlags returns the flag letters of the key readelf prints below the
+// section headers.
+func (d *dumper) sectionFlags(flags elf.SectionFlag) string {
+	letters := map[elf.SectionFlag]byte{
+		elf.SHF_WRITE:            'W',
+		elf.SHF_ALLOC:            'A',
+		elf.SHF_EXECINSTR:        'X',
+		elf.SHF_MERGE:            'M',
+		elf.SHF_STRINGS:          'S',
+		elf.SHF_INFO_LINK:        'I',
+		elf.SHF_LINK_ORDER:       'L',
+		elf.SHF_OS_NONCONFORMING: 'O',
+		elf.SHF_GROUP:            'G',
+		elf.SHF_TLS:              'T',
+		elf.SHF_COMPRESSED:       'C',
+		elf.SHF_EXCLUDE:          'E',
+	}
+	osabi := d.e.Header.OSABI()
+	if osabi == elf.ELFOSABI_GNU || osabi == elf.ELFOSABI_FREEBSD {
+		letters[elf.SHF_GNU_RETAIN] = 'R'
+	}
+	if osabi == elf.ELFOSABI_NONE || osabi == elf.ELFOSABI_GNU {
+		letters[elf.SHF_GNU_MBIND] = 'D'
+	}
+	switch d.e.Header.Machine {
+	case elf.EM_X86_64:
+		letters[0x10000000] = 'l' // SHF_X86_64_LARGE
+	case elf.EM_ARM:
+		letters[0x20000000] = 'y' // SHF_ARM_PURECODE
+	}
+
+	// The bits of the OS and processor masks without a letter are shown
+	// once, where the first of them is.
+	var b strings.Builder
+	var os, proc bool
+	for bit := elf.SectionFlag(1); bit != 0 && bit <= flags; bit <<= 1 {
+		if flags&bit == 0 {
+			continue
+		}
+		c, ok := letters[bit]
+		switch {
+		case ok:
+		case bit&elf.SHF_MASKOS != 0:
+			if os {
+				continue
+			}
+			c, os = 'o', true
+		case bit&elf.SHF_MASKPROC != 0:
+			if proc {
+				continue
+			}
+			c, proc = 'p', true
+		default:
+			c = 'x'
+		}
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+func (d *dumper) programHeaders() error {
+	e := d.e
+	if len(e.Segments) == 0 {
+		d.printf("\nThere are no program headers in this file.\n")
+		return nil
+	}
+	if !d.opts.header {
+		n := len(e.Segments)
+		d.printf("\nElf file type is %s\nEntry point %#x\n", d.typeName(), e.Header.Entry)
+		d.printf("There %s %d program %s, starting at offset %d\n",
+			plural(n, "is", "are"), n, plural(n, "header", "headers"), e.Header.Phoff)
+	}
+
+	d.printf("\nProgram Headers:\n")
+	if d.is32() {
+		d.printf("  Type           Offset   VirtAddr   PhysAddr   FileSiz MemSiz  Flg Align\n")
+	} else {
+		d.printf("  Type           Offset   VirtAddr           PhysAddr           FileSiz  MemSiz   Flg Align\n")
+	}
+	for _, sg := range e.Segments {
+		h := &sg.Header
+		if d.is32() {
+			d.printf("  %-14.14s 0x%06x 0x%08x 0x%08x 0x%05x 0x%05x %s %s\n",
+				d.segmentTypeName(h.Type), h.Offset, h.Vaddr, h.Paddr, h.Filesz, h.Memsz, h.Flags, alternateHex(h.Align))
+		} else {
+			d.printf("  %-14.14s 0x%06x 0x%016x 0x%016x 0x%06x 0x%06x %s %s\n",
+				d.segmentTypeName(h.Type), h.Offset, h.Vaddr, h.Paddr, h.Filesz, h.Memsz, h.Flags, alternateHex(h.Align))
+		}
+		if h.Type == elf.PT_INTERP {
+			data, err := sg.Data()
+			if err != nil {
+				return err
+			}
+			d.printf("      [Requesting program interpreter: %s]\n", cString(data))
+		}
+	}
+
+	if len(e.Sections) == 0 {
+		return nil
+	}
+	d.printf("\n Section to Segment mapping:\n  Segment Sections...\n")
+	for i, sg := range e.Segments {
+		d.printf("   %02d     ", i)
+		for _, s := range e.SectionsInSegment(sg) {
+			d.printf("%s ", s.Name)
+		}
+		d.printf("\n")
+	}
+
+	return nil
+}
+
+var segmentTypeNames = map[elf.ProgramHeaderType]string{
+	elf.PT_NULL:              "NULL",
+	elf.PT_LOAD:              "LOAD",
+	elf.PT_DYNAMIC:           "DYNAMIC",
+	elf.PT_INTERP:            "INTERP",
+	elf.PT_NOTE:              "NOTE",
+	elf.PT_SHLIB:             "SHLIB",
+	elf.PT_PHDR:              "PHDR",
+	elf.PT_TLS:               "TLS",
+	elf.PT_GNU_EH_FRAME:      "GNU_EH_FRAME",
+	elf.PT_GNU_STACK:         "GNU_STACK",
+	elf.PT_GNU_RELRO:         "GNU_RELRO",
+	elf.PT_GNU_PROPERTY:      "GNU_PROPERTY",
+	elf.PT_GNU_SFRAME:        "GNU_SFRAME",
+	elf.PT_OPENBSD_RANDOMIZE: "OPENBSD_RANDOMIZE",
+	elf.PT_OPENBSD_WXNEEDED:  "OPENBSD_WXNEEDED",
+	elf.PT_OPENBSD_BOOTDATA:  "OPENBSD_BOOTDATA",
+}
+
+var machineSegmentTypes = map[elf.Machine]map[elf.ProgramHeaderType]string{
+	elf.EM_ARM:     {0x70000001: "EXIDX"},
+	elf.EM_AARCH64: {0x70000002: "AARCH64_MEMTAG_MTE"},
+	elf.EM_RISCV:   {0x70000003: "RISCV_ATTRIBUTES"},
+	elf.EM_MIPS:    {0x70000000: "REGINFO", 0x70000001: "RTPROC", 0x70000002: "OPTIONS", 0x70000003: "ABIFLAGS"},
+}
+
+func (d *dumper) segmentTypeName(t elf.ProgramHeaderType) string {
+	if s, ok := segmentTypeNames[t]; ok {
+		return s
+	}
+	if s, ok := machineSegmentTypes[d.e.Header.Machine][t]; ok {
+		return s
+	}
+
+	switch {
+	case t >= elf.PT_GNU_MBIND_LO && t <= elf.PT_GNU_MBIND_HI:
+		return fmt.Sprintf("GNU_MBIND+%#x", uint32(t-elf.PT_GNU_MBIND_LO))
+	case t >= elf.PT_LOPROC && t <= elf.PT_HIPROC:
+		return fmt.Sprintf("LOPROC+%#x", uint32(t-elf.PT_LOPROC))
+	case t >= elf.PT_LOOS && t <= elf.PT_HIOS:
+		return fmt.Sprintf("LOOS+%#x", uint32(t-elf.PT_LOOS))
+	}
+	return fmt.Sprintf("<unknown>: %x", uint32(t))
+}
+
+// alternateHex formats v like C's %#x, which leaves the prefix out for zero.
+func alternateHex(v uint64) string {
+	if v == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%#x", v)
+}
+
+// cString returns b up to its first NUL byte.
+func cString(b []byte) string {
+	if i := strings.IndexByte(string(b), 0); i >= 0 {
+		return string(b[:i])
+	}
+	return string(b)
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+func (d *dumper) symbolTables() error {
+	e := d.e
+	// Files without section headers, such as core files, have no symbol
+	// tables to show.
+	if len(e.Sections) == 0 {
+		d.printf("\nDynamic symbol information is not available for displaying symbols.\n")
+		return nil
+	}
+
+	for _, s := range e.Sections {
+		var syms []*elf.Symbol
+		var err error
+		switch s.Header.Type {
+		case elf.SHT_SYMTAB:
+			if !d.opts.symbols {
+				continue
+			}
+			syms, err = e.Symbols()
+		case elf.SHT_DYNSYM:
+			syms, err = e.DynamicSymbols()
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		dynamic := s.Header.Type == elf.SHT_DYNSYM
+
+		n := len(syms)
+		d.printf("\nSymbol table '%s' contains %d %s:\n", s.Name, n, plural(n, "entry", "entries"))
+		if d.is32() {
+			d.printf("   Num:    Value  Size Type    Bind   Vis      Ndx Name\n")
+		} else {
+			d.printf("   Num:    Value          Size Type    Bind   Vis      Ndx Name\n")
+		}
+		for i, sym := range syms {
+			value := fmt.Sprintf("%016x", sym.Value)
+			if d.is32() {
+				value = fmt.Sprintf("%08x", sym.Value)
+			}
+			d.printf("%6d: %s %5s %-7s %-6s %-7s %4s %s",
+				i, value, symbolSize(sym.Size), d.symbolTypeName(sym.Type), d.symbolBindName(sym.Bind),
+				symbolVisibilityName(sym.Visibility), sectionIndexName(sym.Section), d.symbolName(sym))
+			if dynamic {
+				ver, err := d.symbolVersion(i, sym)
+				if err != nil {
+					return err
+				}
+				switch {
+				case ver.name == "":
+				case ver.needed:
+					d.printf("@%s (%d)", ver.name, ver.index)
+				case ver.hidden:
+					d.printf("@%s", ver.name)
+				default:
+					d.printf("@@%s", ver.name)
+				}
+			}
+			d.printf("\n")
+		}
+	}
+
+	return nil
+}
+
+// symbolSize formats the size of a symbol as readelf does, in decimal up to
+// 99999 and in hex beyond.
+func symbolSize(size uint64) string {
+	if size <= 99999 {
+		return fmt.Sprintf("%5d", size)
+	}
+	return fmt.Sprintf("%#x", size)
+}
+
+// symbolName returns the name readelf shows for sym, which for unnamed
+// section symbols is the name of their section.
+func (d *dumper) symbolName(sym *elf.Symbol) string {
+	if sym.Type == elf.STT_SECTION && sym.Name == "" {
+		if s := d.e.SectionAt(sym.Section); s != nil {
+			return s.Name
+		}
+	}
+	return sym.Name
+}
+
+func (d *dumper) symbolTypeName(t elf.SymbolType) string {
+	switch t {
+	case elf.STT_NOTYPE:
+		return "NOTYPE"
+	case elf.STT_OBJECT:
+		return "OBJECT"
+	case elf.STT_FUNC:
+		return "FUNC"
+	case elf.STT_SECTION:
+		return "SECTION"
+	case elf.STT_FILE:
+		return "FILE"
+	case elf.STT_COMMON:
+		return "COMMON"
+	case elf.STT_TLS:
+		return "TLS"
+	}
+
+	osabi := d.e.Header.OSABI()
+	switch {
+	case t == elf.STT_GNU_IFUNC && (osabi == elf.ELFOSABI_NONE || osabi == elf.ELFOSABI_GNU || osabi == elf.ELFOSABI_FREEBSD):
+		return "IFUNC"
+	case t >= elf.STT_LOOS && t <= elf.STT_HIOS:
+		return fmt.Sprintf("<OS specific>: %d", t)
+	case t >= elf.STT_LOPROC && t <= elf.STT_HIPROC:
+		return fmt.Sprintf("<processor specific>: %d", t)
+	}
+	return fmt.Sprintf("<unknown>: %d", t)
+}
+
+func (d *dumper) symbolBindName(b elf.SymbolBind) string {
+	switch b {
+	case elf.STB_LOCAL:
+		return "LOCAL"
+	case elf.STB_GLOBAL:
+		return "GLOBAL"
+	case elf.STB_WEAK:
+		return "WEAK"
+	}
+
+	osabi := d.e.Header.OSABI()
+	switch {
+	case b == elf.STB_GNU_UNIQUE && (osabi == elf.ELFOSABI_NONE || osabi == elf.ELFOSABI_GNU):
+		return "UNIQUE"
+	case b >= elf.STB_LOOS && b <= elf.STB_HIOS:
+		return fmt.Sprintf("<OS specific>: %d", b)
+	case b >= elf.STB_LOPROC && b <= elf.STB_HIPROC:
+		return fmt.Sprintf("<processor specific>: %d", b)
+	}
+	return fmt.Sprintf("<unknown>: %d", b)
+}
+
+func symbolVisibilityName(v elf.SymbolVisibility) string {
+	switch v {
+	case elf.STV_DEFAULT:
+		return "DEFAULT"
+	case elf.STV_INTERNAL:
+		return "INTERNAL"
+	case elf.STV_HIDDEN:
+		return "HIDDEN"
+	case elf.STV_PROTECTED:
+		return "PROTECTED"
+	}
+	return fmt.Sprintf("<unknown>: %d", v)
+}
+
+func sectionIndexName(n uint32) string {
+	switch {
+	case n == elf.SHN_UNDEF:
+		return "UND"
+	case n == elf.SHN_ABS:
+		return "ABS"
+	case n == elf.SHN_COMMON:
+		return "COM"
+	case n >= elf.SHN_LOPROC && n <= elf.SHN_HIPROC:
+		return fmt.Sprintf("PRC[0x%04x]", n)
+	case n >= elf.SHN_LOOS && n <= elf.SHN_HIOS:
+		return fmt.Sprintf("OS [0x%04x]", n)
+	case n >= elf.SHN_LORESERVE && n <= elf.SHN_HIRESERVE:
+		return fmt.Sprintf("RSV[0x%04x]", n)
+	}
+	return fmt.Sprintf("%3d", n)
+}
+
+func (d *dumper) relocations() error {
+	e := d.e
+	rts, err := e.RelocationTables()
+	if err != nil {
+		return err
+	}
+	// Like readelf, leave out empty sections such as the .rela.plt of Go
+	// binaries.
+	found := false
+	for _, rt := range rts {
+		s := rt.Section
+		if s.Header.Size == 0 {
+			continue
+		}
+		found = true
+		if s.Header.Type == elf.SHT_RELR {
+			d.relrRelocations(rt)
+			continue
+		}
+
+		rela := s.Header.Type == elf.SHT_RELA
+		n := len(rt.Relocations)
+		d.printf("\nRelocation section '%s' at offset %#x contains %d %s:\n",
+			s.Name, s.Header.Offset, n, plural(n, "entry", "entries"))
+
+		var syms []*elf.Symbol
+		dynamic := false
+		if st := rt.SymbolTable; st != nil {
+			switch st.Header.Type {
+			case elf.SHT_DYNSYM:
+				syms, err = e.DynamicSymbols()
+				dynamic = true
+			case elf.SHT_SYMTAB:
+				syms, err = e.Symbols()
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		header := "    Offset             Info             Type               Symbol's Value  Symbol's Name"
+		if d.is32() {
+			header = " Offset     Info    Type                Sym. Value  Symbol's Name"
+		}
+		if rela {
+			header += " + Addend"
+		}
+		d.printf("%s\n", header)
+
+		for _, r := range rt.Relocations {
+			typ := relocationTypeName(r.Type)
+			if d.is32() {
+				d.printf("%08x  %08x %-22s", r.Offset, r.Symbol<<8|r.Type.Value, typ)
+			} else {
+				d.printf("%016x  %016x %-22s", r.Offset, uint64(r.Symbol)<<32|uint64(r.Type.Value), typ)
+			}
+
+			if r.Symbol == 0 || int(r.Symbol) >= len(syms) {
+				if rela {
+					d.printf("%*s%s", d.pick(12, 20), "", signedHex(r.Addend))
+				}
+				d.printf("\n")
+				continue
+			}
+
+			sym := syms[r.Symbol]
+			if d.is32() {
+				d.printf(" %08x   ", sym.Value)
+			} else {
+				d.printf(" %016x ", sym.Value)
+			}
+			d.printf("%s", d.relocationSymbolName(sym))
+			if dynamic {
+				ver, err := d.symbolVersion(int(r.Symbol), sym)
+				if err != nil {
+					return err
+				}
+				switch {
+				case ver.name == "":
+				case ver.needed || ver.hidden:
+					d.printf("@%s", ver.name)
+				default:
+					d.printf("@@%s", ver.name)
+				}
+			}
+			if rela {
+				if r.Addend < 0 {
+					d.printf(" - %x", uint64(-r.Addend))
+				} else {
+					d.printf(" + %x", r.Addend)
+				}
+			}
+			d.printf("\n")
+		}
+	}
+	if !found {
+		d.printf("\nThere are no relocations in this file.\n")
+	}
+
+	return nil
+}
+
+// relrRelocations prints a SHT_RELR section as readelf does: the number of
+// words it holds, then the offsets they encode.
+func (d *dumper) relrRelocations(rt *elf.RelocationTable) {
+	s := rt.Section
+	entsize := s.Header.EntSize
+	if entsize == 0 {
+		entsize = uint64(d.pick(4, 8))
+	}
+	n := s.Header.Size / entsize
+	d.printf("\nRelocation section '%s' at offset %#x contains %d %s:\n",
+		s.Name, s.Header.Offset, n, plural(int(n), "entry", "entries"))
+	m := len(rt.Relocations)
+	d.printf("  %d %s\n", m, plural(m, "offset", "offsets"))
+	for _, r := range rt.Relocations {
+		d.printf("%0*x\n", d.pick(8, 16), r.Offset)
+	}
+}
+
+// relocationSymbolName returns the name shown for the symbol of a
+// relocation, the name of the section for section symbols.
+func (d *dumper) relocationSymbolName(sym *elf.Symbol) string {
+	if sym.Type != elf.STT_SECTION {
+		return sym.Name
+	}
+	switch sym.Section {
+	case elf.SHN_UNDEF:
+		return "UND"
+	case elf.SHN_ABS:
+		return "ABS"
+	case elf.SHN_COMMON:
+		return "COM"
+	}
+	if s := d.e.SectionAt(sym.Section); s != nil {
+		return s.Name
+	}
+	return sym.Name
+}
+
+// relocationTypeName returns the name of t, spelling the jump slot
+// relocations the way readelf does.
+func relocationTypeName(t elf.RelocationType) string {
+	switch {
+	case t.Machine == elf.EM_X86_64 && t.Value == uint32(elf.R_X86_64_JMP_SLOT):
+		return "R_X86_64_JUMP_SLOT"
+	case (t.Machine == elf.EM_386 || t.Machine == elf.EM_486) && t.Value == uint32(elf.R_386_JMP_SLOT):
+		return "R_386_JUMP_SLOT"
+	}
+
+	s := t.String()
+	if s == fmt.Sprint(t.Value) {
+		return fmt.Sprintf("unrecognized: %x", t.Value)
+	}
+	return s
+}
+
+func signedHex(v int64) string {
+	if v < 0 {
+		return fmt.Sprintf("-%x", uint64(-v))
+	}
+	return fmt.Sprintf("%x", v)
+}
+
+// pick returns n32 for 32-bit files and n64 for 64-bit ones.
+func (d *dumper) pick(n32, n64 int) int {
+	if d.is32() {
+		return n32
+	}
+	return n64
+}
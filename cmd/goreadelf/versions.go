@@ -0,0 +1,331 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+// versionInfo holds what the versions of dynamic symbols are looked up in.
+type versionInfo struct {
+	// indices are the entries of .gnu.version, nil if there is none.
+	indices []uint16
+	defs    map[uint16]*elf.VersionDefinition
+	needs   map[uint16]string
+}
+
+// symbolVersion is the version printed after the name of a dynamic symbol.
+type symbolVersion struct {
+	name   string
+	index  uint16
+	needed bool
+	hidden bool
+}
+
+func (d *dumper) versionInfo() (*versionInfo, error) {
+	if d.versions != nil {
+		return d.versions, nil
+	}
+
+	e := d.e
+	vi := &versionInfo{defs: map[uint16]*elf.VersionDefinition{}, needs: map[uint16]string{}}
+	if ss := e.SectionsByType(elf.SHT_GNU_versym); len(ss) > 0 {
+		data, err := ss[0].Data()
+		if err != nil {
+			return nil, err
+		}
+		vi.indices = make([]uint16, len(data)/2)
+		for i := range vi.indices {
+			vi.indices[i] = e.Endianness.Uint16(data[i*2:])
+		}
+	}
+
+	defs, err := e.VersionDefinitions()
+	if err != nil {
+		return nil, err
+	}
+	for _, def := range defs {
+		vi.defs[def.Index] = def
+	}
+	reqs, err := e.RequiredVersions()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reqs {
+		for _, v := range r.Versions {
+			vi.needs[v.Index&^elf.VERSYM_HIDDEN] = v.Name
+		}
+	}
+
+	d.versions = vi
+	return vi, nil
+}
+
+// symbolVersion returns the version of the i-th dynamic symbol sym the way
+// readelf picks it: definitions are looked up for defined symbols and
+// needed versions for the others, and the base version is not shown.
+func (d *dumper) symbolVersion(i int, sym *elf.Symbol) (symbolVersion, error) {
+	vi, err := d.versionInfo()
+	if err != nil || i >= len(vi.indices) {
+		return symbolVersion{}, err
+	}
+
+	v := vi.indices[i]
+	ndx := v &^ elf.VERSYM_HIDDEN
+	if v == elf.VER_NDX_LOCAL {
+		return symbolVersion{}, nil
+	}
+	if sym.Section != elf.SHN_UNDEF && v != elf.VERSYM_HIDDEN|elf.VER_NDX_GLOBAL {
+		if def, ok := vi.defs[ndx]; ok {
+			if def.Index == 1 && def.Flags == elf.VER_FLG_BASE {
+				return symbolVersion{}, nil
+			}
+			// The symbols that the linker defines for the versions
+			// themselves are shown without them.
+			if sym.Section == elf.SHN_ABS && sym.Value == 0 && sym.Name == def.Name {
+				return symbolVersion{}, nil
+			}
+			return symbolVersion{name: def.Name, index: ndx, hidden: v&elf.VERSYM_HIDDEN != 0}, nil
+		}
+	}
+	if name, ok := vi.needs[ndx]; ok {
+		return symbolVersion{name: name, index: ndx, needed: true}, nil
+	}
+
+	return symbolVersion{}, nil
+}
+
+func (d *dumper) versionSections() error {
+	found := false
+	for _, s := range d.e.Sections {
+		var err error
+		switch s.Header.Type {
+		case elf.SHT_GNU_verdef:
+			err = d.versionDefinitions(s)
+		case elf.SHT_GNU_verneed:
+			err = d.versionNeeds(s)
+		case elf.SHT_GNU_versym:
+			err = d.versionSymbols(s)
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		found = true
+	}
+	if !found {
+		d.printf("\nNo version information found in this file.\n")
+	}
+
+	return nil
+}
+
+// versionSectionHeader prints the lines every version section starts with.
+func (d *dumper) versionSectionHeader(kind string, s *elf.Section, n int) {
+	h := &s.Header
+	d.printf("\nVersion %s section '%s' contains %d %s:\n", kind, s.Name, n, plural(n, "entry", "entries"))
+	link := "<corrupt>"
+	if ls := d.e.SectionAt(h.Link); ls != nil {
+		link = ls.Name
+	}
+	if d.is32() {
+		d.printf(" Addr: 0x%08x", h.Addr)
+	} else {
+		d.printf(" Addr: 0x%016x", h.Addr)
+	}
+	d.printf("  Offset: 0x%08x  Link: %d (%s)\n", h.Offset, h.Link, link)
+}
+
+// linkedStrings returns the string table the version section s links to.
+func (d *dumper) linkedStrings(s *elf.Section) ([]byte, error) {
+	ls := d.e.SectionAt(s.Header.Link)
+	if ls == nil {
+		return nil, fmt.Errorf("invalid link %d of section %s", s.Header.Link, s.Name)
+	}
+	return ls.Data()
+}
+
+func (d *dumper) versionSymbols(s *elf.Section) error {
+	vi, err := d.versionInfo()
+	if err != nil {
+		return err
+	}
+	d.versionSectionHeader("symbols", s, len(vi.indices))
+
+	for i, v := range vi.indices {
+		if i%4 == 0 {
+			if i > 0 {
+				d.printf("\n")
+			}
+			d.printf("  %03x:", i)
+		}
+		switch v {
+		case elf.VER_NDX_LOCAL:
+			d.printf("   0 (*local*)    ")
+			continue
+		case elf.VER_NDX_GLOBAL:
+			d.printf("   1 (*global*)   ")
+			continue
+		}
+
+		hidden := ' '
+		if v&elf.VERSYM_HIDDEN != 0 {
+			hidden = 'h'
+		}
+		ndx := v &^ elf.VERSYM_HIDDEN
+		name, ok := vi.needs[ndx]
+		if def, isDef := vi.defs[ndx]; isDef && !ok {
+			name, ok = def.Name, true
+		}
+		if !ok {
+			name = "???"
+		}
+		cell := fmt.Sprintf("%4x%c(%s%-*s", ndx, hidden, name, 12-len(name), ")")
+		d.printf("%-18s", cell)
+	}
+	d.printf("\n")
+
+	return nil
+}
+
+func (d *dumper) versionDefinitions(s *elf.Section) error {
+	data, err := s.Data()
+	if err != nil {
+		return err
+	}
+	strs, err := d.linkedStrings(s)
+	if err != nil {
+		return err
+	}
+	bo := d.e.Endianness
+	n := int(s.Header.Info)
+	d.versionSectionHeader("definition", s, n)
+
+	off := uint64(0)
+	for i := 0; i < n; i++ {
+		if off+20 > uint64(len(data)) {
+			return fmt.Errorf("invalid version definition at offset %#x", off)
+		}
+		vd := data[off:]
+		cnt := int(bo.Uint16(vd[6:]))
+		d.printf("  %s: Rev: %d  Flags: %s  Index: %d  Cnt: %d  ",
+			versionOffset(off), bo.Uint16(vd[0:]), versionFlags(bo.Uint16(vd[2:])), bo.Uint16(vd[4:]), cnt)
+
+		aux := off + uint64(bo.Uint32(vd[12:]))
+		for j := 0; j < cnt; j++ {
+			if aux+8 > uint64(len(data)) {
+				return fmt.Errorf("invalid version definition auxiliary entry at offset %#x", aux)
+			}
+			name := cString(safeTail(strs, bo.Uint32(data[aux:])))
+			if j == 0 {
+				d.printf("Name: %s\n", name)
+			} else {
+				d.printf("  %s: Parent %d: %s\n", versionOffset(aux), j, name)
+			}
+			aux += uint64(bo.Uint32(data[aux+4:]))
+		}
+		if cnt == 0 {
+			d.printf("\n")
+		}
+
+		next := uint64(bo.Uint32(vd[16:]))
+		if next == 0 {
+			break
+		}
+		off += next
+	}
+
+	return nil
+}
+
+func (d *dumper) versionNeeds(s *elf.Section) error {
+	data, err := s.Data()
+	if err != nil {
+		return err
+	}
+	strs, err := d.linkedStrings(s)
+	if err != nil {
+		return err
+	}
+	bo := d.e.Endianness
+	n := int(s.Header.Info)
+	d.versionSectionHeader("needs", s, n)
+
+	off := uint64(0)
+	for i := 0; i < n; i++ {
+		if off+16 > uint64(len(data)) {
+			return fmt.Errorf("invalid version requirement at offset %#x", off)
+		}
+		vn := data[off:]
+		cnt := int(bo.Uint16(vn[2:]))
+		d.printf("  %s: Version: %d  File: %s  Cnt: %d\n",
+			versionOffset(off), bo.Uint16(vn[0:]), cString(safeTail(strs, bo.Uint32(vn[4:]))), cnt)
+
+		aux := off + uint64(bo.Uint32(vn[8:]))
+		for j := 0; j < cnt; j++ {
+			if aux+16 > uint64(len(data)) {
+				return fmt.Errorf("invalid version requirement auxiliary entry at offset %#x", aux)
+			}
+			va := data[aux:]
+			d.printf("  %s:   Name: %s  Flags: %s  Version: %d\n",
+				versionOffset(aux), cString(safeTail(strs, bo.Uint32(va[8:]))), versionFlags(bo.Uint16(va[4:])), bo.Uint16(va[6:]))
+			next := uint64(bo.Uint32(va[12:]))
+			if next == 0 {
+				break
+			}
+			aux += next
+		}
+
+		next := uint64(bo.Uint32(vn[12:]))
+		if next == 0 {
+			break
+		}
+		off += next
+	}
+
+	return nil
+}
+
+// versionOffset formats off like C's %#06x, whose width includes the 0x
+// prefix and which leaves the prefix out for zero.
+func versionOffset(off uint64) string {
+	if off == 0 {
+		return "000000"
+	}
+	return fmt.Sprintf("0x%04x", off)
+}
+
+func versionFlags(flags uint16) string {
+	if flags == 0 {
+		return "none"
+	}
+
+	var ss []string
+	for _, f := range []struct {
+		flag elf.VersionFlag
+		name string
+	}{
+		{elf.VER_FLG_BASE, "BASE"},
+		{elf.VER_FLG_WEAK, "WEAK"},
+		{elf.VER_FLG_INFO, "INFO"},
+	} {
+		if elf.VersionFlag(flags)&f.flag != 0 {
+			ss = append(ss, f.name)
+		}
+	}
+	if elf.VersionFlag(flags)&^(elf.VER_FLG_BASE|elf.VER_FLG_WEAK|elf.VER_FLG_INFO) != 0 {
+		ss = append(ss, "<unknown>")
+	}
+
+	return strings.Join(ss, " | ")
+}
+
+// safeTail returns b from off on, or nil if off is out of range.
+func safeTail(b []byte, off uint32) []byte {
+	if uint64(off) >= uint64(len(b)) {
+		return nil
+	}
+	return b[off:]
+}
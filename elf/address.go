@@ -0,0 +1,111 @@
+package elf
+
+import (
+	"fmt"
+)
+
+// SegmentForAddr returns the first PT_LOAD segment whose memory image,
+// including the zero-filled part beyond Filesz, contains the virtual address
+// vaddr. It returns nil if vaddr is not mapped.
+func (e *File) SegmentForAddr(vaddr uint64) *Segment {
+	for _, sg := range e.Segments {
+		h := sg.Header
+		if h.Type == PT_LOAD && vaddr >= h.Vaddr && vaddr-h.Vaddr < h.Memsz {
+			return sg
+		}
+	}
+
+	return nil
+}
+
+// SectionForAddr returns the first allocated section containing the virtual
+// address vaddr, or nil if there is none. Empty sections and SHT_NOBITS
+// thread-local sections such as .tbss, which take no space in the address
+// range they are given, are never returned.
+func (e *File) SectionForAddr(vaddr uint64) *Section {
+	for _, s := range e.Sections {
+		h := s.Header
+		if h.Flags&SHF_ALLOC == 0 || h.Type == SHT_NOBITS && h.Flags&SHF_TLS != 0 {
+			continue
+		}
+		if vaddr >= h.Addr && vaddr-h.Addr < h.Size {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// OffsetForAddr translates the virtual address vaddr into the offset of the
+// file holding its contents. It fails for unmapped addresses and for the
+// zero-filled part of a segment, such as .bss, which has no file contents.
+func (e *File) OffsetForAddr(vaddr uint64) (uint64, error) {
+	sg := e.SegmentForAddr(vaddr)
+	if sg == nil {
+		return 0, fmt.Errorf("address %#x is not mapped by any PT_LOAD segment", vaddr)
+	}
+
+	rel := vaddr - sg.Header.Vaddr
+	if rel >= sg.Header.Filesz {
+		return 0, fmt.Errorf("address %#x is not backed by the file", vaddr)
+	}
+
+	return sg.Header.Offset + rel, nil
+}
+
+// AddrForOffset translates the file offset off into the virtual address it
+// is loaded at. It fails if no PT_LOAD segment maps off.
+func (e *File) AddrForOffset(off uint64) (uint64, error) {
+	for _, sg := range e.Segments {
+		h := sg.Header
+		if h.Type == PT_LOAD && off >= h.Offset && off-h.Offset < h.Filesz {
+			return h.Vaddr + (off - h.Offset), nil
+		}
+	}
+
+	return 0, fmt.Errorf("offset %#x is not mapped by any PT_LOAD segment", off)
+}
+
+// maxZeroFill bounds the zero-filled bytes that ReadAddr may return, unless
+// the file is larger still. p_memsz comes from the file, so without a bound
+// a malformed segment could make ReadAddr allocate without limit.
+const maxZeroFill = 16 << 20
+
+// ReadAddr returns n bytes of the memory image of the file starting at the
+// virtual address vaddr, over the address space described by the PT_LOAD
+// segments. Bytes beyond the Filesz of a segment read as zeros, and a read
+// may span adjacent segments. It returns an error, along with the bytes
+// before it, when it reaches an unmapped address.
+func (e *File) ReadAddr(vaddr, n uint64) ([]byte, error) {
+	var buf []byte
+	zeros := uint64(0)
+	for n > 0 {
+		sg := e.SegmentForAddr(vaddr)
+		if sg == nil {
+			return buf, fmt.Errorf("address %#x is not mapped by any PT_LOAD segment", vaddr)
+		}
+
+		h := sg.Header
+		rel := vaddr - h.Vaddr
+		chunk := min(n, h.Memsz-rel)
+		if rel < h.Filesz {
+			chunk = min(chunk, h.Filesz-rel)
+			b, err := e.readAt(h.Offset+rel, chunk)
+			if err != nil {
+				return buf, fmt.Errorf("failed to read address %#x: %w", vaddr, err)
+			}
+			buf = append(buf, b...)
+		} else {
+			zeros += chunk
+			if zeros > max(e.size, maxZeroFill) {
+				return buf, fmt.Errorf("zero-filled range of %d bytes at address %#x is too large", chunk, vaddr)
+			}
+			buf = append(buf, make([]byte, chunk)...)
+		}
+
+		vaddr += chunk
+		n -= chunk
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,105 @@
+package elf_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+func TestAddressTranslation(t *testing.T) {
+	const name = "../testdata/elf_linux_amd64"
+	raw, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := elf.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for _, e := range []*elf.File{e, f} {
+		off, err := e.OffsetForAddr(0x544300)
+		if err != nil || off != 0x144300 {
+			t.Errorf("OffsetForAddr(.data): have %#x (%v), want 0x144300", off, err)
+		}
+		addr, err := e.AddrForOffset(0x144300)
+		if err != nil || addr != 0x544300 {
+			t.Errorf("AddrForOffset(.data): have %#x (%v), want 0x544300", addr, err)
+		}
+
+		// .bss lies beyond the end of the file contents of its segment.
+		if _, err := e.OffsetForAddr(0x54baa0); err == nil {
+			t.Errorf("OffsetForAddr(.bss) should fail")
+		}
+		if _, err := e.OffsetForAddr(0x1000); err == nil {
+			t.Errorf("OffsetForAddr of an unmapped address should fail")
+		}
+		if _, err := e.AddrForOffset(0x1c1e28); err == nil {
+			t.Errorf("AddrForOffset(.symtab) should fail")
+		}
+
+		if s := e.SectionForAddr(0x54baa0); s == nil || s.Name != ".bss" {
+			t.Errorf("SectionForAddr(.bss): have %v", s)
+		}
+		if s := e.SectionForAddr(0x401010); s == nil || s.Name != ".text" {
+			t.Errorf("SectionForAddr(.text): have %v", s)
+		}
+		if sg := e.SegmentForAddr(0x54baa0); sg != e.Segments[4] {
+			t.Errorf("SegmentForAddr(.bss): have %v", sg)
+		}
+		if sg := e.SegmentForAddr(0x1000); sg != nil {
+			t.Errorf("SegmentForAddr of an unmapped address: have %v", sg)
+		}
+
+		p, err := e.ReadAddr(0x54baa0-8, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := append(append([]byte(nil), raw[0x14ba98:0x14baa0]...), make([]byte, 8)...)
+		if !bytes.Equal(p, want) {
+			t.Errorf("ReadAddr across the end of the file contents:\n\thave %x\n\twant %x\n", p, want)
+		}
+
+		// The read spans the gap between the first two PT_LOAD segments.
+		if _, err := e.ReadAddr(0x400000+0x977ea-4, 8); err == nil {
+			t.Errorf("ReadAddr across an unmapped range should fail")
+		}
+		if b, err := e.ReadAddr(0x536000+0x48510-4, 16); err == nil || len(b) != 4 {
+			t.Errorf("ReadAddr past the last segment: have %d bytes (%v), want 4 and an error", len(b), err)
+		}
+
+		b, err := e.ReadAddr(0x401000, 4)
+		if err != nil || !bytes.Equal(b, raw[0x1000:0x1004]) {
+			t.Errorf("ReadAddr(.text): have %x (%v)", b, err)
+		}
+	}
+}
+
+func TestReadAddrHugeMemsz(t *testing.T) {
+	b := elf.NewBuilder(elf.ELFCLASS64, binary.LittleEndian, elf.EM_X86_64, elf.ET_EXEC)
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_LOAD, Flags: elf.PF_R | elf.PF_W, Vaddr: 0x10000, Memsz: 1 << 40, Align: 0x1000})
+	raw, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := e.ReadAddr(0x10000, 1<<39); err == nil {
+		t.Errorf("ReadAddr of 1<<39 zero-filled bytes should fail")
+	}
+	if b, err := e.ReadAddr(0x10000, 4096); err != nil || !bytes.Equal(b, make([]byte, 4096)) {
+		t.Errorf("ReadAddr of a page of zeros: have %d bytes (%v)", len(b), err)
+	}
+}
@@ -0,0 +1,442 @@
+package elf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// StringTable accumulates the contents of a string table section such as
+// .strtab or .shstrtab. Every distinct string is stored once, following the
+// empty string at offset 0.
+type StringTable struct {
+	buf     []byte
+	offsets map[string]uint32
+}
+
+func NewStringTable() *StringTable {
+	return &StringTable{buf: []byte{0}, offsets: map[string]uint32{"": 0}}
+}
+
+// Add returns the offset of s in the table, appending s if it is not there
+// yet.
+func (t *StringTable) Add(s string) uint32 {
+	if off, ok := t.offsets[s]; ok {
+		return off
+	}
+
+	off := uint32(len(t.buf))
+	t.offsets[s] = off
+	t.buf = append(append(t.buf, s...), 0)
+
+	return off
+}
+
+// Bytes returns the contents of the table.
+func (t *StringTable) Bytes() []byte {
+	return t.buf
+}
+
+// Builder creates an ELF file from scratch. The program header table follows
+// the ELF header, then the sections are laid out in the order they are
+// added, followed by the generated .shstrtab and the section header table.
+// Sections belonging to a PT_LOAD segment are placed so that their file
+// offsets match their addresses modulo the segment alignment.
+type Builder struct {
+	// Header is the ELF header of the file. Type, Machine, Version, Entry,
+	// Flags and the EI_OSABI and EI_ABIVERSION bytes of Ident are written
+	// as set; the other fields are computed by Build.
+	Header ELFHeader
+
+	class    Class
+	order    binary.ByteOrder
+	sections []*builderSection
+	segments []*builderSegment
+
+	symbols []Symbol
+	symtab  uint32
+	strtab  *StringTable
+}
+
+type builderSection struct {
+	name   string
+	header SectionHeader
+	data   []byte
+	// strtab is set for string tables, whose contents are taken when the
+	// file is built so that strings can be added after the section.
+	strtab *StringTable
+}
+
+type builderSegment struct {
+	header   ProgramHeader
+	sections []uint32
+}
+
+// NewBuilder returns a Builder for a file of the given class, byte order,
+// machine and type. Section 0, the null section, is always present.
+func NewBuilder(class Class, order binary.ByteOrder, machine Machine, typ Type) *Builder {
+	return &Builder{
+		Header: ELFHeader{
+			Type:    typ,
+			Machine: machine,
+			Version: uint32(EV_CURRENT),
+		},
+		class:    class,
+		order:    order,
+		sections: []*builderSection{{}},
+	}
+}
+
+// AddSection appends a section holding data and returns its index. The
+// Name, Offset and Size fields of header are ignored, except that Size is
+// kept for SHT_NOBITS sections, which have no data.
+func (b *Builder) AddSection(name string, header SectionHeader, data []byte) uint32 {
+	if header.Type != SHT_NOBITS {
+		header.Size = uint64(len(data))
+	}
+	b.sections = append(b.sections, &builderSection{name: name, header: header, data: data})
+
+	return uint32(len(b.sections) - 1)
+}
+
+// AddStringTable appends a SHT_STRTAB section with the contents of t and
+// returns its index.
+func (b *Builder) AddStringTable(name string, t *StringTable) uint32 {
+	b.sections = append(b.sections, &builderSection{
+		name:   name,
+		header: SectionHeader{Type: SHT_STRTAB, Addralign: 1},
+		strtab: t,
+	})
+
+	return uint32(len(b.sections) - 1)
+}
+
+// AddNotes appends a SHT_NOTE section holding notes and returns its index.
+// The section is 8 byte aligned when it holds a NT_GNU_PROPERTY_TYPE_0 note
+// of a 64-bit file, and 4 byte aligned otherwise.
+func (b *Builder) AddNotes(name string, notes ...*Note) uint32 {
+	align := uint64(4)
+	for _, n := range notes {
+		if n.Name == ELF_NOTE_GNU && n.Type == NT_GNU_PROPERTY_TYPE_0 && b.class == ELFCLASS64 {
+			align = 8
+		}
+	}
+
+	var data []byte
+	for _, n := range notes {
+		var nh [noteHeaderSize]byte
+		b.order.PutUint32(nh[0:], uint32(len(n.Name)+1))
+		b.order.PutUint32(nh[4:], uint32(len(n.Desc)))
+		b.order.PutUint32(nh[8:], uint32(n.Type))
+		data = append(data, nh[:]...)
+		data = append(append(data, n.Name...), 0)
+		data = append(data, make([]byte, alignUp(uint64(len(data)), align)-uint64(len(data)))...)
+		data = append(data, n.Desc...)
+		data = append(data, make([]byte, alignUp(uint64(len(data)), align)-uint64(len(data)))...)
+	}
+
+	return b.AddSection(name, SectionHeader{Type: SHT_NOTE, Flags: SHF_ALLOC, Addralign: align}, data)
+}
+
+// AddSymbol adds sym to the .symtab section, creating it along with its
+// .strtab the first time. Local symbols are written before the others as
+// the symbol table requires, each group in the order it was added, after
+// the null symbol.
+func (b *Builder) AddSymbol(sym Symbol) {
+	if b.strtab == nil {
+		b.strtab = NewStringTable()
+		b.symtab = b.AddSection(".symtab", SectionHeader{Type: SHT_SYMTAB}, nil)
+		b.sections[b.symtab].header.Link = b.AddStringTable(".strtab", b.strtab)
+	}
+
+	b.symbols = append(b.symbols, sym)
+}
+
+// AddSegment appends a program header and returns its index. When sections
+// are given, the segment is made to cover them: Offset, Vaddr, Filesz and
+// Memsz are computed from their layout, and Paddr is set to Vaddr unless
+// it is set already. A PT_PHDR segment covers the program header table.
+// Otherwise header is written as is.
+func (b *Builder) AddSegment(header ProgramHeader, sections ...uint32) uint32 {
+	b.segments = append(b.segments, &builderSegment{header: header, sections: sections})
+
+	return uint32(len(b.segments) - 1)
+}
+
+// SectionHeader returns the header of section n so that fields of
+// generated sections, such as the flags and address of a note section, can
+// be adjusted. It returns nil if there is no such section.
+func (b *Builder) SectionHeader(n uint32) *SectionHeader {
+	if int(n) >= len(b.sections) {
+		return nil
+	}
+
+	return &b.sections[n].header
+}
+
+// Build lays out the file and returns its contents.
+func (b *Builder) Build() ([]byte, error) {
+	var is32 bool
+	switch b.class {
+	case ELFCLASS32:
+		is32 = true
+	case ELFCLASS64:
+	default:
+		return nil, fmt.Errorf("invalid elf class: %d", b.class)
+	}
+
+	var data Data
+	switch b.order {
+	case binary.LittleEndian:
+		data = ELFDATA2LSB
+	case binary.BigEndian:
+		data = ELFDATA2MSB
+	default:
+		return nil, fmt.Errorf("unsupported byte order: %v", b.order)
+	}
+
+	ehsize, wordSize := uint64(64), uint64(8)
+	if is32 {
+		ehsize, wordSize = 52, 4
+	}
+
+	// Resolve the contents of every section, generating .shstrtab last.
+	shstrtab := NewStringTable()
+	shs := make([]SectionHeader, len(b.sections)+1)
+	datas := make([][]byte, len(b.sections)+1)
+	for i, s := range b.sections {
+		shs[i] = s.header
+		datas[i] = s.data
+		if s.strtab != nil {
+			datas[i] = s.strtab.Bytes()
+		}
+		if i > 0 && s.header.Type != SHT_NOBITS {
+			shs[i].Size = uint64(len(datas[i]))
+		}
+	}
+	if b.strtab != nil {
+		// Adding the symbols may extend .strtab, so do it first.
+		symtab, info, err := b.encodeSymbols(is32)
+		if err != nil {
+			return nil, err
+		}
+		datas[b.symtab] = symtab
+		sh := &shs[b.symtab]
+		sh.Size, sh.Info, sh.Addralign = uint64(len(symtab)), info, wordSize
+		sh.EntSize = sizeSymbol64
+		if is32 {
+			sh.EntSize = sizeSymbol32
+		}
+		datas[sh.Link] = b.strtab.Bytes()
+		shs[sh.Link].Size = uint64(len(datas[sh.Link]))
+	}
+	shstrndx := len(b.sections)
+	shs[shstrndx] = SectionHeader{Type: SHT_STRTAB, Addralign: 1}
+	for i, s := range b.sections {
+		shs[i].Name = shstrtab.Add(s.name)
+	}
+	shs[shstrndx].Name = shstrtab.Add(".shstrtab")
+	datas[shstrndx] = shstrtab.Bytes()
+	shs[shstrndx].Size = uint64(len(datas[shstrndx]))
+	shs[0] = SectionHeader{}
+
+	// The alignment of the PT_LOAD segment each section belongs to.
+	loadAlign := map[uint32]uint64{}
+	for i, sg := range b.segments {
+		for _, n := range sg.sections {
+			if n == 0 || int(n) >= len(b.sections) {
+				return nil, fmt.Errorf("segment %d refers to invalid section %d", i, n)
+			}
+			if sg.header.Type == PT_LOAD {
+				loadAlign[n] = max(loadAlign[n], sg.header.Align, 1)
+			}
+		}
+	}
+	segmentOf := map[uint32]*builderSegment{}
+	for _, sg := range b.segments {
+		if sg.header.Type != PT_LOAD {
+			continue
+		}
+		for _, n := range sg.sections {
+			segmentOf[n] = sg
+		}
+	}
+
+	phoff := uint64(0)
+	phnum := uint64(len(b.segments))
+	phtSize := phnum * sizeProgramHeader64
+	if is32 {
+		phtSize = phnum * sizeProgramHeader32
+	}
+	cursor := ehsize
+	if phnum > 0 {
+		phoff = alignCongruent(cursor, wordSize, 0)
+		cursor = phoff + phtSize
+	}
+
+	// Sections of a PT_LOAD segment keep the distances between their
+	// addresses; the first one decides where the segment starts.
+	type base struct{ offset, addr uint64 }
+	bases := map[*builderSegment]base{}
+	for i := 1; i < len(shs); i++ {
+		sh := &shs[i]
+		sg := segmentOf[uint32(i)]
+		if bs, ok := bases[sg]; ok && sg != nil {
+			if sh.Addr < bs.addr || bs.offset+sh.Addr-bs.addr < cursor && sh.Type != SHT_NOBITS {
+				return nil, fmt.Errorf("section %d (%s) overlaps the previous section of its segment", i, b.sectionName(i))
+			}
+			sh.Offset = bs.offset + sh.Addr - bs.addr
+		} else if sg != nil {
+			sh.Offset = alignCongruent(cursor, loadAlign[uint32(i)], sh.Addr)
+			bases[sg] = base{sh.Offset, sh.Addr}
+		} else {
+			sh.Offset = alignCongruent(cursor, max(sh.Addralign, 1), 0)
+		}
+
+		if sh.Type != SHT_NOBITS {
+			cursor = sh.Offset + uint64(len(datas[i]))
+		}
+	}
+
+	shnum := uint64(len(shs))
+	shoff := alignCongruent(cursor, wordSize, 0)
+	shtSize := shnum * sizeSectionHeader64
+	if is32 {
+		shtSize = shnum * sizeSectionHeader32
+	}
+
+	phs := make([]ProgramHeader, phnum)
+	for i, sg := range b.segments {
+		phs[i] = sg.header
+		ph := &phs[i]
+		switch {
+		case len(sg.sections) > 0:
+			first := true
+			var fileEnd, memEnd uint64
+			for _, n := range sg.sections {
+				sh := shs[n]
+				if first || sh.Offset < ph.Offset {
+					ph.Offset = sh.Offset
+				}
+				if first || sh.Addr < ph.Vaddr {
+					ph.Vaddr = sh.Addr
+				}
+				first = false
+				size := uint64(len(datas[n]))
+				if sh.Type == SHT_NOBITS {
+					size = 0
+				}
+				fileEnd = max(fileEnd, sh.Offset+size)
+				if !tbssSpecial(&sh, ph) {
+					memEnd = max(memEnd, sh.Addr+sh.Size)
+				}
+			}
+			ph.Filesz = max(fileEnd, ph.Offset) - ph.Offset
+			ph.Memsz = max(memEnd, ph.Vaddr) - ph.Vaddr
+			if ph.Paddr == 0 {
+				ph.Paddr = ph.Vaddr
+			}
+		case ph.Type == PT_PHDR:
+			ph.Offset, ph.Filesz, ph.Memsz = phoff, phtSize, phtSize
+		}
+	}
+
+	hdr := b.Header
+	copy(hdr.Ident[:], ELF_MAGIC)
+	hdr.Ident[EI_CLASS] = byte(b.class)
+	hdr.Ident[EI_DATA] = byte(data)
+	hdr.Ident[EI_VERSION] = EV_CURRENT
+	hdr.Phoff, hdr.Shoff = phoff, shoff
+	setHeaderCounts(&hdr, shs, uint64(shstrndx), phnum, is32)
+	if phnum == 0 {
+		hdr.Phoff, hdr.Phentsize = 0, 0
+	}
+
+	out := make([]byte, shoff+shtSize)
+	var buf bytes.Buffer
+	if err := writeStruct(&buf, b.order, is32, &hdr); err != nil {
+		return nil, err
+	}
+	copy(out, buf.Bytes())
+
+	buf.Reset()
+	for i := range phs {
+		if err := writeStruct(&buf, b.order, is32, &phs[i]); err != nil {
+			return nil, err
+		}
+	}
+	copy(out[phoff:], buf.Bytes())
+
+	for i := range shs {
+		if shs[i].Type != SHT_NOBITS {
+			copy(out[shs[i].Offset:], datas[i])
+		}
+	}
+
+	buf.Reset()
+	for i := range shs {
+		if err := writeStruct(&buf, b.order, is32, &shs[i]); err != nil {
+			return nil, err
+		}
+	}
+	copy(out[shoff:], buf.Bytes())
+
+	return out, nil
+}
+
+// encodeSymbols returns the contents of .symtab and its sh_info, the index
+// of the first non-local symbol.
+func (b *Builder) encodeSymbols(is32 bool) ([]byte, uint32, error) {
+	syms := append([]Symbol{{}}, b.symbols...)
+	sort.SliceStable(syms[1:], func(i, j int) bool {
+		return syms[1+i].Bind == STB_LOCAL && syms[1+j].Bind != STB_LOCAL
+	})
+
+	info := uint32(len(syms))
+	var buf bytes.Buffer
+	for i, sym := range syms {
+		if sym.Bind != STB_LOCAL && uint32(i) < info {
+			info = uint32(i)
+		}
+		if sym.Section > SHN_HIRESERVE {
+			return nil, 0, fmt.Errorf("symbol %s: section index %d requires SHT_SYMTAB_SHNDX, which is not supported", sym.Name, sym.Section)
+		}
+
+		name := b.strtab.Add(sym.Name)
+		st := uint8(sym.Bind)<<4 | uint8(sym.Type)&0xf
+		var err error
+		if is32 {
+			err = binary.Write(&buf, b.order, &symbol32{
+				Name:  name,
+				Value: uint32(sym.Value),
+				Size:  uint32(sym.Size),
+				Info:  st,
+				Other: uint8(sym.Visibility),
+				Shndx: uint16(sym.Section),
+			})
+		} else {
+			err = binary.Write(&buf, b.order, &symbol64{
+				Name:  name,
+				Info:  st,
+				Other: uint8(sym.Visibility),
+				Shndx: uint16(sym.Section),
+				Value: sym.Value,
+				Size:  sym.Size,
+			})
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to write symbol %s: %w", sym.Name, err)
+		}
+	}
+
+	return buf.Bytes(), info, nil
+}
+
+func (b *Builder) sectionName(i int) string {
+	if i < len(b.sections) {
+		return b.sections[i].name
+	}
+
+	return ".shstrtab"
+}
@@ -0,0 +1,178 @@
+package elf_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+// exit0 is x86-64 code for exit(0): mov $60, %eax; xor %edi, %edi; syscall.
+var exit0 = []byte{0xb8, 0x3c, 0x00, 0x00, 0x00, 0x31, 0xff, 0x0f, 0x05}
+
+func buildExecutable(t *testing.T, class elf.Class, bo binary.ByteOrder) []byte {
+	t.Helper()
+
+	b := elf.NewBuilder(class, bo, elf.EM_X86_64, elf.ET_EXEC)
+	b.Header.Entry = 0x401000
+
+	text := b.AddSection(".text", elf.SectionHeader{Type: elf.SHT_PROGBITS, Flags: elf.SHF_ALLOC | elf.SHF_EXECINSTR, Addr: 0x401000, Addralign: 16}, exit0)
+	note := b.AddNotes(".note.gnu.build-id", &elf.Note{Name: elf.ELF_NOTE_GNU, Type: elf.NT_GNU_BUILD_ID, Desc: []byte{0xde, 0xad, 0xbe, 0xef}})
+	b.SectionHeader(note).Addr = 0x402000
+	data := b.AddSection(".data", elf.SectionHeader{Type: elf.SHT_PROGBITS, Flags: elf.SHF_ALLOC | elf.SHF_WRITE, Addr: 0x403000, Addralign: 8}, []byte{1, 2, 3, 4, 5, 6, 7, 8})
+	bss := b.AddSection(".bss", elf.SectionHeader{Type: elf.SHT_NOBITS, Flags: elf.SHF_ALLOC | elf.SHF_WRITE, Addr: 0x403008, Size: 0x100, Addralign: 8}, nil)
+	b.AddSection(".comment", elf.SectionHeader{Type: elf.SHT_PROGBITS, Flags: elf.SHF_MERGE | elf.SHF_STRINGS, Addralign: 1, EntSize: 1}, []byte("builder\x00"))
+
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_LOAD, Flags: elf.PF_R | elf.PF_X, Align: 0x1000}, text)
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_LOAD, Flags: elf.PF_R, Align: 0x1000}, note)
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_LOAD, Flags: elf.PF_R | elf.PF_W, Align: 0x1000}, data, bss)
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_NOTE, Flags: elf.PF_R, Align: 4}, note)
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_GNU_STACK, Flags: elf.PF_R | elf.PF_W, Align: 16})
+
+	b.AddSymbol(elf.Symbol{Name: "_start", Value: 0x401000, Size: uint64(len(exit0)), Bind: elf.STB_GLOBAL, Type: elf.STT_FUNC, Section: text})
+	b.AddSymbol(elf.Symbol{Name: "counter", Value: 0x403008, Size: 8, Bind: elf.STB_LOCAL, Type: elf.STT_OBJECT, Section: bss})
+	b.AddSymbol(elf.Symbol{Name: "abs", Value: 42, Bind: elf.STB_WEAK, Type: elf.STT_NOTYPE, Section: elf.SHN_ABS})
+
+	raw, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return raw
+}
+
+func TestBuilder(t *testing.T) {
+	for _, bo := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		for _, class := range []elf.Class{elf.ELFCLASS32, elf.ELFCLASS64} {
+			raw := buildExecutable(t, class, bo)
+			e, err := elf.New(raw)
+			if err != nil {
+				t.Fatalf("%v/%v: %s", class, bo, err)
+			}
+
+			if e.Header.Ident[elf.EI_CLASS] != byte(class) || e.Endianness != bo {
+				t.Errorf("%v/%v: have ident %v", class, bo, e.Header.Ident)
+			}
+			if e.Header.Entry != 0x401000 || e.Header.Type != elf.ET_EXEC || e.Header.Machine != elf.EM_X86_64 {
+				t.Errorf("%v/%v: have header %#v", class, bo, e.Header)
+			}
+
+			var names []string
+			for _, s := range e.Sections {
+				names = append(names, s.Name)
+			}
+			wantNames := []string{"", ".text", ".note.gnu.build-id", ".data", ".bss", ".comment", ".symtab", ".strtab", ".shstrtab"}
+			if !reflect.DeepEqual(names, wantNames) {
+				t.Errorf("%v/%v:\n\thave %#v\n\twant %#v\n", class, bo, names, wantNames)
+			}
+			if text := e.SectionByName(".text"); !bytes.Equal(text.Raw, exit0) {
+				t.Errorf("%v/%v: have .text %x", class, bo, text.Raw)
+			}
+
+			// Every allocated section is mapped at its address.
+			for _, s := range e.Sections {
+				if s.Header.Flags&elf.SHF_ALLOC == 0 || s.Header.Type == elf.SHT_NOBITS {
+					continue
+				}
+				found := false
+				for _, sg := range e.SegmentsByType(elf.PT_LOAD) {
+					h := sg.Header
+					if s.Header.Addr >= h.Vaddr && s.Header.Addr+s.Header.Size <= h.Vaddr+h.Filesz &&
+						s.Header.Offset-h.Offset == s.Header.Addr-h.Vaddr && h.Offset%h.Align == h.Vaddr%h.Align {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("%v/%v: section %s is not mapped by a PT_LOAD segment", class, bo, s.Name)
+				}
+			}
+			rw := e.Segments[2].Header
+			if rw.Filesz != 8 || rw.Memsz != 0x108 {
+				t.Errorf("%v/%v: have data segment %#v", class, bo, rw)
+			}
+
+			id, err := e.GNUBuildID()
+			if err != nil || !bytes.Equal(id, []byte{0xde, 0xad, 0xbe, 0xef}) {
+				t.Errorf("%v/%v: have build id %x (%v)", class, bo, id, err)
+			}
+			if ns, err := e.SegmentNotes(e.SegmentsByType(elf.PT_NOTE)[0]); err != nil || len(ns) != 1 {
+				t.Errorf("%v/%v: have %d notes in PT_NOTE (%v)", class, bo, len(ns), err)
+			}
+
+			syms, err := e.Symbols()
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantSyms := []*elf.Symbol{
+				{},
+				{Name: "counter", Value: 0x403008, Size: 8, Bind: elf.STB_LOCAL, Type: elf.STT_OBJECT, Section: 4},
+				{Name: "_start", Value: 0x401000, Size: uint64(len(exit0)), Bind: elf.STB_GLOBAL, Type: elf.STT_FUNC, Section: 1},
+				{Name: "abs", Value: 42, Bind: elf.STB_WEAK, Type: elf.STT_NOTYPE, Section: elf.SHN_ABS},
+			}
+			if !reflect.DeepEqual(syms, wantSyms) {
+				t.Errorf("%v/%v:\n\thave %#v\n\twant %#v\n", class, bo, syms, wantSyms)
+			}
+			if info := e.SectionByName(".symtab").Header.Info; info != 2 {
+				t.Errorf("%v/%v: have .symtab sh_info %d, want 2", class, bo, info)
+			}
+
+			// A built file is already laid out the way WriteTo would.
+			var buf bytes.Buffer
+			if _, err := e.WriteTo(&buf); err != nil || !bytes.Equal(buf.Bytes(), raw) {
+				t.Errorf("%v/%v: WriteTo does not reproduce the built file (%v)", class, bo, err)
+			}
+		}
+	}
+}
+
+func TestBuilderRelocatable(t *testing.T) {
+	b := elf.NewBuilder(elf.ELFCLASS64, binary.LittleEndian, elf.EM_X86_64, elf.ET_REL)
+	st := elf.NewStringTable()
+	if off := st.Add("first"); off != 1 {
+		t.Errorf("have offset %d, want 1", off)
+	}
+	if off := st.Add("first"); off != 1 {
+		t.Errorf("strings should be stored once, have offset %d", off)
+	}
+	b.AddStringTable(".dynstr", st)
+	st.Add("second")
+
+	raw, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(e.Segments) != 0 || e.Header.Phoff != 0 {
+		t.Errorf("have %d segments at %#x, want none", len(e.Segments), e.Header.Phoff)
+	}
+	if s := e.SectionByName(".dynstr"); s == nil || string(s.Raw) != "\x00first\x00second\x00" {
+		t.Errorf("strings added after the section should be included")
+	}
+}
+
+func TestBuilderInvalid(t *testing.T) {
+	b := elf.NewBuilder(elf.ELFCLASSNONE, binary.LittleEndian, elf.EM_X86_64, elf.ET_REL)
+	if _, err := b.Build(); err == nil {
+		t.Errorf("invalid class should fail")
+	}
+
+	b = elf.NewBuilder(elf.ELFCLASS64, binary.LittleEndian, elf.EM_X86_64, elf.ET_EXEC)
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_LOAD}, 5)
+	if _, err := b.Build(); err == nil {
+		t.Errorf("segment with an invalid section should fail")
+	}
+
+	b = elf.NewBuilder(elf.ELFCLASS64, binary.LittleEndian, elf.EM_X86_64, elf.ET_EXEC)
+	s1 := b.AddSection(".a", elf.SectionHeader{Type: elf.SHT_PROGBITS, Flags: elf.SHF_ALLOC, Addr: 0x1000}, make([]byte, 16))
+	s2 := b.AddSection(".b", elf.SectionHeader{Type: elf.SHT_PROGBITS, Flags: elf.SHF_ALLOC, Addr: 0x1008}, make([]byte, 16))
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_LOAD, Align: 0x1000}, s1, s2)
+	if _, err := b.Build(); err == nil {
+		t.Errorf("overlapping sections should fail")
+	}
+}
@@ -0,0 +1,189 @@
+package elf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hnts/goelftools/internal/zstd"
+)
+
+// Compression describes how the contents of a section are compressed.
+type Compression struct {
+	Type CompressionType
+	// Size and Addralign are the size and alignment of the uncompressed
+	// contents.
+	Size      uint64
+	Addralign uint64
+	// Legacy is set for .zdebug sections, which start with "ZLIB" and the
+	// big-endian uncompressed size instead of a compression header and are
+	// not flagged SHF_COMPRESSED.
+	Legacy bool
+
+	// offset is where the compressed stream starts in the section.
+	offset uint64
+}
+
+// chdr32 and chdr64 are the compression headers (Elf_Chdr) that start
+// SHF_COMPRESSED sections.
+type chdr32 struct {
+	Type      uint32
+	Size      uint32
+	Addralign uint32
+}
+
+type chdr64 struct {
+	Type      uint32
+	Reserved  uint32
+	Size      uint64
+	Addralign uint64
+}
+
+var (
+	sizeChdr32 = uint64(binary.Size(chdr32{}))
+	sizeChdr64 = uint64(binary.Size(chdr64{}))
+)
+
+// zdebugHeaderSize is the size of the "ZLIB" magic and the size that start
+// legacy .zdebug sections.
+const zdebugHeaderSize = 12
+
+// Compression returns how the contents of the section are compressed, or nil
+// if they are not. Only the header of the contents is read.
+func (s *Section) Compression() (*Compression, error) {
+	if s.Header.Type == SHT_NOBITS {
+		return nil, nil
+	}
+
+	head := s.Raw
+	if s.Raw == nil && s.sr != nil {
+		head = make([]byte, min(uint64(s.sr.Size()), sizeChdr64))
+		if n, err := s.sr.ReadAt(head, 0); n != len(head) {
+			return nil, fmt.Errorf("failed to read section %s: %w", s.Name, err)
+		}
+	}
+
+	return s.compression(head)
+}
+
+// compression decodes the compression header at the start of raw, which
+// holds the beginning of the stored contents of the section.
+func (s *Section) compression(raw []byte) (*Compression, error) {
+	if s.Header.Type == SHT_NOBITS {
+		return nil, nil
+	}
+
+	if s.Header.Flags&SHF_COMPRESSED == 0 {
+		if !strings.HasPrefix(s.Name, ".zdebug") || len(raw) < zdebugHeaderSize || string(raw[:4]) != "ZLIB" {
+			return nil, nil
+		}
+
+		return &Compression{
+			Type:      ELFCOMPRESS_ZLIB,
+			Size:      binary.BigEndian.Uint64(raw[4:]),
+			Addralign: s.Header.Addralign,
+			Legacy:    true,
+			offset:    zdebugHeaderSize,
+		}, nil
+	}
+
+	if s.file == nil {
+		return nil, fmt.Errorf("compressed section %s does not belong to a file", s.Name)
+	}
+
+	r := bytes.NewReader(raw)
+	if s.file.is32() {
+		var ch chdr32
+		if err := binary.Read(r, s.file.Endianness, &ch); err != nil {
+			return nil, fmt.Errorf("failed to read compression header of section %s: %w", s.Name, err)
+		}
+		return &Compression{
+			Type:      CompressionType(ch.Type),
+			Size:      uint64(ch.Size),
+			Addralign: uint64(ch.Addralign),
+			offset:    sizeChdr32,
+		}, nil
+	}
+
+	var ch chdr64
+	if err := binary.Read(r, s.file.Endianness, &ch); err != nil {
+		return nil, fmt.Errorf("failed to read compression header of section %s: %w", s.Name, err)
+	}
+
+	return &Compression{
+		Type:      CompressionType(ch.Type),
+		Size:      ch.Size,
+		Addralign: ch.Addralign,
+		offset:    sizeChdr64,
+	}, nil
+}
+
+// decompress inflates the stored contents raw of a section, checking that
+// they decompress to exactly Size bytes.
+func (c *Compression) decompress(raw []byte) ([]byte, error) {
+	src := raw[c.offset:]
+
+	var out []byte
+	switch c.Type {
+	case ELFCOMPRESS_ZLIB:
+		zr, err := zlib.NewReader(bytes.NewReader(src))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress section: %w", err)
+		}
+		// Read one byte more than expected to detect oversized contents.
+		out, err = io.ReadAll(io.LimitReader(zr, int64(min(c.Size, 1<<62))+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress section: %w", err)
+		}
+	case ELFCOMPRESS_ZSTD:
+		var err error
+		out, err = zstd.Decompress(src, c.Size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress section: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported compression type: %d", c.Type)
+	}
+
+	if uint64(len(out)) != c.Size {
+		return nil, fmt.Errorf("decompressed section size %d does not match %d", len(out), c.Size)
+	}
+
+	return out, nil
+}
+
+// lazyReader is an io.ReadSeeker over contents loaded on first use.
+type lazyReader struct {
+	load func() ([]byte, error)
+	r    *bytes.Reader
+	err  error
+}
+
+func (l *lazyReader) init() error {
+	if l.r == nil && l.err == nil {
+		var b []byte
+		b, l.err = l.load()
+		l.r = bytes.NewReader(b)
+	}
+
+	return l.err
+}
+
+func (l *lazyReader) Read(p []byte) (int, error) {
+	if err := l.init(); err != nil {
+		return 0, err
+	}
+
+	return l.r.Read(p)
+}
+
+func (l *lazyReader) Seek(offset int64, whence int) (int64, error) {
+	if err := l.init(); err != nil {
+		return 0, err
+	}
+
+	return l.r.Seek(offset, whence)
+}
@@ -0,0 +1,154 @@
+package elf_test
+
+import (
+	"bytes"
+	stdelf "debug/elf"
+	"io"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+const (
+	libsampleDebug     = "../testdata/libsample_linux_amd64_debug.o"
+	libsampleDebugZlib = "../testdata/libsample_linux_amd64_debug_zlib.o"
+	libsampleDebugZstd = "../testdata/libsample_linux_amd64_debug_zstd.o"
+)
+
+func TestCompressedSections(t *testing.T) {
+	plain, err := elf.New(mustReadFile(t, libsampleDebug))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		typ  elf.CompressionType
+	}{
+		{libsampleDebugZlib, elf.ELFCOMPRESS_ZLIB},
+		{libsampleDebugZstd, elf.ELFCOMPRESS_ZSTD},
+	} {
+		e, err := elf.New(mustReadFile(t, tt.name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := elf.Open(tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		for _, e := range []*elf.File{e, f} {
+			compressed := 0
+			for _, s := range e.Sections {
+				c, err := s.Compression()
+				if err != nil {
+					t.Fatalf("%s: %s", s.Name, err)
+				}
+				if s.Header.Flags&elf.SHF_COMPRESSED == 0 {
+					if c != nil {
+						t.Errorf("%s: %s should not be compressed", tt.name, s.Name)
+					}
+					continue
+				}
+
+				compressed++
+				want, err := plain.SectionByName(s.Name).Data()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if c == nil || c.Type != tt.typ || c.Size != uint64(len(want)) || c.Legacy {
+					t.Errorf("%s: %s: have compression %#v", tt.name, s.Name, c)
+				}
+
+				data, err := s.Data()
+				if err != nil {
+					t.Errorf("%s: %s: %s", tt.name, s.Name, err)
+				}
+				if !bytes.Equal(data, want) {
+					t.Errorf("%s: %s: decompressed contents differ", tt.name, s.Name)
+				}
+
+				opened, err := io.ReadAll(s.Open())
+				if err != nil || !bytes.Equal(opened, want) {
+					t.Errorf("%s: %s: Open does not decompress (%v)", tt.name, s.Name, err)
+				}
+			}
+			if compressed == 0 {
+				t.Errorf("%s: no compressed sections found", tt.name)
+			}
+		}
+	}
+}
+
+func TestZdebugSections(t *testing.T) {
+	const name = "../testdata/elf_linux_amd64"
+	e, err := elf.New(mustReadFile(t, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	std, err := stdelf.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer std.Close()
+
+	for _, name := range []string{".zdebug_abbrev", ".zdebug_info", ".zdebug_line", ".zdebug_frame"} {
+		s := e.SectionByName(name)
+		c, err := s.Compression()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c == nil || c.Type != elf.ELFCOMPRESS_ZLIB || !c.Legacy {
+			t.Errorf("%s: have compression %#v", name, c)
+			continue
+		}
+
+		data, err := s.Data()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := std.Section(name).Data()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uint64(len(data)) != c.Size || !bytes.Equal(data, want) {
+			t.Errorf("%s: have %d bytes, want %d matching debug/elf", name, len(data), c.Size)
+		}
+		if bytes.Equal(s.Raw, data) {
+			t.Errorf("%s: Raw should keep the compressed contents", name)
+		}
+	}
+
+	if c, err := e.SectionByName(".debug_gdb_scripts").Compression(); c != nil || err != nil {
+		t.Errorf(".debug_gdb_scripts: have compression %#v (%v)", c, err)
+	}
+}
+
+func TestCompressedSectionsMalformed(t *testing.T) {
+	e, err := elf.New(mustReadFile(t, libsampleDebugZstd))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := e.SectionByName(".debug_info")
+	s.Raw = append([]byte(nil), s.Raw...)
+	s.Raw[8]++ // ch_size
+	if _, err := s.Data(); err == nil {
+		t.Errorf("size mismatch should fail")
+	}
+
+	s.Raw[8]--
+	s.Raw[0] = 0x7f // ch_type
+	if _, err := s.Data(); err == nil {
+		t.Errorf("unknown compression type should fail")
+	}
+	if _, err := io.ReadAll(s.Open()); err == nil {
+		t.Errorf("Open of an unknown compression type should fail")
+	}
+
+	s.Raw = s.Raw[:10]
+	if _, err := s.Compression(); err == nil {
+		t.Errorf("truncated compression header should fail")
+	}
+}
@@ -12,9 +12,87 @@ const (
 	EI_PADDING    uint8 = 9
 )
 
-// SHN_XINDEX marks that the real section header string table index is stored
-// out of band (in the sh_link field of section 0), which is not yet supported.
-const SHN_XINDEX uint16 = 0xffff
+// Versions of the ELF format, in e_ident[EI_VERSION] and e_version.
+// EV_CURRENT is the only valid one.
+const (
+	EV_NONE    uint8 = 0
+	EV_CURRENT uint8 = 1
+)
+
+// OSABI identifies the operating system or ABI the file is built for, from
+// e_ident[EI_OSABI]. Values from 64 up are specific to the machine.
+type OSABI uint8
+
+const (
+	ELFOSABI_NONE       OSABI = 0
+	ELFOSABI_SYSV       OSABI = 0
+	ELFOSABI_HPUX       OSABI = 1
+	ELFOSABI_NETBSD     OSABI = 2
+	ELFOSABI_LINUX      OSABI = 3
+	ELFOSABI_GNU        OSABI = 3
+	ELFOSABI_SOLARIS    OSABI = 6
+	ELFOSABI_AIX        OSABI = 7
+	ELFOSABI_IRIX       OSABI = 8
+	ELFOSABI_FREEBSD    OSABI = 9
+	ELFOSABI_TRU64      OSABI = 10
+	ELFOSABI_MODESTO    OSABI = 11
+	ELFOSABI_OPENBSD    OSABI = 12
+	ELFOSABI_OPENVMS    OSABI = 13
+	ELFOSABI_NSK        OSABI = 14
+	ELFOSABI_AROS       OSABI = 15
+	ELFOSABI_FENIXOS    OSABI = 16
+	ELFOSABI_CLOUDABI   OSABI = 17
+	ELFOSABI_OPENVOS    OSABI = 18
+	ELFOSABI_CUDA       OSABI = 51
+	ELFOSABI_ARM_AEABI  OSABI = 64
+	ELFOSABI_AMDGPU_HSA OSABI = 64
+	ELFOSABI_ARM        OSABI = 97
+	ELFOSABI_STANDALONE OSABI = 255
+)
+
+// Class is the word size of the file, from e_ident[EI_CLASS].
+type Class uint8
+
+const (
+	ELFCLASSNONE Class = 0
+	ELFCLASS32   Class = 1
+	ELFCLASS64   Class = 2
+)
+
+// Data is the byte order of the file, from e_ident[EI_DATA].
+type Data uint8
+
+const (
+	ELFDATANONE Data = 0
+	ELFDATA2LSB Data = 1
+	ELFDATA2MSB Data = 2
+)
+
+// ABIVersion is the version of the ABI given by OSABI, from
+// e_ident[EI_ABIVERSION]. Its meaning depends on the OS/ABI; most of them
+// leave it 0.
+type ABIVersion uint8
+
+// Reserved section indices. SHN_XINDEX in e_shstrndx or st_shndx means that
+// the real index is stored out of band: in the sh_link field of section 0 or
+// in the SHT_SYMTAB_SHNDX table respectively. They are untyped so that they
+// compare against both the 16-bit on-disk fields and resolved 32-bit indices.
+const (
+	SHN_UNDEF     = 0
+	SHN_LORESERVE = 0xff00
+	SHN_LOPROC    = 0xff00
+	SHN_HIPROC    = 0xff1f
+	SHN_LOOS      = 0xff20
+	SHN_HIOS      = 0xff3f
+	SHN_ABS       = 0xfff1
+	SHN_COMMON    = 0xfff2
+	SHN_XINDEX    = 0xffff
+	SHN_HIRESERVE = 0xffff
+)
+
+// PN_XNUM in e_phnum means that the real number of program headers is stored
+// in the sh_info field of section 0.
+const PN_XNUM = 0xffff
 
 type Type uint16
 
@@ -25,6 +103,8 @@ const (
 	ET_DYN    Type = 3
 	ET_CORE   Type = 4
 	ET_NUM    Type = 5
+	ET_LOOS   Type = 0xfe00
+	ET_HIOS   Type = 0xfeff
 	ET_LPROC  Type = 0xff00
 	ET_HIPROC Type = 0xffff
 )
@@ -32,17 +112,191 @@ const (
 type Machine uint16
 
 const (
-	EM_NONE    Machine = 0
-	EM_386     Machine = 3
-	EM_486     Machine = 6
-	EM_860     Machine = 7
-	EM_MIPS    Machine = 8
-	EM_PPC     Machine = 20
-	EM_PPC64   Machine = 21
-	EM_ARM     Machine = 40
-	EM_IA_64   Machine = 50
-	EM_X86_64  Machine = 62
-	EM_AARCH64 Machine = 183
+	EM_NONE          Machine = 0
+	EM_M32           Machine = 1
+	EM_SPARC         Machine = 2
+	EM_386           Machine = 3
+	EM_68K           Machine = 4
+	EM_88K           Machine = 5
+	EM_486           Machine = 6
+	EM_IAMCU         Machine = 6
+	EM_860           Machine = 7
+	EM_MIPS          Machine = 8
+	EM_S370          Machine = 9
+	EM_MIPS_RS3_LE   Machine = 10
+	EM_PARISC        Machine = 15
+	EM_VPP500        Machine = 17
+	EM_SPARC32PLUS   Machine = 18
+	EM_960           Machine = 19
+	EM_PPC           Machine = 20
+	EM_PPC64         Machine = 21
+	EM_S390          Machine = 22
+	EM_SPU           Machine = 23
+	EM_V800          Machine = 36
+	EM_FR20          Machine = 37
+	EM_RH32          Machine = 38
+	EM_RCE           Machine = 39
+	EM_ARM           Machine = 40
+	EM_ALPHA_STD     Machine = 41
+	EM_SH            Machine = 42
+	EM_SPARCV9       Machine = 43
+	EM_TRICORE       Machine = 44
+	EM_ARC           Machine = 45
+	EM_H8_300        Machine = 46
+	EM_H8_300H       Machine = 47
+	EM_H8S           Machine = 48
+	EM_H8_500        Machine = 49
+	EM_IA_64         Machine = 50
+	EM_MIPS_X        Machine = 51
+	EM_COLDFIRE      Machine = 52
+	EM_68HC12        Machine = 53
+	EM_MMA           Machine = 54
+	EM_PCP           Machine = 55
+	EM_NCPU          Machine = 56
+	EM_NDR1          Machine = 57
+	EM_STARCORE      Machine = 58
+	EM_ME16          Machine = 59
+	EM_ST100         Machine = 60
+	EM_TINYJ         Machine = 61
+	EM_X86_64        Machine = 62
+	EM_PDSP          Machine = 63
+	EM_PDP10         Machine = 64
+	EM_PDP11         Machine = 65
+	EM_FX66          Machine = 66
+	EM_ST9PLUS       Machine = 67
+	EM_ST7           Machine = 68
+	EM_68HC16        Machine = 69
+	EM_68HC11        Machine = 70
+	EM_68HC08        Machine = 71
+	EM_68HC05        Machine = 72
+	EM_SVX           Machine = 73
+	EM_ST19          Machine = 74
+	EM_VAX           Machine = 75
+	EM_CRIS          Machine = 76
+	EM_JAVELIN       Machine = 77
+	EM_FIREPATH      Machine = 78
+	EM_ZSP           Machine = 79
+	EM_MMIX          Machine = 80
+	EM_HUANY         Machine = 81
+	EM_PRISM         Machine = 82
+	EM_AVR           Machine = 83
+	EM_FR30          Machine = 84
+	EM_D10V          Machine = 85
+	EM_D30V          Machine = 86
+	EM_V850          Machine = 87
+	EM_M32R          Machine = 88
+	EM_MN10300       Machine = 89
+	EM_MN10200       Machine = 90
+	EM_PJ            Machine = 91
+	EM_OPENRISC      Machine = 92
+	EM_ARC_COMPACT   Machine = 93
+	EM_XTENSA        Machine = 94
+	EM_VIDEOCORE     Machine = 95
+	EM_TMM_GPP       Machine = 96
+	EM_NS32K         Machine = 97
+	EM_TPC           Machine = 98
+	EM_SNP1K         Machine = 99
+	EM_ST200         Machine = 100
+	EM_IP2K          Machine = 101
+	EM_MAX           Machine = 102
+	EM_CR            Machine = 103
+	EM_F2MC16        Machine = 104
+	EM_MSP430        Machine = 105
+	EM_BLACKFIN      Machine = 106
+	EM_SE_C33        Machine = 107
+	EM_SEP           Machine = 108
+	EM_ARCA          Machine = 109
+	EM_UNICORE       Machine = 110
+	EM_EXCESS        Machine = 111
+	EM_DXP           Machine = 112
+	EM_ALTERA_NIOS2  Machine = 113
+	EM_CRX           Machine = 114
+	EM_XGATE         Machine = 115
+	EM_C166          Machine = 116
+	EM_M16C          Machine = 117
+	EM_DSPIC30F      Machine = 118
+	EM_CE            Machine = 119
+	EM_M32C          Machine = 120
+	EM_TSK3000       Machine = 131
+	EM_RS08          Machine = 132
+	EM_SHARC         Machine = 133
+	EM_ECOG2         Machine = 134
+	EM_SCORE7        Machine = 135
+	EM_DSP24         Machine = 136
+	EM_VIDEOCORE3    Machine = 137
+	EM_LATTICEMICO32 Machine = 138
+	EM_SE_C17        Machine = 139
+	EM_TI_C6000      Machine = 140
+	EM_TI_C2000      Machine = 141
+	EM_TI_C5500      Machine = 142
+	EM_TI_ARP32      Machine = 143
+	EM_TI_PRU        Machine = 144
+	EM_MMDSP_PLUS    Machine = 160
+	EM_CYPRESS_M8C   Machine = 161
+	EM_R32C          Machine = 162
+	EM_TRIMEDIA      Machine = 163
+	EM_QDSP6         Machine = 164
+	EM_8051          Machine = 165
+	EM_STXP7X        Machine = 166
+	EM_NDS32         Machine = 167
+	EM_ECOG1X        Machine = 168
+	EM_MAXQ30        Machine = 169
+	EM_XIMO16        Machine = 170
+	EM_MANIK         Machine = 171
+	EM_CRAYNV2       Machine = 172
+	EM_RX            Machine = 173
+	EM_METAG         Machine = 174
+	EM_MCST_ELBRUS   Machine = 175
+	EM_ECOG16        Machine = 176
+	EM_CR16          Machine = 177
+	EM_ETPU          Machine = 178
+	EM_SLE9X         Machine = 179
+	EM_L10M          Machine = 180
+	EM_K10M          Machine = 181
+	EM_AARCH64       Machine = 183
+	EM_AVR32         Machine = 185
+	EM_STM8          Machine = 186
+	EM_TILE64        Machine = 187
+	EM_TILEPRO       Machine = 188
+	EM_MICROBLAZE    Machine = 189
+	EM_CUDA          Machine = 190
+	EM_TILEGX        Machine = 191
+	EM_CLOUDSHIELD   Machine = 192
+	EM_COREA_1ST     Machine = 193
+	EM_COREA_2ND     Machine = 194
+	EM_ARCV2         Machine = 195
+	EM_OPEN8         Machine = 196
+	EM_RL78          Machine = 197
+	EM_VIDEOCORE5    Machine = 198
+	EM_78KOR         Machine = 199
+	EM_56800EX       Machine = 200
+	EM_BA1           Machine = 201
+	EM_BA2           Machine = 202
+	EM_XCORE         Machine = 203
+	EM_MCHP_PIC      Machine = 204
+	EM_INTELGT       Machine = 205
+	EM_KM32          Machine = 210
+	EM_KMX32         Machine = 211
+	EM_KMX16         Machine = 212
+	EM_KMX8          Machine = 213
+	EM_KVARC         Machine = 214
+	EM_CDP           Machine = 215
+	EM_COGE          Machine = 216
+	EM_COOL          Machine = 217
+	EM_NORC          Machine = 218
+	EM_CSR_KALIMBA   Machine = 219
+	EM_Z80           Machine = 220
+	EM_VISIUM        Machine = 221
+	EM_FT32          Machine = 222
+	EM_MOXIE         Machine = 223
+	EM_AMDGPU        Machine = 224
+	EM_RISCV         Machine = 243
+	EM_BPF           Machine = 247
+	EM_CSKY          Machine = 252
+	EM_LOONGARCH     Machine = 258
+
+	// EM_ALPHA is the unofficial value used by Linux for Alpha.
+	EM_ALPHA Machine = 0x9026
 )
 
 type SectionHeaderType uint32
@@ -65,12 +319,42 @@ const (
 	SHT_PREINIT_ARRAY SectionHeaderType = 16
 	SHT_GROUP         SectionHeaderType = 17
 	SHT_SYMTAB_SHNDX  SectionHeaderType = 18
+	SHT_RELR          SectionHeaderType = 19
 	SHT_LOOS          SectionHeaderType = 0x60000000
 	SHT_HIOS          SectionHeaderType = 0x6fffffff
-	SHT_LOPROC        SectionHeaderType = 0x70000000
-	SHT_HIPROC        SectionHeaderType = 0x7fffffff
-	SHT_LOUSER        SectionHeaderType = 0x80000000
-	SHT_HIUSER        SectionHeaderType = 0xffffffff
+
+	SHT_ANDROID_REL              SectionHeaderType = 0x60000001
+	SHT_ANDROID_RELA             SectionHeaderType = 0x60000002
+	SHT_GNU_INCREMENTAL_INPUTS   SectionHeaderType = 0x6fff4700
+	SHT_LLVM_ODRTAB              SectionHeaderType = 0x6fff4c00
+	SHT_LLVM_LINKER_OPTIONS      SectionHeaderType = 0x6fff4c01
+	SHT_LLVM_ADDRSIG             SectionHeaderType = 0x6fff4c03
+	SHT_LLVM_DEPENDENT_LIBRARIES SectionHeaderType = 0x6fff4c04
+	SHT_LLVM_SYMPART             SectionHeaderType = 0x6fff4c05
+	SHT_LLVM_PART_EHDR           SectionHeaderType = 0x6fff4c06
+	SHT_LLVM_PART_PHDR           SectionHeaderType = 0x6fff4c07
+	SHT_LLVM_BB_ADDR_MAP_V0      SectionHeaderType = 0x6fff4c08
+	SHT_LLVM_CALL_GRAPH_PROFILE  SectionHeaderType = 0x6fff4c09
+	SHT_LLVM_BB_ADDR_MAP         SectionHeaderType = 0x6fff4c0a
+	SHT_LLVM_OFFLOADING          SectionHeaderType = 0x6fff4c0b
+	SHT_LLVM_LTO                 SectionHeaderType = 0x6fff4c0c
+	SHT_ANDROID_RELR             SectionHeaderType = 0x6fffff00
+	SHT_GNU_SFRAME               SectionHeaderType = 0x6ffffff4
+	SHT_GNU_ATTRIBUTES           SectionHeaderType = 0x6ffffff5
+	SHT_GNU_HASH                 SectionHeaderType = 0x6ffffff6
+	SHT_GNU_LIBLIST              SectionHeaderType = 0x6ffffff7
+	SHT_CHECKSUM                 SectionHeaderType = 0x6ffffff8
+	SHT_SUNW_move                SectionHeaderType = 0x6ffffffa
+	SHT_SUNW_COMDAT              SectionHeaderType = 0x6ffffffb
+	SHT_SUNW_syminfo             SectionHeaderType = 0x6ffffffc
+	SHT_GNU_verdef               SectionHeaderType = 0x6ffffffd
+	SHT_GNU_verneed              SectionHeaderType = 0x6ffffffe
+	SHT_GNU_versym               SectionHeaderType = 0x6fffffff
+
+	SHT_LOPROC SectionHeaderType = 0x70000000
+	SHT_HIPROC SectionHeaderType = 0x7fffffff
+	SHT_LOUSER SectionHeaderType = 0x80000000
+	SHT_HIUSER SectionHeaderType = 0xffffffff
 )
 
 type SectionFlag uint64
@@ -87,12 +371,25 @@ const (
 	SHF_GROUP            SectionFlag = 0x200
 	SHF_TLS              SectionFlag = 0x400
 	SHF_COMPRESSED       SectionFlag = 0x800
+	SHF_GNU_RETAIN       SectionFlag = 0x200000
 	SHF_MASKOS           SectionFlag = 0x0ff00000
+	SHF_GNU_MBIND        SectionFlag = 0x01000000
 	SHF_MASKPROC         SectionFlag = 0xf0000000
 	SHF_ORDERED          SectionFlag = 0x40000000
 	SHF_EXCLUDE          SectionFlag = 0x80000000
 )
 
+type CompressionType uint32
+
+const (
+	ELFCOMPRESS_ZLIB   CompressionType = 1
+	ELFCOMPRESS_ZSTD   CompressionType = 2
+	ELFCOMPRESS_LOOS   CompressionType = 0x60000000
+	ELFCOMPRESS_HIOS   CompressionType = 0x6fffffff
+	ELFCOMPRESS_LOPROC CompressionType = 0x70000000
+	ELFCOMPRESS_HIPROC CompressionType = 0x7fffffff
+)
+
 type ProgramHeaderType uint32
 
 const (
@@ -109,9 +406,23 @@ const (
 	PT_GNU_EH_FRAME ProgramHeaderType = 0x6474e550
 	PT_GNU_STACK    ProgramHeaderType = 0x6474e551
 	PT_GNU_RELRO    ProgramHeaderType = 0x6474e552
+	PT_GNU_PROPERTY ProgramHeaderType = 0x6474e553
+	PT_GNU_SFRAME   ProgramHeaderType = 0x6474e554
+	PT_GNU_MBIND_LO ProgramHeaderType = 0x6474e555
+	PT_GNU_MBIND_HI ProgramHeaderType = 0x6474f554
+	PT_SUNW_UNWIND  ProgramHeaderType = 0x6464e550
+	PT_SUNWBSS      ProgramHeaderType = 0x6ffffffa
+	PT_SUNWSTACK    ProgramHeaderType = 0x6ffffffb
 	PT_HIOS         ProgramHeaderType = 0x6fffffff
-	PT_LOPROC       ProgramHeaderType = 0x70000000
-	PT_HIPROC       ProgramHeaderType = 0x7fffffff
+
+	PT_OPENBSD_MUTABLE   ProgramHeaderType = 0x65a3dbe5
+	PT_OPENBSD_RANDOMIZE ProgramHeaderType = 0x65a3dbe6
+	PT_OPENBSD_WXNEEDED  ProgramHeaderType = 0x65a3dbe7
+	PT_OPENBSD_NOBTCFI   ProgramHeaderType = 0x65a3dbe8
+	PT_OPENBSD_SYSCALLS  ProgramHeaderType = 0x65a3dbe9
+	PT_OPENBSD_BOOTDATA  ProgramHeaderType = 0x65a41be6
+	PT_LOPROC            ProgramHeaderType = 0x70000000
+	PT_HIPROC            ProgramHeaderType = 0x7fffffff
 )
 
 type ProgramFlag uint32
@@ -123,3 +434,302 @@ const (
 	PF_MASKOS   ProgramFlag = 0x0ff00000
 	PF_MASKPROC ProgramFlag = 0xf0000000
 )
+
+type SymbolBind uint8
+
+const (
+	STB_LOCAL      SymbolBind = 0
+	STB_GLOBAL     SymbolBind = 1
+	STB_WEAK       SymbolBind = 2
+	STB_GNU_UNIQUE SymbolBind = 10
+	STB_LOOS       SymbolBind = 10
+	STB_HIOS       SymbolBind = 12
+	STB_LOPROC     SymbolBind = 13
+	STB_HIPROC     SymbolBind = 15
+)
+
+type SymbolType uint8
+
+const (
+	STT_NOTYPE    SymbolType = 0
+	STT_OBJECT    SymbolType = 1
+	STT_FUNC      SymbolType = 2
+	STT_SECTION   SymbolType = 3
+	STT_FILE      SymbolType = 4
+	STT_COMMON    SymbolType = 5
+	STT_TLS       SymbolType = 6
+	STT_GNU_IFUNC SymbolType = 10
+	STT_LOOS      SymbolType = 10
+	STT_HIOS      SymbolType = 12
+	STT_LOPROC    SymbolType = 13
+	STT_HIPROC    SymbolType = 15
+)
+
+type SymbolVisibility uint8
+
+const (
+	STV_DEFAULT   SymbolVisibility = 0
+	STV_INTERNAL  SymbolVisibility = 1
+	STV_HIDDEN    SymbolVisibility = 2
+	STV_PROTECTED SymbolVisibility = 3
+)
+
+type DynTag int64
+
+const (
+	DT_NULL            DynTag = 0
+	DT_NEEDED          DynTag = 1
+	DT_PLTRELSZ        DynTag = 2
+	DT_PLTGOT          DynTag = 3
+	DT_HASH            DynTag = 4
+	DT_STRTAB          DynTag = 5
+	DT_SYMTAB          DynTag = 6
+	DT_RELA            DynTag = 7
+	DT_RELASZ          DynTag = 8
+	DT_RELAENT         DynTag = 9
+	DT_STRSZ           DynTag = 10
+	DT_SYMENT          DynTag = 11
+	DT_INIT            DynTag = 12
+	DT_FINI            DynTag = 13
+	DT_SONAME          DynTag = 14
+	DT_RPATH           DynTag = 15
+	DT_SYMBOLIC        DynTag = 16
+	DT_REL             DynTag = 17
+	DT_RELSZ           DynTag = 18
+	DT_RELENT          DynTag = 19
+	DT_PLTREL          DynTag = 20
+	DT_DEBUG           DynTag = 21
+	DT_TEXTREL         DynTag = 22
+	DT_JMPREL          DynTag = 23
+	DT_BIND_NOW        DynTag = 24
+	DT_INIT_ARRAY      DynTag = 25
+	DT_FINI_ARRAY      DynTag = 26
+	DT_INIT_ARRAYSZ    DynTag = 27
+	DT_FINI_ARRAYSZ    DynTag = 28
+	DT_RUNPATH         DynTag = 29
+	DT_FLAGS           DynTag = 30
+	DT_ENCODING        DynTag = 32
+	DT_PREINIT_ARRAY   DynTag = 32
+	DT_PREINIT_ARRAYSZ DynTag = 33
+	DT_SYMTAB_SHNDX    DynTag = 34
+	DT_RELRSZ          DynTag = 35
+	DT_RELR            DynTag = 36
+	DT_RELRENT         DynTag = 37
+	DT_LOOS            DynTag = 0x6000000d
+	DT_HIOS            DynTag = 0x6ffff000
+	DT_VALRNGLO        DynTag = 0x6ffffd00
+	DT_GNU_PRELINKED   DynTag = 0x6ffffdf5
+	DT_GNU_CONFLICTSZ  DynTag = 0x6ffffdf6
+	DT_GNU_LIBLISTSZ   DynTag = 0x6ffffdf7
+	DT_CHECKSUM        DynTag = 0x6ffffdf8
+	DT_PLTPADSZ        DynTag = 0x6ffffdf9
+	DT_MOVEENT         DynTag = 0x6ffffdfa
+	DT_MOVESZ          DynTag = 0x6ffffdfb
+	DT_FEATURE_1       DynTag = 0x6ffffdfc
+	DT_POSFLAG_1       DynTag = 0x6ffffdfd
+	DT_SYMINSZ         DynTag = 0x6ffffdfe
+	DT_SYMINENT        DynTag = 0x6ffffdff
+	DT_VALRNGHI        DynTag = 0x6ffffdff
+	DT_ADDRRNGLO       DynTag = 0x6ffffe00
+	DT_GNU_HASH        DynTag = 0x6ffffef5
+	DT_TLSDESC_PLT     DynTag = 0x6ffffef6
+	DT_TLSDESC_GOT     DynTag = 0x6ffffef7
+	DT_GNU_CONFLICT    DynTag = 0x6ffffef8
+	DT_GNU_LIBLIST     DynTag = 0x6ffffef9
+	DT_CONFIG          DynTag = 0x6ffffefa
+	DT_DEPAUDIT        DynTag = 0x6ffffefb
+	DT_AUDIT           DynTag = 0x6ffffefc
+	DT_PLTPAD          DynTag = 0x6ffffefd
+	DT_MOVETAB         DynTag = 0x6ffffefe
+	DT_SYMINFO         DynTag = 0x6ffffeff
+	DT_ADDRRNGHI       DynTag = 0x6ffffeff
+	DT_VERSYM          DynTag = 0x6ffffff0
+	DT_RELACOUNT       DynTag = 0x6ffffff9
+	DT_RELCOUNT        DynTag = 0x6ffffffa
+	DT_FLAGS_1         DynTag = 0x6ffffffb
+	DT_VERDEF          DynTag = 0x6ffffffc
+	DT_VERDEFNUM       DynTag = 0x6ffffffd
+	DT_VERNEED         DynTag = 0x6ffffffe
+	DT_VERNEEDNUM      DynTag = 0x6fffffff
+	DT_LOPROC          DynTag = 0x70000000
+	DT_AUXILIARY       DynTag = 0x7ffffffd
+	DT_FILTER          DynTag = 0x7fffffff
+	DT_HIPROC          DynTag = 0x7fffffff
+)
+
+type DynFlag uint64
+
+const (
+	DF_ORIGIN     DynFlag = 0x1
+	DF_SYMBOLIC   DynFlag = 0x2
+	DF_TEXTREL    DynFlag = 0x4
+	DF_BIND_NOW   DynFlag = 0x8
+	DF_STATIC_TLS DynFlag = 0x10
+)
+
+type DynFlag1 uint64
+
+const (
+	DF_1_NOW        DynFlag1 = 0x1
+	DF_1_GLOBAL     DynFlag1 = 0x2
+	DF_1_GROUP      DynFlag1 = 0x4
+	DF_1_NODELETE   DynFlag1 = 0x8
+	DF_1_LOADFLTR   DynFlag1 = 0x10
+	DF_1_INITFIRST  DynFlag1 = 0x20
+	DF_1_NOOPEN     DynFlag1 = 0x40
+	DF_1_ORIGIN     DynFlag1 = 0x80
+	DF_1_DIRECT     DynFlag1 = 0x100
+	DF_1_TRANS      DynFlag1 = 0x200
+	DF_1_INTERPOSE  DynFlag1 = 0x400
+	DF_1_NODEFLIB   DynFlag1 = 0x800
+	DF_1_NODUMP     DynFlag1 = 0x1000
+	DF_1_CONFALT    DynFlag1 = 0x2000
+	DF_1_ENDFILTEE  DynFlag1 = 0x4000
+	DF_1_DISPRELDNE DynFlag1 = 0x8000
+	DF_1_DISPRELPND DynFlag1 = 0x10000
+	DF_1_NODIRECT   DynFlag1 = 0x20000
+	DF_1_IGNMULDEF  DynFlag1 = 0x40000
+	DF_1_NOKSYMS    DynFlag1 = 0x80000
+	DF_1_NOHDR      DynFlag1 = 0x100000
+	DF_1_EDITED     DynFlag1 = 0x200000
+	DF_1_NORELOC    DynFlag1 = 0x400000
+	DF_1_SYMINTPOSE DynFlag1 = 0x800000
+	DF_1_GLOBAUDIT  DynFlag1 = 0x1000000
+	DF_1_SINGLETON  DynFlag1 = 0x2000000
+	DF_1_STUB       DynFlag1 = 0x4000000
+	DF_1_PIE        DynFlag1 = 0x8000000
+)
+
+type VersionFlag uint16
+
+const (
+	VER_FLG_BASE VersionFlag = 0x1
+	VER_FLG_WEAK VersionFlag = 0x2
+	VER_FLG_INFO VersionFlag = 0x4
+)
+
+// Reserved version indices of SHT_GNU_versym entries, whose top bit
+// VERSYM_HIDDEN marks symbols that cannot be bound to by default.
+const (
+	VER_NDX_LOCAL  = 0
+	VER_NDX_GLOBAL = 1
+	VERSYM_HIDDEN  = 0x8000
+)
+
+const (
+	ELF_NOTE_GNU   = "GNU"
+	ELF_NOTE_GO    = "Go"
+	ELF_NOTE_FDO   = "FDO"
+	ELF_NOTE_CORE  = "CORE"
+	ELF_NOTE_LINUX = "LINUX"
+)
+
+type NoteType uint32
+
+const (
+	NT_GNU_ABI_TAG            NoteType = 1
+	NT_GNU_HWCAP              NoteType = 2
+	NT_GNU_BUILD_ID           NoteType = 3
+	NT_GNU_GOLD_VERSION       NoteType = 4
+	NT_GNU_PROPERTY_TYPE_0    NoteType = 5
+	NT_GO_BUILDID             NoteType = 4
+	NT_FDO_PACKAGING_METADATA NoteType = 0xcafe1a7e
+
+	// Notes of core files, owned by CORE or LINUX.
+	NT_PRSTATUS   NoteType = 1
+	NT_FPREGSET   NoteType = 2
+	NT_PRPSINFO   NoteType = 3
+	NT_TASKSTRUCT NoteType = 4
+	NT_AUXV       NoteType = 6
+	NT_X86_XSTATE NoteType = 0x202
+	NT_ARM_VFP    NoteType = 0x400
+	NT_SIGINFO    NoteType = 0x53494749
+	NT_FILE       NoteType = 0x46494c45
+	NT_PRXFPREG   NoteType = 0x46e62b7f
+)
+
+type AuxType uint64
+
+const (
+	AT_NULL              AuxType = 0
+	AT_IGNORE            AuxType = 1
+	AT_EXECFD            AuxType = 2
+	AT_PHDR              AuxType = 3
+	AT_PHENT             AuxType = 4
+	AT_PHNUM             AuxType = 5
+	AT_PAGESZ            AuxType = 6
+	AT_BASE              AuxType = 7
+	AT_FLAGS             AuxType = 8
+	AT_ENTRY             AuxType = 9
+	AT_NOTELF            AuxType = 10
+	AT_UID               AuxType = 11
+	AT_EUID              AuxType = 12
+	AT_GID               AuxType = 13
+	AT_EGID              AuxType = 14
+	AT_PLATFORM          AuxType = 15
+	AT_HWCAP             AuxType = 16
+	AT_CLKTCK            AuxType = 17
+	AT_SECURE            AuxType = 23
+	AT_BASE_PLATFORM     AuxType = 24
+	AT_RANDOM            AuxType = 25
+	AT_HWCAP2            AuxType = 26
+	AT_RSEQ_FEATURE_SIZE AuxType = 27
+	AT_RSEQ_ALIGN        AuxType = 28
+	AT_HWCAP3            AuxType = 29
+	AT_HWCAP4            AuxType = 30
+	AT_EXECFN            AuxType = 31
+	AT_SYSINFO           AuxType = 32
+	AT_SYSINFO_EHDR      AuxType = 33
+	AT_MINSIGSTKSZ       AuxType = 51
+)
+
+type GNUABIOS uint32
+
+const (
+	ELF_NOTE_OS_LINUX    GNUABIOS = 0
+	ELF_NOTE_OS_GNU      GNUABIOS = 1
+	ELF_NOTE_OS_SOLARIS2 GNUABIOS = 2
+	ELF_NOTE_OS_FREEBSD  GNUABIOS = 3
+)
+
+type GNUPropertyType uint32
+
+const (
+	GNU_PROPERTY_STACK_SIZE            GNUPropertyType = 1
+	GNU_PROPERTY_NO_COPY_ON_PROTECTED  GNUPropertyType = 2
+	GNU_PROPERTY_1_NEEDED              GNUPropertyType = 0xb0008000
+	GNU_PROPERTY_LOPROC                GNUPropertyType = 0xc0000000
+	GNU_PROPERTY_AARCH64_FEATURE_1_AND GNUPropertyType = 0xc0000000
+	GNU_PROPERTY_X86_FEATURE_1_AND     GNUPropertyType = 0xc0000002
+	GNU_PROPERTY_X86_ISA_1_NEEDED      GNUPropertyType = 0xc0008002
+	GNU_PROPERTY_X86_FEATURE_2_NEEDED  GNUPropertyType = 0xc0008001
+	GNU_PROPERTY_X86_ISA_1_USED        GNUPropertyType = 0xc0010002
+	GNU_PROPERTY_X86_FEATURE_2_USED    GNUPropertyType = 0xc0010001
+	GNU_PROPERTY_HIPROC                GNUPropertyType = 0xdfffffff
+)
+
+type X86Feature1 uint32
+
+const (
+	GNU_PROPERTY_X86_FEATURE_1_IBT     X86Feature1 = 1 << 0
+	GNU_PROPERTY_X86_FEATURE_1_SHSTK   X86Feature1 = 1 << 1
+	GNU_PROPERTY_X86_FEATURE_1_LAM_U48 X86Feature1 = 1 << 2
+	GNU_PROPERTY_X86_FEATURE_1_LAM_U57 X86Feature1 = 1 << 3
+)
+
+type X86ISA1 uint32
+
+const (
+	GNU_PROPERTY_X86_ISA_1_BASELINE X86ISA1 = 1 << 0
+	GNU_PROPERTY_X86_ISA_1_V2       X86ISA1 = 1 << 1
+	GNU_PROPERTY_X86_ISA_1_V3       X86ISA1 = 1 << 2
+	GNU_PROPERTY_X86_ISA_1_V4       X86ISA1 = 1 << 3
+)
+
+type AArch64Feature1 uint32
+
+const (
+	GNU_PROPERTY_AARCH64_FEATURE_1_BTI AArch64Feature1 = 1 << 0
+	GNU_PROPERTY_AARCH64_FEATURE_1_PAC AArch64Feature1 = 1 << 1
+	GNU_PROPERTY_AARCH64_FEATURE_1_GCS AArch64Feature1 = 1 << 2
+)
@@ -0,0 +1,526 @@
+package elf
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// CoreFile is an ET_CORE file, the memory image of a process the kernel
+// dumped along with the state of its threads. The methods of File still
+// describe the file itself; ReadAt and ReadAddr read the memory of the
+// process instead.
+type CoreFile struct {
+	*File
+	// Threads holds a thread for every NT_PRSTATUS note, starting with the
+	// one that received the fatal signal.
+	Threads []*CoreThread
+	// Process is the NT_PRPSINFO note, if there is one.
+	Process *ProcessInfo
+	// Auxv is the auxiliary vector of the process, from the NT_AUXV note.
+	Auxv []*AuxEntry
+	// Mappings lists the files mapped into the process, from the NT_FILE
+	// note.
+	Mappings []*FileMapping
+	// Diagnostics lists the notes NewCoreFile left out because they could
+	// not be decoded. The problems found while parsing the file itself are
+	// in File.Diagnostics.
+	Diagnostics []Diagnostic
+}
+
+// CoreThread is the state of a thread as recorded by its NT_PRSTATUS note and
+// the notes that follow it.
+type CoreThread struct {
+	PID, PPID, PGRP, SID int
+	// Signal is the current signal of the thread, and SigInfo the NT_SIGINFO
+	// note describing it for the thread that received the fatal signal.
+	Signal     int
+	SigInfo    *SigInfo
+	SigPending uint64
+	SigHeld    uint64
+
+	UserTime, SystemTime   time.Duration
+	CUserTime, CSystemTime time.Duration
+
+	// Registers holds the general registers in the order of the kernel's
+	// user_regs_struct. It is nil for machines other than x86-64, AArch64,
+	// i386 and ARM.
+	Registers []Register
+	// FPRegisters is the NT_FPREGSET note of the thread, if there is one.
+	FPRegisters *FPRegisters
+
+	layout *registerLayout
+}
+
+// Register is a general register of a thread.
+type Register struct {
+	Name  string
+	Value uint64
+}
+
+// SigInfo is the siginfo_t of a NT_SIGINFO note. Addr is set for the
+// signals raised by faults, and PID and UID for signals sent by a process.
+type SigInfo struct {
+	Signo, Errno, Code int32
+	Addr               uint64
+	PID, UID           int
+}
+
+// ProcessInfo is the descriptor of a NT_PRPSINFO note.
+type ProcessInfo struct {
+	// State is the state of the process as a letter, such as R for running.
+	State    byte
+	Zombie   bool
+	Nice     int8
+	Flags    uint64
+	UID, GID uint32
+	PID      int
+	PPID     int
+	PGRP     int
+	SID      int
+	// Name is the name of the executable, truncated to 15 bytes, and Args
+	// the start of its command line.
+	Name string
+	Args string
+}
+
+// AuxEntry is an entry of the auxiliary vector.
+type AuxEntry struct {
+	Type  AuxType
+	Value uint64
+}
+
+// FileMapping is a range of memory mapped from a file, as listed by the
+// NT_FILE note.
+type FileMapping struct {
+	Start, End uint64
+	// Offset is the offset of the file Start maps.
+	Offset uint64
+	Path   string
+}
+
+// FPRegisters is a NT_FPREGSET note. Its layout depends on the machine, so
+// Raw holds the whole descriptor; the fields are decoded for x86-64 and
+// i386, where Control and Status are the x87 control and status words, and
+// for AArch64, where they are FPCR and FPSR.
+type FPRegisters struct {
+	Control, Status uint32
+	MXCSR           uint32
+	// X87 holds the 80-bit stack registers st0-st7.
+	X87 [][]byte
+	// Vector holds the 128-bit registers xmm0-xmm15 or v0-v31.
+	Vector [][]byte
+	Raw    []byte
+}
+
+// registerLayout names the general registers of a machine and the ones
+// holding the program counter and the stack pointer.
+type registerLayout struct {
+	names  []string
+	pc, sp string
+}
+
+var coreRegisterLayouts = map[Machine]*registerLayout{
+	EM_X86_64: {
+		names: []string{
+			"r15", "r14", "r13", "r12", "rbp", "rbx", "r11", "r10", "r9", "r8",
+			"rax", "rcx", "rdx", "rsi", "rdi", "orig_rax", "rip", "cs", "eflags",
+			"rsp", "ss", "fs_base", "gs_base", "ds", "es", "fs", "gs",
+		},
+		pc: "rip", sp: "rsp",
+	},
+	EM_AARCH64: {
+		names: []string{
+			"x0", "x1", "x2", "x3", "x4", "x5", "x6", "x7", "x8", "x9", "x10",
+			"x11", "x12", "x13", "x14", "x15", "x16", "x17", "x18", "x19", "x20",
+			"x21", "x22", "x23", "x24", "x25", "x26", "x27", "x28", "x29", "x30",
+			"sp", "pc", "pstate",
+		},
+		pc: "pc", sp: "sp",
+	},
+	EM_386: {
+		names: []string{
+			"ebx", "ecx", "edx", "esi", "edi", "ebp", "eax", "ds", "es", "fs",
+			"gs", "orig_eax", "eip", "cs", "eflags", "esp", "ss",
+		},
+		pc: "eip", sp: "esp",
+	},
+	EM_ARM: {
+		names: []string{
+			"r0", "r1", "r2", "r3", "r4", "r5", "r6", "r7", "r8", "r9", "r10",
+			"r11", "r12", "sp", "lr", "pc", "cpsr", "orig_r0",
+		},
+		pc: "pc", sp: "sp",
+	},
+}
+
+// NewCoreFile decodes the notes of the core file e. Cores of crashed
+// processes are often damaged, so a note that cannot be decoded is left out
+// and recorded in the Diagnostics of the CoreFile, and the notes of a
+// PT_NOTE segment before the first malformed one are kept.
+func NewCoreFile(e *File) (*CoreFile, error) {
+	if e.Header.Type != ET_CORE {
+		return nil, fmt.Errorf("not a core file: type %d", e.Header.Type)
+	}
+
+	type segmentNote struct {
+		*Note
+		segment *Segment
+	}
+	c := &CoreFile{File: e}
+	var ns []segmentNote
+	for _, sg := range e.SegmentsByType(PT_NOTE) {
+		sgns, err := e.SegmentNotes(sg)
+		if err != nil {
+			c.Diagnostics = append(c.Diagnostics, errorDiagnostic(sg.Header.Offset, err))
+		}
+		for _, n := range sgns {
+			ns = append(ns, segmentNote{n, sg})
+		}
+	}
+
+	var thread *CoreThread
+	for _, sn := range ns {
+		n := sn.Note
+		if n.Name != ELF_NOTE_CORE {
+			continue
+		}
+
+		var err error
+		switch n.Type {
+		case NT_PRSTATUS:
+			if thread, err = c.decodePRStatus(n.Desc); err == nil {
+				c.Threads = append(c.Threads, thread)
+			}
+		case NT_FPREGSET:
+			if thread != nil {
+				thread.FPRegisters, err = c.decodeFPRegisters(n.Desc)
+			}
+		case NT_SIGINFO:
+			if thread != nil {
+				thread.SigInfo, err = c.decodeSigInfo(n.Desc)
+			}
+		case NT_PRPSINFO:
+			c.Process, err = c.decodePRPSInfo(n.Desc)
+		case NT_AUXV:
+			c.Auxv = c.decodeAuxv(n.Desc)
+		case NT_FILE:
+			c.Mappings, err = c.decodeFileMappings(n.Desc)
+		}
+		if err != nil {
+			err = fmt.Errorf("invalid %s note: %w", n.Type, err)
+			c.Diagnostics = append(c.Diagnostics, errorDiagnostic(sn.segment.Header.Offset, err))
+		}
+	}
+
+	return c, nil
+}
+
+// PC returns the program counter of the thread.
+func (t *CoreThread) PC() uint64 {
+	if t.layout == nil {
+		return 0
+	}
+	v, _ := t.Register(t.layout.pc)
+	return v
+}
+
+// SP returns the stack pointer of the thread.
+func (t *CoreThread) SP() uint64 {
+	if t.layout == nil {
+		return 0
+	}
+	v, _ := t.Register(t.layout.sp)
+	return v
+}
+
+// Register returns the value of the general register named name.
+func (t *CoreThread) Register(name string) (uint64, bool) {
+	for _, r := range t.Registers {
+		if r.Name == name {
+			return r.Value, true
+		}
+	}
+
+	return 0, false
+}
+
+// ReadAt reads len(p) bytes of the memory of the process starting at addr,
+// making the core file an io.ReaderAt over that memory.
+// Unlike File.ReadAddr, only the memory the kernel dumped can be read: it
+// fails for the parts of the PT_LOAD segments beyond their Filesz, which
+// are left out of the core rather than zero.
+func (c *CoreFile) ReadAt(p []byte, addr int64) (int, error) {
+	if addr < 0 {
+		return 0, fmt.Errorf("invalid address: %d", addr)
+	}
+
+	b, err := c.readDumped(p[:0], uint64(addr), uint64(len(p)))
+	return len(b), err
+}
+
+// ReadAddr returns n bytes of the memory of the process at addr, like
+// ReadAt.
+func (c *CoreFile) ReadAddr(addr, n uint64) ([]byte, error) {
+	return c.readDumped(nil, addr, n)
+}
+
+func (c *CoreFile) readDumped(buf []byte, addr, n uint64) ([]byte, error) {
+	for n > 0 {
+		sg := c.SegmentForAddr(addr)
+		if sg == nil {
+			return buf, fmt.Errorf("address %#x is not mapped by any PT_LOAD segment", addr)
+		}
+
+		h := sg.Header
+		rel := addr - h.Vaddr
+		if rel >= h.Filesz {
+			return buf, fmt.Errorf("address %#x is not included in the core", addr)
+		}
+		chunk := min(n, h.Filesz-rel)
+		b, err := c.readAt(h.Offset+rel, chunk)
+		if err != nil {
+			return buf, fmt.Errorf("failed to read address %#x: %w", addr, err)
+		}
+		buf = append(buf, b...)
+
+		addr += chunk
+		n -= chunk
+	}
+
+	return buf, nil
+}
+
+// coreWords decodes the machine words of a core note.
+type coreWords struct {
+	e    *File
+	desc []byte
+}
+
+func (w coreWords) size() uint64 {
+	if w.e.is32() {
+		return 4
+	}
+	return 8
+}
+
+func (w coreWords) word(off uint64) uint64 {
+	if w.e.is32() {
+		return uint64(w.e.Endianness.Uint32(w.desc[off:]))
+	}
+	return w.e.Endianness.Uint64(w.desc[off:])
+}
+
+func (w coreWords) int32(off uint64) int32 {
+	return int32(w.e.Endianness.Uint32(w.desc[off:]))
+}
+
+func (w coreWords) timeval(off uint64) time.Duration {
+	return time.Duration(w.word(off))*time.Second + time.Duration(w.word(off+w.size()))*time.Microsecond
+}
+
+// decodePRStatus decodes a struct elf_prstatus: a siginfo header, the
+// signal masks, the process ids and times, then the general registers.
+func (c *CoreFile) decodePRStatus(desc []byte) (*CoreThread, error) {
+	w := coreWords{c.File, desc}
+	ws := w.size()
+	regsOff := 32 + 10*ws
+	if uint64(len(desc)) < regsOff {
+		return nil, fmt.Errorf("prstatus note too short: %d bytes", len(desc))
+	}
+
+	t := &CoreThread{
+		Signal:      int(c.Endianness.Uint16(desc[12:])),
+		SigPending:  w.word(16),
+		SigHeld:     w.word(16 + ws),
+		PID:         int(w.int32(16 + 2*ws)),
+		PPID:        int(w.int32(20 + 2*ws)),
+		PGRP:        int(w.int32(24 + 2*ws)),
+		SID:         int(w.int32(28 + 2*ws)),
+		UserTime:    w.timeval(32 + 2*ws),
+		SystemTime:  w.timeval(32 + 4*ws),
+		CUserTime:   w.timeval(32 + 6*ws),
+		CSystemTime: w.timeval(32 + 8*ws),
+	}
+
+	l := coreRegisterLayouts[c.Header.Machine]
+	if l == nil {
+		return t, nil
+	}
+	if uint64(len(desc)) < regsOff+uint64(len(l.names))*ws {
+		return nil, fmt.Errorf("prstatus note too short for %d registers: %d bytes", len(l.names), len(desc))
+	}
+	t.layout = l
+	t.Registers = make([]Register, len(l.names))
+	for i, name := range l.names {
+		t.Registers[i] = Register{Name: name, Value: w.word(regsOff + uint64(i)*ws)}
+	}
+
+	return t, nil
+}
+
+// decodePRPSInfo decodes a struct elf_prpsinfo. i386 and ARM use 16-bit user
+// and group ids, which make their descriptor 124 bytes long.
+func (c *CoreFile) decodePRPSInfo(desc []byte) (*ProcessInfo, error) {
+	w := coreWords{c.File, desc}
+	ws := w.size()
+	idSize := uint64(4)
+	if ws == 4 && len(desc) == 124 {
+		idSize = 2
+	}
+	pidOff := 4 + ws + 2*idSize
+	if ws == 8 {
+		pidOff += 4 // pr_flag is aligned
+	}
+	if uint64(len(desc)) < pidOff+16+16+80 {
+		return nil, fmt.Errorf("prpsinfo note too short: %d bytes", len(desc))
+	}
+
+	p := &ProcessInfo{
+		State:  desc[1],
+		Zombie: desc[2] != 0,
+		Nice:   int8(desc[3]),
+		Flags:  w.word(pidOff - 2*idSize - ws),
+		PID:    int(w.int32(pidOff)),
+		PPID:   int(w.int32(pidOff + 4)),
+		PGRP:   int(w.int32(pidOff + 8)),
+		SID:    int(w.int32(pidOff + 12)),
+		Name:   cString(desc[pidOff+16 : pidOff+32]),
+		Args:   string(bytes.TrimRight([]byte(cString(desc[pidOff+32:pidOff+112])), " ")),
+	}
+	if idSize == 2 {
+		p.UID = uint32(c.Endianness.Uint16(desc[pidOff-4:]))
+		p.GID = uint32(c.Endianness.Uint16(desc[pidOff-2:]))
+	} else {
+		p.UID = c.Endianness.Uint32(desc[pidOff-8:])
+		p.GID = c.Endianness.Uint32(desc[pidOff-4:])
+	}
+
+	return p, nil
+}
+
+// Signals whose siginfo_t carries the faulting address.
+var faultSignals = map[int32]bool{4: true, 5: true, 7: true, 8: true, 11: true} // SIGILL, SIGTRAP, SIGBUS, SIGFPE, SIGSEGV
+
+// decodeSigInfo decodes a siginfo_t, whose union of signal specific fields
+// follows the signal number, error and code at pointer alignment.
+func (c *CoreFile) decodeSigInfo(desc []byte) (*SigInfo, error) {
+	w := coreWords{c.File, desc}
+	// The union holds a pid and uid, or an address.
+	off := max(12, w.size()*2)
+	if uint64(len(desc)) < off+max(8, w.size()) {
+		return nil, fmt.Errorf("siginfo note too short: %d bytes", len(desc))
+	}
+
+	si := &SigInfo{Signo: w.int32(0), Errno: w.int32(4), Code: w.int32(8)}
+	switch {
+	case si.Code <= 0:
+		si.PID = int(w.int32(off))
+		si.UID = int(c.Endianness.Uint32(desc[off+4:]))
+	case faultSignals[si.Signo]:
+		si.Addr = w.word(off)
+	}
+
+	return si, nil
+}
+
+func (c *CoreFile) decodeAuxv(desc []byte) []*AuxEntry {
+	w := coreWords{c.File, desc}
+	ws := w.size()
+
+	var auxv []*AuxEntry
+	for off := uint64(0); off+2*ws <= uint64(len(desc)); off += 2 * ws {
+		typ := AuxType(w.word(off))
+		if typ == AT_NULL {
+			break
+		}
+		auxv = append(auxv, &AuxEntry{Type: typ, Value: w.word(off + ws)})
+	}
+
+	return auxv
+}
+
+// decodeFileMappings decodes a NT_FILE note: the number of mappings and the
+// page size, a start, end and page offset for every mapping, then their
+// paths.
+func (c *CoreFile) decodeFileMappings(desc []byte) ([]*FileMapping, error) {
+	w := coreWords{c.File, desc}
+	ws := w.size()
+	if uint64(len(desc)) < 2*ws {
+		return nil, fmt.Errorf("file note too short: %d bytes", len(desc))
+	}
+	count, pageSize := w.word(0), w.word(ws)
+	if count > (uint64(len(desc))-2*ws)/(3*ws) {
+		return nil, fmt.Errorf("file note with %d mappings exceeds %d bytes", count, len(desc))
+	}
+
+	paths := bytes.Split(desc[2*ws+count*3*ws:], []byte{0})
+	if uint64(len(paths)) < count {
+		return nil, fmt.Errorf("file note has %d paths for %d mappings", len(paths), count)
+	}
+	ms := make([]*FileMapping, count)
+	for i := range ms {
+		off := 2*ws + uint64(i)*3*ws
+		ms[i] = &FileMapping{
+			Start:  w.word(off),
+			End:    w.word(off + ws),
+			Offset: w.word(off+2*ws) * pageSize,
+			Path:   string(paths[i]),
+		}
+	}
+
+	return ms, nil
+}
+
+// decodeFPRegisters decodes the user_fpregs_struct of x86-64, the
+// user_i387_struct of i386 and the user_fpsimd_state of AArch64.
+func (c *CoreFile) decodeFPRegisters(desc []byte) (*FPRegisters, error) {
+	fp := &FPRegisters{Raw: desc}
+	regs := func(off uint64, n int, size uint64) [][]byte {
+		rs := make([][]byte, n)
+		for i := range rs {
+			rs[i] = desc[off+uint64(i)*16 : off+uint64(i)*16+size]
+		}
+		return rs
+	}
+
+	switch c.Header.Machine {
+	case EM_X86_64:
+		if len(desc) < 416 {
+			return nil, fmt.Errorf("fpregset note too short: %d bytes", len(desc))
+		}
+		fp.Control = uint32(c.Endianness.Uint16(desc))
+		fp.Status = uint32(c.Endianness.Uint16(desc[2:]))
+		fp.MXCSR = c.Endianness.Uint32(desc[24:])
+		fp.X87 = regs(32, 8, 10)
+		fp.Vector = regs(160, 16, 16)
+	case EM_386:
+		if len(desc) < 108 {
+			return nil, fmt.Errorf("fpregset note too short: %d bytes", len(desc))
+		}
+		fp.Control = c.Endianness.Uint32(desc)
+		fp.Status = c.Endianness.Uint32(desc[4:])
+		fp.X87 = make([][]byte, 8)
+		for i := range fp.X87 {
+			fp.X87[i] = desc[28+i*10 : 38+i*10]
+		}
+	case EM_AARCH64:
+		if len(desc) < 520 {
+			return nil, fmt.Errorf("fpregset note too short: %d bytes", len(desc))
+		}
+		fp.Vector = regs(0, 32, 16)
+		fp.Status = c.Endianness.Uint32(desc[512:])
+		fp.Control = c.Endianness.Uint32(desc[516:])
+	}
+
+	return fp, nil
+}
+
+// cString returns b up to its first NUL byte.
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+
+	return string(b)
+}
@@ -0,0 +1,314 @@
+package elf_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+const crash = "../testdata/crash_linux_amd64"
+
+func openCore(t *testing.T) *elf.CoreFile {
+	t.Helper()
+
+	zr, err := gzip.NewReader(bytes.NewReader(mustReadFile(t, "../testdata/core_linux_amd64.gz")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := elf.NewCoreFile(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestCoreFile(t *testing.T) {
+	c := openCore(t)
+
+	wantProcess := &elf.ProcessInfo{
+		State: 'R', Flags: 0x400600, PID: 15178, PPID: 15168, PGRP: 15178, SID: 15168,
+		Name: "crash_linux_amd", Args: "./crash_linux_amd64",
+	}
+	if !reflect.DeepEqual(c.Process, wantProcess) {
+		t.Errorf("Process:\n\thave %#v\n\twant %#v\n", c.Process, wantProcess)
+	}
+
+	if len(c.Threads) != 2 {
+		t.Fatalf("have %d threads, want 2", len(c.Threads))
+	}
+	main, idle := c.Threads[0], c.Threads[1]
+	if main.PID != 15178 || idle.PID != 15179 || main.Signal != 11 || idle.PPID != 15168 {
+		t.Errorf("have threads %d and %d with signal %d", main.PID, idle.PID, main.Signal)
+	}
+	if want := (&elf.SigInfo{Signo: 11, Code: 1, Addr: 0x10}); !reflect.DeepEqual(main.SigInfo, want) {
+		t.Errorf("SigInfo:\n\thave %#v\n\twant %#v\n", main.SigInfo, want)
+	}
+	if idle.SigInfo != nil {
+		t.Errorf("only the faulting thread has a siginfo, have %#v", idle.SigInfo)
+	}
+	if len(main.Registers) != 27 || main.Registers[16].Name != "rip" {
+		t.Errorf("have registers %v", main.Registers)
+	}
+	// The thread faulted loading the address 0x10 in main.
+	if rax, ok := main.Register("rax"); !ok || rax != 0x10 {
+		t.Errorf("have rax %#x", rax)
+	}
+	if main.PC() != 0x5638279aa1a7 || main.SP() != 0x7fffb5ddbfb0 {
+		t.Errorf("have pc %#x and sp %#x", main.PC(), main.SP())
+	}
+	if fp := main.FPRegisters; fp == nil || fp.Control != 0x37f || fp.MXCSR != 0x1f80 || len(fp.X87) != 8 || len(fp.Vector) != 16 {
+		t.Errorf("have fp registers %#v", fp)
+	}
+
+	auxv := map[elf.AuxType]uint64{}
+	for _, a := range c.Auxv {
+		auxv[a.Type] = a.Value
+	}
+	if auxv[elf.AT_PAGESZ] != 0x1000 || auxv[elf.AT_PHNUM] != 13 || auxv[elf.AT_ENTRY] != 0x5638279aa070 {
+		t.Errorf("have auxv %v", auxv)
+	}
+
+	if len(c.Mappings) != 15 {
+		t.Fatalf("have %d mappings, want 15", len(c.Mappings))
+	}
+	want := &elf.FileMapping{Start: 0x5638279aa000, End: 0x5638279ab000, Offset: 0x1000, Path: "/root/module/testdata/crash_linux_amd64"}
+	if !reflect.DeepEqual(c.Mappings[1], want) {
+		t.Errorf("mapping 1:\n\thave %#v\n\twant %#v\n", c.Mappings[1], want)
+	}
+
+	// The entry point and the program counter fall where the executable
+	// was mapped.
+	exe, err := elf.New(mustReadFile(t, crash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := c.Mappings[0].Start
+	if auxv[elf.AT_ENTRY]-base != exe.Header.Entry {
+		t.Errorf("have entry %#x, want %#x", auxv[elf.AT_ENTRY]-base, exe.Header.Entry)
+	}
+	if pc := main.PC() - base; pc < 0x116c || pc >= 0x116c+63 {
+		t.Errorf("pc %#x is not in main", pc)
+	}
+}
+
+func TestCoreFileMemory(t *testing.T) {
+	c := openCore(t)
+
+	// The first page of the executable was dumped, with its program
+	// headers.
+	var auxPhdr uint64
+	for _, a := range c.Auxv {
+		if a.Type == elf.AT_PHDR {
+			auxPhdr = a.Value
+		}
+	}
+	phdrs, err := c.ReadAddr(auxPhdr, 56)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := mustReadFile(t, crash)[64:120]; !bytes.Equal(phdrs, want) {
+		t.Errorf("have program header %x, want %x", phdrs, want)
+	}
+
+	p := make([]byte, 8)
+	if n, err := c.ReadAt(p, int64(c.Threads[0].SP())); n != 8 || err != nil {
+		t.Errorf("ReadAt of the stack: %d, %v", n, err)
+	}
+
+	// The text of the executable is mapped from the file and left out.
+	if _, err := c.ReadAddr(c.Threads[0].PC(), 1); err == nil {
+		t.Errorf("reading memory left out of the core should fail")
+	}
+	if _, err := c.ReadAddr(0x10, 1); err == nil {
+		t.Errorf("reading unmapped memory should fail")
+	}
+}
+
+func TestCoreFileMachines(t *testing.T) {
+	for _, tt := range []struct {
+		class   elf.Class
+		order   binary.ByteOrder
+		machine elf.Machine
+		regs    int
+		pc, sp  string
+	}{
+		{elf.ELFCLASS64, binary.LittleEndian, elf.EM_AARCH64, 34, "pc", "sp"},
+		{elf.ELFCLASS32, binary.LittleEndian, elf.EM_386, 17, "eip", "esp"},
+		{elf.ELFCLASS32, binary.LittleEndian, elf.EM_ARM, 18, "pc", "sp"},
+		{elf.ELFCLASS32, binary.BigEndian, elf.EM_ARM, 18, "pc", "sp"},
+	} {
+		ws := 8
+		if tt.class == elf.ELFCLASS32 {
+			ws = 4
+		}
+		putWord := func(b []byte, v uint64) {
+			if ws == 4 {
+				tt.order.PutUint32(b, uint32(v))
+			} else {
+				tt.order.PutUint64(b, v)
+			}
+		}
+
+		regsOff := 32 + 10*ws
+		prstatus := make([]byte, regsOff+tt.regs*ws+4)
+		tt.order.PutUint16(prstatus[12:], 6)       // pr_cursig
+		tt.order.PutUint32(prstatus[16+2*ws:], 42) // pr_pid
+		putWord(prstatus[32+2*ws:], 3)             // pr_utime.tv_sec
+		putWord(prstatus[32+3*ws:], 500)           // pr_utime.tv_usec
+		for i := 0; i < tt.regs; i++ {
+			putWord(prstatus[regsOff+i*ws:], uint64(0x1000+i))
+		}
+
+		// 32-bit processes have 16-bit user and group ids.
+		prpsinfo := make([]byte, 136)
+		pidOff := 24
+		if ws == 4 {
+			prpsinfo, pidOff = make([]byte, 124), 12
+		}
+		prpsinfo[1] = 'S'
+		tt.order.PutUint32(prpsinfo[pidOff:], 42)
+		copy(prpsinfo[pidOff+16:], "sleep")
+		copy(prpsinfo[pidOff+32:], "sleep 10 ")
+
+		auxv := make([]byte, 4*ws)
+		putWord(auxv, uint64(elf.AT_PAGESZ))
+		putWord(auxv[ws:], 0x10000)
+
+		siginfo := make([]byte, 128)
+		tt.order.PutUint32(siginfo, 6)
+		tt.order.PutUint32(siginfo[8:], 0xfffffffa) // SI_TKILL
+		tt.order.PutUint32(siginfo[max(12, 2*ws):], 42)
+
+		b := elf.NewBuilder(tt.class, tt.order, tt.machine, elf.ET_CORE)
+		notes := b.AddNotes("note0",
+			&elf.Note{Name: elf.ELF_NOTE_CORE, Type: elf.NT_PRSTATUS, Desc: prstatus},
+			&elf.Note{Name: elf.ELF_NOTE_CORE, Type: elf.NT_PRPSINFO, Desc: prpsinfo},
+			&elf.Note{Name: elf.ELF_NOTE_CORE, Type: elf.NT_SIGINFO, Desc: siginfo},
+			&elf.Note{Name: elf.ELF_NOTE_CORE, Type: elf.NT_AUXV, Desc: auxv},
+		)
+		b.AddSegment(elf.ProgramHeader{Type: elf.PT_NOTE, Align: 4}, notes)
+		raw, err := b.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		e, err := elf.New(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := elf.NewCoreFile(e)
+		if err != nil {
+			t.Fatalf("%v: %s", tt.machine, err)
+		}
+
+		if len(c.Threads) != 1 {
+			t.Fatalf("%v: have %d threads", tt.machine, len(c.Threads))
+		}
+		th := c.Threads[0]
+		if th.PID != 42 || th.Signal != 6 || th.UserTime.Microseconds() != 3000500 || len(th.Registers) != tt.regs {
+			t.Errorf("%v: have thread %#v", tt.machine, th)
+		}
+		pc, _ := th.Register(tt.pc)
+		sp, _ := th.Register(tt.sp)
+		if th.PC() != pc || th.SP() != sp || pc == 0 || sp == 0 {
+			t.Errorf("%v: have pc %#x and sp %#x", tt.machine, th.PC(), th.SP())
+		}
+		if want := (&elf.SigInfo{Signo: 6, Code: -6, PID: 42}); !reflect.DeepEqual(th.SigInfo, want) {
+			t.Errorf("%v: SigInfo:\n\thave %#v\n\twant %#v\n", tt.machine, th.SigInfo, want)
+		}
+		if p := c.Process; p == nil || p.PID != 42 || p.State != 'S' || p.Name != "sleep" || p.Args != "sleep 10" {
+			t.Errorf("%v: have process %#v", tt.machine, p)
+		}
+		if want := []*elf.AuxEntry{{Type: elf.AT_PAGESZ, Value: 0x10000}}; !reflect.DeepEqual(c.Auxv, want) {
+			t.Errorf("%v: have auxv %#v", tt.machine, c.Auxv)
+		}
+	}
+}
+
+func TestNewCoreFileMalformed(t *testing.T) {
+	e, err := elf.New(mustReadFile(t, crash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := elf.NewCoreFile(e); err == nil {
+		t.Errorf("an executable should not be a core file")
+	}
+
+	// Bad notes are left out, and the notes around them kept.
+	for _, tt := range []struct {
+		machine elf.Machine
+		class   elf.Class
+		note    *elf.Note
+		want    string
+	}{
+		{elf.EM_X86_64, elf.ELFCLASS64, &elf.Note{Name: elf.ELF_NOTE_CORE, Type: elf.NT_PRSTATUS, Desc: make([]byte, 200)},
+			"invalid NT_PRSTATUS note: "},
+		// A count of one mapping but only room for the two-word header.
+		{elf.EM_X86_64, elf.ELFCLASS64, &elf.Note{Name: elf.ELF_NOTE_CORE, Type: elf.NT_FILE, Desc: append(binary.LittleEndian.AppendUint64(nil, 1), make([]byte, 16)...)},
+			"invalid NT_FILE note: file note with 1 mappings exceeds 24 bytes"},
+		// The uid of a SI_USER siginfo is past the end.
+		{elf.EM_386, elf.ELFCLASS32, &elf.Note{Name: elf.ELF_NOTE_CORE, Type: elf.NT_SIGINFO, Desc: make([]byte, 16)},
+			"invalid NT_SIGINFO note: siginfo note too short: 16 bytes"},
+	} {
+		b := elf.NewBuilder(tt.class, binary.LittleEndian, tt.machine, elf.ET_CORE)
+		prstatus, ws := make([]byte, 336), 8
+		if tt.class == elf.ELFCLASS32 {
+			prstatus, ws = make([]byte, 144), 4
+		}
+		auxv := make([]byte, 4*ws)
+		auxv[0], auxv[ws+1] = byte(elf.AT_PAGESZ), 0x10
+		notes := b.AddNotes("note0",
+			&elf.Note{Name: elf.ELF_NOTE_CORE, Type: elf.NT_PRSTATUS, Desc: prstatus},
+			tt.note,
+			&elf.Note{Name: elf.ELF_NOTE_CORE, Type: elf.NT_AUXV, Desc: auxv},
+		)
+		b.AddSegment(elf.ProgramHeader{Type: elf.PT_NOTE, Align: 4}, notes)
+		raw, err := b.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e, err = elf.New(raw); err != nil {
+			t.Fatal(err)
+		}
+		c, err := elf.NewCoreFile(e)
+		if err != nil {
+			t.Fatalf("%v: %v", tt.note.Type, err)
+		}
+		if len(c.Threads) != 1 || c.Threads[0].SigInfo != nil || c.Mappings != nil || len(c.Auxv) != 1 {
+			t.Errorf("%v: %d threads, %d mappings, auxv %v", tt.note.Type, len(c.Threads), len(c.Mappings), c.Auxv)
+		}
+		// The notes left out are recorded in the CoreFile, not the File.
+		if len(c.Diagnostics) != 1 || !strings.HasPrefix(c.Diagnostics[0].Message, tt.want) || len(e.Diagnostics) != 0 {
+			t.Errorf("%v: diagnostics %v, file diagnostics %v", tt.note.Type, c.Diagnostics, e.Diagnostics)
+		}
+	}
+
+	// A core cut in the middle of its notes keeps the ones before the cut.
+	c := openCore(t)
+	sg := c.SegmentsByType(elf.PT_NOTE)[0]
+	raw := c.Raw[:sg.Header.Offset+sg.Header.Filesz/2]
+	e, err = elf.NewWithOptions(raw, elf.Options{Lenient: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, err = elf.NewCoreFile(e); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Threads) == 0 || c.Process == nil {
+		t.Errorf("cut core: %d threads, process %v", len(c.Threads), c.Process)
+	}
+}
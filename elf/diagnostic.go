@@ -0,0 +1,86 @@
+package elf
+
+import "fmt"
+
+// Severity tells how serious the problem a Diagnostic describes is.
+type Severity int
+
+const (
+	// SeverityWarning marks a violation of the format that does not
+	// change how the file is read.
+	SeverityWarning Severity = iota
+	// SeverityError marks a problem that was stepped over, leaving out or
+	// cutting the data it concerns, by lenient parsing or NewCoreFile.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// Diagnostic describes a problem found while parsing a file. Offset is the
+// file offset of the data the problem is in.
+type Diagnostic struct {
+	Severity Severity
+	Offset   uint64
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s at offset %#x: %s", d.Severity, d.Offset, d.Message)
+}
+
+// warnf records a warning about the data at off.
+func (e *File) warnf(off uint64, format string, args ...any) {
+	e.Diagnostics = append(e.Diagnostics, Diagnostic{
+		Severity: SeverityWarning,
+		Offset:   off,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// fail handles err, a problem with the data at off. Outside lenient parsing
+// it is returned as is; lenient parsing records it and returns nil so that
+// the caller goes on with what it could read.
+func (e *File) fail(off uint64, err error) error {
+	if !e.lenient {
+		return err
+	}
+	e.Diagnostics = append(e.Diagnostics, errorDiagnostic(off, err))
+
+	return nil
+}
+
+// errorDiagnostic describes err, a problem with the data at off that was
+// stepped over.
+func errorDiagnostic(off uint64, err error) Diagnostic {
+	return Diagnostic{
+		Severity: SeverityError,
+		Offset:   off,
+		Message:  err.Error(),
+	}
+}
+
+// fitTable returns how many of the n entries of entsize bytes of the table
+// at off lie within the file, recording an error when that is fewer than n.
+func (e *File) fitTable(what string, off, n uint64, entsize uint16) uint64 {
+	if entsize == 0 {
+		return n
+	}
+	fit := uint64(0)
+	if off <= e.size {
+		fit = (e.size - off) / uint64(entsize)
+	}
+	if n > fit {
+		e.fail(off, fmt.Errorf("%s table truncated to %d of %d entries", what, fit, n))
+		return fit
+	}
+
+	return n
+}
@@ -0,0 +1,200 @@
+package elf
+
+import (
+	"debug/dwarf"
+	"fmt"
+	"strings"
+)
+
+// DWARF returns the debugging information of the file, read from the
+// .debug_* sections, or the legacy compressed .zdebug_* ones. Compressed
+// sections are decompressed, and for relocatable objects the relocations
+// targeting the debug sections are applied first.
+func (e *File) DWARF() (*dwarf.Data, error) {
+	sections := map[string][]byte{}
+	var types [][]byte
+	for _, s := range e.Sections {
+		name, ok := dwarfSectionName(s.Name)
+		if !ok {
+			continue
+		}
+
+		data, err := e.dwarfSection(s)
+		if err != nil {
+			return nil, err
+		}
+
+		// A file may hold several .debug_types sections in COMDAT groups.
+		if name == "types" {
+			types = append(types, data)
+		} else if _, dup := sections[name]; !dup {
+			sections[name] = data
+		}
+	}
+
+	d, err := dwarf.New(sections["abbrev"], sections["aranges"], sections["frame"], sections["info"],
+		sections["line"], sections["pubnames"], sections["ranges"], sections["str"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dwarf: %w", err)
+	}
+
+	// DWARF 5 sections.
+	for _, name := range []string{"addr", "line_str", "loclists", "rnglists", "str_offsets"} {
+		if data, ok := sections[name]; ok {
+			if err := d.AddSection(".debug_"+name, data); err != nil {
+				return nil, fmt.Errorf("failed to read dwarf section .debug_%s: %w", name, err)
+			}
+		}
+	}
+
+	for i, data := range types {
+		if err := d.AddTypes(fmt.Sprintf("types-%d", i), data); err != nil {
+			return nil, fmt.Errorf("failed to read dwarf types: %w", err)
+		}
+	}
+
+	return d, nil
+}
+
+// dwarfSectionName returns the name of a DWARF section without its
+// .debug_ or .zdebug_ prefix.
+func dwarfSectionName(name string) (string, bool) {
+	if n, ok := strings.CutPrefix(name, ".debug_"); ok {
+		return n, true
+	}
+
+	return strings.CutPrefix(name, ".zdebug_")
+}
+
+// dwarfSection returns the decompressed contents of the DWARF section s with
+// the relocations of relocatable objects applied.
+func (e *File) dwarfSection(s *Section) ([]byte, error) {
+	data, err := s.Data()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read section %s: %w", s.Name, err)
+	}
+	if e.Header.Type != ET_REL {
+		return data, nil
+	}
+
+	// Relocate a copy, leaving Raw as it is.
+	data = append([]byte(nil), data...)
+	for _, rs := range e.Sections {
+		t := rs.Header.Type
+		if t != SHT_REL && t != SHT_RELA || e.SectionAt(rs.Header.Info) != s {
+			continue
+		}
+		if err := e.applyRelocations(data, rs); err != nil {
+			return nil, fmt.Errorf("failed to relocate section %s: %w", s.Name, err)
+		}
+	}
+
+	return data, nil
+}
+
+// applyRelocations applies the absolute relocations of the relocation
+// section rs to data. Relocations against undefined symbols and of types
+// DWARF sections do not use are left alone.
+func (e *File) applyRelocations(data []byte, rs *Section) error {
+	rels, err := e.Relocations(rs)
+	if err != nil {
+		return err
+	}
+
+	symtab := e.SectionAt(rs.Header.Link)
+	if symtab == nil {
+		return fmt.Errorf("invalid symbol table index %d for relocation section %s", rs.Header.Link, rs.Name)
+	}
+	syms, err := e.parseSymbols(symtab)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range rels {
+		size := dwarfRelocationSize(r.Type)
+		if size == 0 || r.Symbol == 0 {
+			continue
+		}
+		if uint64(r.Symbol) >= uint64(len(syms)) {
+			return fmt.Errorf("invalid symbol index %d", r.Symbol)
+		}
+		sym := syms[r.Symbol]
+		if sym.Section == SHN_UNDEF || sym.Section >= SHN_LORESERVE && sym.Section <= SHN_HIRESERVE {
+			continue
+		}
+		if r.Offset > uint64(len(data)) || uint64(len(data))-r.Offset < size {
+			return fmt.Errorf("relocation offset %#x out of range", r.Offset)
+		}
+
+		// SHT_REL relocations keep the addend at the location.
+		addend := uint64(r.Addend)
+		if rs.Header.Type == SHT_REL {
+			if size == 4 {
+				addend = uint64(e.Endianness.Uint32(data[r.Offset:]))
+			} else {
+				addend = e.Endianness.Uint64(data[r.Offset:])
+			}
+		}
+
+		v := sym.Value + addend
+		if size == 4 {
+			e.Endianness.PutUint32(data[r.Offset:], uint32(v))
+		} else {
+			e.Endianness.PutUint64(data[r.Offset:], v)
+		}
+	}
+
+	return nil
+}
+
+// dwarfRelocationSize returns the size of the word an absolute relocation
+// of type t writes, or 0 for other relocation types.
+func dwarfRelocationSize(t RelocationType) uint64 {
+	switch t.Machine {
+	case EM_X86_64:
+		switch R_X86_64(t.Value) {
+		case R_X86_64_64:
+			return 8
+		case R_X86_64_32:
+			return 4
+		}
+	case EM_386, EM_486:
+		if R_386(t.Value) == R_386_32 {
+			return 4
+		}
+	case EM_AARCH64:
+		switch R_AARCH64(t.Value) {
+		case R_AARCH64_ABS64:
+			return 8
+		case R_AARCH64_ABS32:
+			return 4
+		}
+	case EM_ARM:
+		if R_ARM(t.Value) == R_ARM_ABS32 {
+			return 4
+		}
+	case EM_RISCV:
+		switch R_RISCV(t.Value) {
+		case R_RISCV_64:
+			return 8
+		case R_RISCV_32:
+			return 4
+		}
+	case EM_PPC64:
+		switch R_PPC64(t.Value) {
+		case R_PPC64_ADDR64:
+			return 8
+		case R_PPC64_ADDR32:
+			return 4
+		}
+	case EM_MIPS:
+		switch R_MIPS(t.Value) {
+		case R_MIPS_64:
+			return 8
+		case R_MIPS_32:
+			return 4
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,117 @@
+package elf_test
+
+import (
+	"debug/dwarf"
+	stdelf "debug/elf"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+func TestDWARF(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		funcs []string
+	}{
+		{libsampleDebug, []string{"sample_init", "sample_hypot", "sample_print"}},
+		{libsampleDebugZlib, []string{"sample_init", "sample_hypot", "sample_print"}},
+		{libsampleDebugZstd, []string{"sample_init", "sample_hypot", "sample_print"}},
+		{"../testdata/elf_linux_amd64", []string{"main.main", "runtime.main"}},
+	} {
+		e, err := elf.New(mustReadFile(t, tt.name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := e.DWARF()
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		have := dwarfSummary(t, d)
+
+		for _, fn := range tt.funcs {
+			if !slices.Contains(have, "subprogram "+fn) {
+				t.Errorf("%s: function %s not found", tt.name, fn)
+			}
+		}
+
+		// The compressed objects must decode to the uncompressed one.
+		oracle := tt.name
+		if oracle == libsampleDebugZlib || oracle == libsampleDebugZstd {
+			oracle = libsampleDebug
+		}
+		std, err := stdelf.Open(oracle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer std.Close()
+		sd, err := std.DWARF()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := dwarfSummary(t, sd); !reflect.DeepEqual(have, want) {
+			t.Errorf("%s: DWARF differs from debug/elf: have %d entries, want %d", tt.name, len(have), len(want))
+		}
+	}
+}
+
+func TestDWARFNoDebugInfo(t *testing.T) {
+	e, err := elf.New(mustReadFile(t, "../testdata/libsample_linux_amd64.o"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.DWARF(); err == nil {
+		t.Errorf("a file without debug information should fail")
+	}
+}
+
+// dwarfSummary lists the compile units and subprograms of d with their
+// attributes, followed by the rows of their line tables.
+func dwarfSummary(t *testing.T, d *dwarf.Data) []string {
+	t.Helper()
+
+	var out []string
+	r := d.Reader()
+	for {
+		ent, err := r.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ent == nil {
+			break
+		}
+
+		switch ent.Tag {
+		case dwarf.TagCompileUnit:
+			out = append(out, fmt.Sprintf("compile unit %v", ent.Val(dwarf.AttrName)))
+
+			lr, err := d.LineReader(ent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if lr == nil {
+				continue
+			}
+			var le dwarf.LineEntry
+			for {
+				if err := lr.Next(&le); err != nil {
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					t.Fatal(err)
+				}
+				out = append(out, fmt.Sprintf("line %#x %s:%d", le.Address, le.File.Name, le.Line))
+			}
+		case dwarf.TagSubprogram:
+			if name, ok := ent.Val(dwarf.AttrName).(string); ok {
+				out = append(out, "subprogram "+name, fmt.Sprintf("low pc %v", ent.Val(dwarf.AttrLowpc)))
+			}
+		}
+	}
+
+	return out
+}
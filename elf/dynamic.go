@@ -0,0 +1,285 @@
+package elf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// DynamicEntry represents an entry of the dynamic section (Elf_Dyn).
+// Value holds either an integer or a virtual address depending on Tag.
+type DynamicEntry struct {
+	Tag   DynTag
+	Value uint64
+}
+
+type dynamicEntry32 struct {
+	Tag   int32
+	Value uint32
+}
+
+type dynamicEntry64 struct {
+	Tag   int64
+	Value uint64
+}
+
+var (
+	sizeDynamicEntry32 = uint64(binary.Size(dynamicEntry32{}))
+	sizeDynamicEntry64 = uint64(binary.Size(dynamicEntry64{}))
+)
+
+// DynamicEntries returns the entries of the dynamic section up to, but not
+// including, the terminating DT_NULL. The PT_DYNAMIC segment is preferred so
+// that files without section headers can be decoded; the SHT_DYNAMIC section
+// is used when there is no such segment. It returns nil if the file has
+// neither.
+func (e *File) DynamicEntries() ([]*DynamicEntry, error) {
+	var raw []byte
+	var err error
+	if sgs := e.SegmentsByType(PT_DYNAMIC); len(sgs) > 0 {
+		raw, err = sgs[0].Data()
+	} else if ss := e.SectionsByType(SHT_DYNAMIC); len(ss) > 0 {
+		raw, err = ss[0].Data()
+	} else {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dynamic section: %w", err)
+	}
+
+	is32 := e.is32()
+	entSize := sizeDynamicEntry64
+	if is32 {
+		entSize = sizeDynamicEntry32
+	}
+
+	var des []*DynamicEntry
+	r := bytes.NewReader(raw)
+	for i := 0; uint64(r.Len()) >= entSize; i++ {
+		de := &DynamicEntry{}
+		if is32 {
+			var d32 dynamicEntry32
+			if err := binary.Read(r, e.Endianness, &d32); err != nil {
+				return nil, fmt.Errorf("failed to read dynamic entry %d: %w", i, err)
+			}
+			de.Tag = DynTag(d32.Tag)
+			de.Value = uint64(d32.Value)
+		} else {
+			var d64 dynamicEntry64
+			if err := binary.Read(r, e.Endianness, &d64); err != nil {
+				return nil, fmt.Errorf("failed to read dynamic entry %d: %w", i, err)
+			}
+			de.Tag = DynTag(d64.Tag)
+			de.Value = d64.Value
+		}
+
+		if de.Tag == DT_NULL {
+			break
+		}
+		des = append(des, de)
+	}
+
+	return des, nil
+}
+
+// DynValues returns the values of every dynamic entry with the given tag.
+func (e *File) DynValues(tag DynTag) ([]uint64, error) {
+	des, err := e.DynamicEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	var vs []uint64
+	for _, de := range des {
+		if de.Tag == tag {
+			vs = append(vs, de.Value)
+		}
+	}
+
+	return vs, nil
+}
+
+// DynStrings returns the strings referenced by every dynamic entry with the
+// given tag, such as DT_NEEDED or DT_SONAME. The strings are resolved through
+// the DT_STRTAB table.
+func (e *File) DynStrings(tag DynTag) ([]string, error) {
+	des, err := e.DynamicEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	var strtab []byte
+	var ss []string
+	for _, de := range des {
+		if de.Tag != tag {
+			continue
+		}
+
+		if strtab == nil {
+			strtab, err = e.dynamicStringTable(des)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if de.Value > 0xffffffff {
+			return nil, fmt.Errorf("invalid dynamic string offset: %d", de.Value)
+		}
+		s, err := stringAt(strtab, uint32(de.Value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid string of dynamic entry %#x: %w", uint64(de.Tag), err)
+		}
+		ss = append(ss, s)
+	}
+
+	return ss, nil
+}
+
+// Needed returns the libraries listed in DT_NEEDED entries.
+func (e *File) Needed() ([]string, error) {
+	return e.DynStrings(DT_NEEDED)
+}
+
+// Soname returns the DT_SONAME of a shared object, or an empty string if
+// there is none.
+func (e *File) Soname() (string, error) {
+	return e.firstDynString(DT_SONAME)
+}
+
+// RPath returns the colon separated directories of DT_RPATH.
+func (e *File) RPath() ([]string, error) {
+	return e.dynPath(DT_RPATH)
+}
+
+// RunPath returns the colon separated directories of DT_RUNPATH.
+func (e *File) RunPath() ([]string, error) {
+	return e.dynPath(DT_RUNPATH)
+}
+
+// DynFlags returns the value of DT_FLAGS, or 0 if there is none.
+func (e *File) DynFlags() (DynFlag, error) {
+	v, err := e.firstDynValue(DT_FLAGS)
+	return DynFlag(v), err
+}
+
+// DynFlags1 returns the value of DT_FLAGS_1, or 0 if there is none.
+func (e *File) DynFlags1() (DynFlag1, error) {
+	v, err := e.firstDynValue(DT_FLAGS_1)
+	return DynFlag1(v), err
+}
+
+// InitArray returns the function addresses of DT_INIT_ARRAY as stored in
+// the file. Dynamic relocations are not applied, so position independent
+// objects may hold link-time addresses or zeros here.
+func (e *File) InitArray() ([]uint64, error) {
+	return e.dynPointerArray(DT_INIT_ARRAY, DT_INIT_ARRAYSZ)
+}
+
+// FiniArray returns the function addresses of DT_FINI_ARRAY as stored in
+// the file.
+func (e *File) FiniArray() ([]uint64, error) {
+	return e.dynPointerArray(DT_FINI_ARRAY, DT_FINI_ARRAYSZ)
+}
+
+// PreinitArray returns the function addresses of DT_PREINIT_ARRAY as stored
+// in the file.
+func (e *File) PreinitArray() ([]uint64, error) {
+	return e.dynPointerArray(DT_PREINIT_ARRAY, DT_PREINIT_ARRAYSZ)
+}
+
+func (e *File) firstDynValue(tag DynTag) (uint64, error) {
+	vs, err := e.DynValues(tag)
+	if err != nil || len(vs) == 0 {
+		return 0, err
+	}
+
+	return vs[0], nil
+}
+
+func (e *File) firstDynString(tag DynTag) (string, error) {
+	ss, err := e.DynStrings(tag)
+	if err != nil || len(ss) == 0 {
+		return "", err
+	}
+
+	return ss[0], nil
+}
+
+func (e *File) dynPath(tag DynTag) ([]string, error) {
+	ss, err := e.DynStrings(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, s := range ss {
+		dirs = append(dirs, strings.Split(s, ":")...)
+	}
+
+	return dirs, nil
+}
+
+func (e *File) dynPointerArray(addrTag, sizeTag DynTag) ([]uint64, error) {
+	des, err := e.DynamicEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	addr, hasAddr := findDynValue(des, addrTag)
+	size, hasSize := findDynValue(des, sizeTag)
+	if !hasAddr || !hasSize {
+		return nil, nil
+	}
+
+	buf, err := e.ReadAddr(addr, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dynamic array at %#x: %w", addr, err)
+	}
+
+	ptrSize := 8
+	if e.is32() {
+		ptrSize = 4
+	}
+	ptrs := make([]uint64, 0, len(buf)/ptrSize)
+	for i := 0; i+ptrSize <= len(buf); i += ptrSize {
+		if ptrSize == 4 {
+			ptrs = append(ptrs, uint64(e.Endianness.Uint32(buf[i:])))
+		} else {
+			ptrs = append(ptrs, e.Endianness.Uint64(buf[i:]))
+		}
+	}
+
+	return ptrs, nil
+}
+
+func findDynValue(des []*DynamicEntry, tag DynTag) (uint64, bool) {
+	for _, de := range des {
+		if de.Tag == tag {
+			return de.Value, true
+		}
+	}
+
+	return 0, false
+}
+
+// dynamicStringTable locates the string table referenced by DT_STRTAB and
+// DT_STRSZ. When the address cannot be mapped through the PT_LOAD segments,
+// the string table linked from the SHT_DYNAMIC section is used instead.
+func (e *File) dynamicStringTable(des []*DynamicEntry) ([]byte, error) {
+	addr, hasAddr := findDynValue(des, DT_STRTAB)
+	size, hasSize := findDynValue(des, DT_STRSZ)
+	if hasAddr && hasSize {
+		if strtab, err := e.ReadAddr(addr, size); err == nil {
+			return strtab, nil
+		}
+	}
+
+	if ss := e.SectionsByType(SHT_DYNAMIC); len(ss) > 0 {
+		if s := e.SectionAt(ss[0].Header.Link); s != nil && s.Header.Type == SHT_STRTAB {
+			return s.Data()
+		}
+	}
+
+	return nil, fmt.Errorf("dynamic string table not found")
+}
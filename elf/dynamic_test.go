@@ -0,0 +1,139 @@
+package elf_test
+
+import (
+	"encoding/binary"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+const libsample = "../testdata/libsample_linux_amd64.so"
+
+func TestDynamic(t *testing.T) {
+	b, err := os.ReadFile(libsample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A copy without section headers checks that everything is resolved
+	// through the program headers alone.
+	stripped := append([]byte(nil), b...)
+	le := binary.LittleEndian
+	le.PutUint64(stripped[40:], 0) // e_shoff
+	le.PutUint16(stripped[60:], 0) // e_shnum
+	le.PutUint16(stripped[62:], 0) // e_shstrndx
+
+	for name, raw := range map[string][]byte{"original": b, "stripped": stripped} {
+		t.Run(name, func(t *testing.T) {
+			e, err := elf.New(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			des, err := e.DynamicEntries()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(des) != 28 {
+				t.Errorf("have %d dynamic entries, want %d", len(des), 28)
+			}
+			if des[0].Tag != elf.DT_NEEDED {
+				t.Errorf("first entry should be DT_NEEDED, have %#v", des[0])
+			}
+
+			needed, err := e.Needed()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []string{"libm.so.6", "libc.so.6"}; !reflect.DeepEqual(needed, want) {
+				t.Errorf("Needed:\n\thave %#v\n\twant %#v\n", needed, want)
+			}
+
+			soname, err := e.Soname()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if soname != "libsample.so.1" {
+				t.Errorf("Soname: have %q, want %q", soname, "libsample.so.1")
+			}
+
+			runpath, err := e.RunPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []string{"$ORIGIN/../lib"}; !reflect.DeepEqual(runpath, want) {
+				t.Errorf("RunPath:\n\thave %#v\n\twant %#v\n", runpath, want)
+			}
+
+			rpath, err := e.RPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rpath != nil {
+				t.Errorf("RPath: have %#v, want nil", rpath)
+			}
+
+			flags, err := e.DynFlags()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if flags != elf.DF_BIND_NOW {
+				t.Errorf("DynFlags: have %#x, want %#x", flags, elf.DF_BIND_NOW)
+			}
+
+			flags1, err := e.DynFlags1()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if flags1 != elf.DF_1_NOW {
+				t.Errorf("DynFlags1: have %#x, want %#x", flags1, elf.DF_1_NOW)
+			}
+
+			initArray, err := e.InitArray()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []uint64{0x1120, 0x1060}; !reflect.DeepEqual(initArray, want) {
+				t.Errorf("InitArray:\n\thave %#v\n\twant %#v\n", initArray, want)
+			}
+
+			finiArray, err := e.FiniArray()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []uint64{0x10e0}; !reflect.DeepEqual(finiArray, want) {
+				t.Errorf("FiniArray:\n\thave %#v\n\twant %#v\n", finiArray, want)
+			}
+
+			strtab, err := e.DynValues(elf.DT_STRTAB)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []uint64{0x360}; !reflect.DeepEqual(strtab, want) {
+				t.Errorf("DynValues(DT_STRTAB):\n\thave %#v\n\twant %#v\n", strtab, want)
+			}
+		})
+	}
+}
+
+func TestDynamicStatic(t *testing.T) {
+	b, err := os.ReadFile("../testdata/elf_linux_amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := elf.New(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	des, err := e.DynamicEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if des != nil {
+		t.Errorf("statically linked binary should have no dynamic entries, have %d", len(des))
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"io"
+	"os"
 )
 
 type ELFHeader struct {
@@ -23,6 +25,19 @@ type ELFHeader struct {
 	Shstrndx  uint16
 }
 
+// Class returns the word size of the file, from e_ident[EI_CLASS].
+func (h *ELFHeader) Class() Class { return Class(h.Ident[EI_CLASS]) }
+
+// Data returns the byte order of the file, from e_ident[EI_DATA].
+func (h *ELFHeader) Data() Data { return Data(h.Ident[EI_DATA]) }
+
+// OSABI returns the operating system or ABI the file is built for, from
+// e_ident[EI_OSABI].
+func (h *ELFHeader) OSABI() OSABI { return OSABI(h.Ident[EI_OSABI]) }
+
+// ABIVersion returns the version of the OS/ABI, from e_ident[EI_ABIVERSION].
+func (h *ELFHeader) ABIVersion() ABIVersion { return ABIVersion(h.Ident[EI_ABIVERSION]) }
+
 type elfHeader32 struct {
 	Ident     [16]byte
 	Type      uint16
@@ -41,13 +56,36 @@ type elfHeader32 struct {
 }
 
 // File represents a parsed ELF file, including its header, sections,
-// segments, detected endianness, and the raw underlying bytes.
+// segments, detected endianness, and the raw underlying bytes. Raw is only
+// set by New; files opened with NewFromReaderAt or Open read their contents
+// on demand instead.
 type File struct {
 	Header     *ELFHeader
 	Sections   []*Section
 	Segments   []*Segment
 	Endianness binary.ByteOrder
 	Raw        []byte
+
+	// Diagnostics lists the problems found while parsing that did not
+	// stop it, in the order they were found.
+	Diagnostics []Diagnostic
+
+	reader  io.ReaderAt
+	size    uint64
+	closer  io.Closer
+	lenient bool
+
+	// shstrtab, shdrSize and phdrSize record the section header string
+	// table and the extents of the header tables as parsed, and sections
+	// the sections in their original order, for WriteTo.
+	shstrtab *Section
+	shdrSize uint64
+	phdrSize uint64
+	sections []*Section
+
+	// unnamed is set by lenient parsing when the section header string
+	// table index is invalid, leaving every section without its name.
+	unnamed bool
 }
 
 type SectionHeader struct {
@@ -76,10 +114,23 @@ type sectionHeader32 struct {
 	Entsize   uint32
 }
 
+// Section represents a section of the file. Raw holds its contents as stored
+// in the file when the file was parsed by New and is nil otherwise; Data and
+// Open work in both cases.
 type Section struct {
 	Header SectionHeader
 	Name   string
 	Raw    []byte
+
+	// Broken is set by lenient parsing when the name or the contents of the
+	// section could not be read. Name is then empty or the contents are cut
+	// at the end of the file. Header keeps the values read from the file,
+	// so Offset and Size may then describe more than Raw and Data hold;
+	// WriteTo refuses files with broken sections.
+	Broken bool
+
+	sr   *io.SectionReader
+	file *File
 }
 
 type ProgramHeader struct {
@@ -104,9 +155,19 @@ type programHeader32 struct {
 	Align  uint32
 }
 
+// Segment represents a segment of the file. Like Section, Raw is only set
+// when the file was parsed by New.
 type Segment struct {
 	Header ProgramHeader
 	Raw    []byte
+
+	// Broken is set by lenient parsing when the contents of the segment
+	// extend past the end of the file, where they are cut. Header keeps the
+	// values read from the file, so Offset and Filesz then describe more
+	// than Raw and Data hold; WriteTo refuses files with broken segments.
+	Broken bool
+
+	sr *io.SectionReader
 }
 
 var (
@@ -120,35 +181,66 @@ var (
 // contained in raw, guarding against integer overflow and out-of-bounds
 // access caused by malformed ELF files.
 func safeSlice(raw []byte, offset, size uint64) ([]byte, error) {
+	if err := checkRange(uint64(len(raw)), offset, size); err != nil {
+		return nil, err
+	}
+
+	return raw[offset : offset+size], nil
+}
+
+// checkRange reports an error unless [offset:offset+size] lies within a file
+// of length total.
+func checkRange(total, offset, size uint64) error {
 	end := offset + size
 	if end < offset {
-		return nil, fmt.Errorf("range [%d:%d+%d] overflows", offset, offset, size)
+		return fmt.Errorf("range [%d:%d+%d] overflows", offset, offset, size)
+	}
+	if offset > total || end > total {
+		return fmt.Errorf("range [%d:%d] out of bounds (len %d)", offset, end, total)
 	}
-	if offset > uint64(len(raw)) || end > uint64(len(raw)) {
-		return nil, fmt.Errorf("range [%d:%d] out of bounds (len %d)", offset, end, len(raw))
+
+	return nil
+}
+
+// readAt returns size bytes at offset of the underlying file. The result
+// shares memory with Raw when the file was parsed by New.
+func (e *File) readAt(offset, size uint64) ([]byte, error) {
+	if e.Raw != nil {
+		return safeSlice(e.Raw, offset, size)
+	}
+	if e.reader == nil {
+		return nil, fmt.Errorf("file has no underlying data")
+	}
+	if err := checkRange(e.size, offset, size); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, size)
+	if n, err := e.reader.ReadAt(buf, int64(offset)); n != len(buf) {
+		return nil, fmt.Errorf("failed to read range [%d:%d]: %w", offset, offset+size, err)
 	}
 
-	return raw[offset:end], nil
+	return buf, nil
 }
 
-// sectionName resolves a null-terminated name at nameOffset inside the
-// section header string table, bounded by the string table itself rather than
-// the whole file. A NUL that only appears in data following the table is not
-// accepted as a terminator.
-func sectionName(strtab []byte, nameOffset uint32) (string, error) {
-	if uint64(nameOffset) >= uint64(len(strtab)) {
-		return "", fmt.Errorf("invalid section name offset: %d (string table size %d)", nameOffset, len(strtab))
+// stringAt resolves a null-terminated string at offset inside a string table
+// such as .shstrtab, .strtab or .dynstr, bounded by the table itself rather
+// than the whole file. A NUL that only appears in data following the table
+// is not accepted as a terminator.
+func stringAt(strtab []byte, offset uint32) (string, error) {
+	if uint64(offset) >= uint64(len(strtab)) {
+		return "", fmt.Errorf("invalid string offset: %d (string table size %d)", offset, len(strtab))
 	}
 
-	rel := bytes.IndexByte(strtab[nameOffset:], 0)
+	rel := bytes.IndexByte(strtab[offset:], 0)
 	if rel < 0 {
-		return "", fmt.Errorf("section name at offset %d is not null-terminated within the string table", nameOffset)
+		return "", fmt.Errorf("string at offset %d is not null-terminated within the string table", offset)
 	}
 
-	return string(strtab[nameOffset : uint64(nameOffset)+uint64(rel)]), nil
+	return string(strtab[offset : uint64(offset)+uint64(rel)]), nil
 }
 
-func parseSectionHeaders(raw []byte, endianness binary.ByteOrder, is32 bool, shoff uint64, shnum, shentsize uint16) ([]SectionHeader, error) {
+func (e *File) parseSectionHeaders(is32 bool, shoff, shnum uint64, shentsize uint16) ([]SectionHeader, error) {
 	structSize := sizeSectionHeader64
 	if is32 {
 		structSize = sizeSectionHeader32
@@ -156,25 +248,28 @@ func parseSectionHeaders(raw []byte, endianness binary.ByteOrder, is32 bool, sho
 	if uint64(shentsize) < structSize {
 		return nil, fmt.Errorf("invalid section header entry size: %d", shentsize)
 	}
+	if shnum > e.size/uint64(shentsize) {
+		return nil, fmt.Errorf("invalid number of section headers: %d", shnum)
+	}
 
 	shs := make([]SectionHeader, shnum)
-	for i := 0; i < int(shnum); i++ {
-		entryOffset := shoff + uint64(i)*uint64(shentsize)
-		buf, err := safeSlice(raw, entryOffset, structSize)
+	for i := uint64(0); i < shnum; i++ {
+		entryOffset := shoff + i*uint64(shentsize)
+		buf, err := e.readAt(entryOffset, structSize)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read section header %d: %w", i, err)
+			return shs[:i], fmt.Errorf("failed to read section header %d: %w", i, err)
 		}
 
 		r := bytes.NewReader(buf)
 		if is32 {
 			var sh32 sectionHeader32
-			if err := binary.Read(r, endianness, &sh32); err != nil {
+			if err := binary.Read(r, e.Endianness, &sh32); err != nil {
 				return nil, fmt.Errorf("failed to read section header %d: %w", i, err)
 			}
 			shs[i] = convertToSectionHeader(&sh32)
 		} else {
 			var sh SectionHeader
-			if err := binary.Read(r, endianness, &sh); err != nil {
+			if err := binary.Read(r, e.Endianness, &sh); err != nil {
 				return nil, fmt.Errorf("failed to read section header %d: %w", i, err)
 			}
 			shs[i] = sh
@@ -184,7 +279,7 @@ func parseSectionHeaders(raw []byte, endianness binary.ByteOrder, is32 bool, sho
 	return shs, nil
 }
 
-func parseProgramHeaders(raw []byte, endianness binary.ByteOrder, is32 bool, phoff uint64, phnum, phentsize uint16) ([]ProgramHeader, error) {
+func (e *File) parseProgramHeaders(is32 bool, phoff, phnum uint64, phentsize uint16) ([]ProgramHeader, error) {
 	structSize := sizeProgramHeader64
 	if is32 {
 		structSize = sizeProgramHeader32
@@ -192,25 +287,28 @@ func parseProgramHeaders(raw []byte, endianness binary.ByteOrder, is32 bool, pho
 	if uint64(phentsize) < structSize {
 		return nil, fmt.Errorf("invalid program header entry size: %d", phentsize)
 	}
+	if phnum > e.size/uint64(phentsize) {
+		return nil, fmt.Errorf("invalid number of program headers: %d", phnum)
+	}
 
 	phs := make([]ProgramHeader, phnum)
-	for i := 0; i < int(phnum); i++ {
-		entryOffset := phoff + uint64(i)*uint64(phentsize)
-		buf, err := safeSlice(raw, entryOffset, structSize)
+	for i := uint64(0); i < phnum; i++ {
+		entryOffset := phoff + i*uint64(phentsize)
+		buf, err := e.readAt(entryOffset, structSize)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read program header %d: %w", i, err)
+			return phs[:i], fmt.Errorf("failed to read program header %d: %w", i, err)
 		}
 
 		r := bytes.NewReader(buf)
 		if is32 {
 			var ph32 programHeader32
-			if err := binary.Read(r, endianness, &ph32); err != nil {
+			if err := binary.Read(r, e.Endianness, &ph32); err != nil {
 				return nil, fmt.Errorf("failed to read program header %d: %w", i, err)
 			}
 			phs[i] = convertToProgramHeader(&ph32)
 		} else {
 			var ph ProgramHeader
-			if err := binary.Read(r, endianness, &ph); err != nil {
+			if err := binary.Read(r, e.Endianness, &ph); err != nil {
 				return nil, fmt.Errorf("failed to read program header %d: %w", i, err)
 			}
 			phs[i] = ph
@@ -220,122 +318,431 @@ func parseProgramHeaders(raw []byte, endianness binary.ByteOrder, is32 bool, pho
 	return phs, nil
 }
 
+// resolveHeaderCounts returns the number of section headers, the section
+// header string table index and the number of program headers. Files with
+// too many sections or segments for the 16-bit ELF header fields store the
+// real values in section 0: e_shnum == 0 moves the count to sh_size,
+// e_shstrndx == SHN_XINDEX moves the index to sh_link and e_phnum == PN_XNUM
+// moves the count to sh_info.
+func (e *File) resolveHeaderCounts(is32 bool) (uint64, uint32, uint64, error) {
+	header := e.Header
+	shnum := uint64(header.Shnum)
+	shstrndx := uint32(header.Shstrndx)
+	phnum := uint64(header.Phnum)
+
+	if shnum != 0 && shstrndx != SHN_XINDEX && phnum != PN_XNUM {
+		return shnum, shstrndx, phnum, nil
+	}
+	if header.Shoff == 0 {
+		if phnum == PN_XNUM {
+			return 0, 0, 0, fmt.Errorf("extended program header number (PN_XNUM) without section headers")
+		}
+		return 0, shstrndx, phnum, nil
+	}
+
+	shs, err := e.parseSectionHeaders(is32, header.Shoff, 1, header.Shentsize)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	sh0 := shs[0]
+
+	if shnum == 0 {
+		shnum = sh0.Size
+	}
+	if shstrndx == SHN_XINDEX {
+		shstrndx = sh0.Link
+	}
+	if phnum == PN_XNUM {
+		phnum = uint64(sh0.Info)
+	}
+
+	return shnum, shstrndx, phnum, nil
+}
+
 func New(raw []byte) (*File, error) {
-	if len(raw) < int(MAGIC_SIZE) {
-		return nil, fmt.Errorf("insufficient elf format size: %d", len(raw))
+	e := &File{
+		Raw:  raw,
+		size: uint64(len(raw)),
+	}
+	if err := e.parse(); err != nil {
+		return nil, err
 	}
 
-	if !bytes.Equal(raw[:MAGIC_SIZE], []byte(ELF_MAGIC)) {
-		return nil, fmt.Errorf("invalid magic number: %s", raw[:MAGIC_SIZE])
+	return e, nil
+}
+
+// Options changes how NewWithOptions parses a file.
+type Options struct {
+	// Lenient keeps parsing past problems that make New fail, such as a
+	// section name outside the string table or a section extending past
+	// the end of the file. Each of them is recorded in File.Diagnostics
+	// with SeverityError, and the sections and segments it concerns are
+	// marked Broken. An invalid section header string table index leaves
+	// all the sections without names. Only a file whose ELF header cannot
+	// be read is still rejected.
+	Lenient bool
+}
+
+// NewWithOptions parses raw like New, as changed by opts. It is meant for
+// truncated or corrupted files, such as partial downloads, malware and
+// core dumps of crashed processes.
+func NewWithOptions(raw []byte, opts Options) (*File, error) {
+	e := &File{
+		Raw:     raw,
+		size:    uint64(len(raw)),
+		lenient: opts.Lenient,
+	}
+	if err := e.parse(); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// NewFromReaderAt parses the headers of the size byte ELF file read through
+// r. Section and segment contents are not read until they are requested with
+// Data or Open, so r must stay readable while the File is in use.
+func NewFromReaderAt(r io.ReaderAt, size int64) (*File, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("invalid file size: %d", size)
+	}
+
+	e := &File{
+		reader: r,
+		size:   uint64(size),
+	}
+	if err := e.parse(); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Open opens the named file and parses it with NewFromReaderAt. The returned
+// File must be closed with Close.
+func Open(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
 	}
 
-	if len(raw) <= int(EI_DATA) {
-		return nil, fmt.Errorf("insufficient elf format size: %d", len(raw))
+	e, err := NewFromReaderAt(f, fi.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	e.closer = f
+
+	return e, nil
+}
+
+// Close releases the file opened by Open or OpenMmap. It does nothing for
+// files created by New or NewFromReaderAt.
+func (e *File) Close() error {
+	if e.closer == nil {
+		return nil
+	}
+
+	err := e.closer.Close()
+	e.closer = nil
+
+	return err
+}
+
+// parse decodes the ELF header, section headers and program headers of the
+// underlying file. Section and segment bodies are bounds checked, and only
+// sliced from Raw when it is set.
+func (e *File) parse() error {
+	ident, err := e.readAt(0, min(e.size, uint64(binary.Size(ELFHeader{}))))
+	if err != nil {
+		return fmt.Errorf("failed to read elf header: %w", err)
+	}
+
+	if len(ident) < int(MAGIC_SIZE) {
+		return fmt.Errorf("insufficient elf format size: %d", len(ident))
+	}
+
+	if !bytes.Equal(ident[:MAGIC_SIZE], []byte(ELF_MAGIC)) {
+		return fmt.Errorf("invalid magic number: %s", ident[:MAGIC_SIZE])
+	}
+
+	if len(ident) <= int(EI_DATA) {
+		return fmt.Errorf("insufficient elf format size: %d", len(ident))
 	}
 
 	var endianness binary.ByteOrder
-	switch raw[EI_DATA] {
-	case 1:
+	switch Data(ident[EI_DATA]) {
+	case ELFDATA2LSB:
 		endianness = binary.LittleEndian
-	case 2:
+	case ELFDATA2MSB:
 		endianness = binary.BigEndian
 	default:
-		return nil, fmt.Errorf("invalid endianness: %d", raw[EI_DATA])
+		return fmt.Errorf("invalid endianness: %d", ident[EI_DATA])
 	}
 
-	if raw[EI_CLASS] != 1 && raw[EI_CLASS] != 2 {
-		return nil, fmt.Errorf("invalid elf class: %d", raw[EI_CLASS])
+	class := Class(ident[EI_CLASS])
+	if class != ELFCLASS32 && class != ELFCLASS64 {
+		return fmt.Errorf("invalid elf class: %d", ident[EI_CLASS])
 	}
-	is32 := raw[EI_CLASS] == 1
+	is32 := class == ELFCLASS32
 
 	var header ELFHeader
-	r := bytes.NewReader(raw)
+	r := bytes.NewReader(ident)
 	if is32 {
 		header32 := new(elfHeader32)
 		if err := binary.Read(r, endianness, header32); err != nil {
-			return nil, fmt.Errorf("failed to read elf header: %w", err)
+			return fmt.Errorf("failed to read elf header: %w", err)
 		}
 		header = convertToELFHeader(header32)
 	} else {
 		if err := binary.Read(r, endianness, &header); err != nil {
-			return nil, fmt.Errorf("failed to read elf header: %w", err)
+			return fmt.Errorf("failed to read elf header: %w", err)
 		}
 	}
 
-	e := &File{
-		Header:     &header,
-		Endianness: endianness,
-		Raw:        raw,
+	e.Header = &header
+	e.Endianness = endianness
+	e.checkIdent()
+
+	shnum, shstrndx, phnum, err := e.resolveHeaderCounts(is32)
+	if err != nil {
+		if err := e.fail(header.Shoff, err); err != nil {
+			return err
+		}
+		// Without section 0 neither the sections nor an extended segment
+		// count can be read.
+		shnum, shstrndx, phnum = 0, 0, uint64(header.Phnum)
+		if phnum == PN_XNUM {
+			phnum = 0
+		}
 	}
 
-	if header.Shnum == 0 {
+	if err := e.parseSections(is32, shnum, shstrndx); err != nil {
+		return err
+	}
+
+	return e.parseSegments(is32, phnum)
+}
+
+// parseSections reads the shnum section headers and the names and bodies of
+// the sections.
+func (e *File) parseSections(is32 bool, shnum uint64, shstrndx uint32) error {
+	header := e.Header
+	if e.lenient {
+		shnum = e.fitTable("section header", header.Shoff, shnum, header.Shentsize)
+	}
+	if shnum == 0 {
 		e.Sections = make([]*Section, 0)
-	} else {
-		shs, err := parseSectionHeaders(raw, endianness, is32, header.Shoff, header.Shnum, header.Shentsize)
-		if err != nil {
-			return nil, err
-		}
+		return nil
+	}
 
-		if header.Shstrndx == SHN_XINDEX {
-			return nil, fmt.Errorf("unsupported extended section header string table index (SHN_XINDEX)")
+	shs, err := e.parseSectionHeaders(is32, header.Shoff, shnum, header.Shentsize)
+	if err != nil {
+		if err := e.fail(header.Shoff, err); err != nil {
+			return err
 		}
-		if header.Shstrndx >= header.Shnum {
-			return nil, fmt.Errorf("invalid section header string table index: %d", header.Shstrndx)
+	}
+
+	var strtab []byte
+	named := false
+	if uint64(shstrndx) >= uint64(len(shs)) {
+		err := fmt.Errorf("invalid section header string table index: %d", shstrndx)
+		if err := e.fail(offsetShstrndx(is32), err); err != nil {
+			return err
 		}
-		strtabHeader := shs[header.Shstrndx]
-		strtab, err := safeSlice(raw, strtabHeader.Offset, strtabHeader.Size)
+		e.unnamed = len(shs) > 0
+	} else {
+		strtabHeader := shs[shstrndx]
+		strtab, err = e.readAt(strtabHeader.Offset, strtabHeader.Size)
 		if err != nil {
-			return nil, fmt.Errorf("invalid section header string table: %w", err)
+			err = fmt.Errorf("invalid section header string table: %w", err)
+			if err := e.fail(strtabHeader.Offset, err); err != nil {
+				return err
+			}
+			strtab, _ = e.partialBody(strtabHeader.Offset, strtabHeader.Size)
 		}
+		named = true
+	}
 
-		e.Sections = make([]*Section, header.Shnum)
-		for i := 0; i < len(shs); i++ {
-			name, err := sectionName(strtab, shs[i].Name)
+	e.Sections = make([]*Section, len(shs))
+	for i := 0; i < len(shs); i++ {
+		entryOffset := header.Shoff + uint64(i)*uint64(header.Shentsize)
+		s := &Section{
+			Header: shs[i],
+			file:   e,
+		}
+		if named {
+			s.Name, err = stringAt(strtab, shs[i].Name)
 			if err != nil {
-				return nil, err
-			}
-
-			var sr []byte
-			if shs[i].Type != SHT_NOBITS {
-				sr, err = safeSlice(raw, shs[i].Offset, shs[i].Size)
-				if err != nil {
-					return nil, fmt.Errorf("invalid section %d (%s) body: %w", i, name, err)
+				if err := e.fail(entryOffset, fmt.Errorf("invalid name of section %d: %w", i, err)); err != nil {
+					return err
 				}
-			} else {
-				sr = make([]byte, 0)
+				s.Broken = true
 			}
+		}
 
-			e.Sections[i] = &Section{
-				Header: shs[i],
-				Name:   name,
-				Raw:    sr,
+		if shs[i].Type != SHT_NOBITS {
+			s.Raw, s.sr, err = e.body(shs[i].Offset, shs[i].Size)
+			if err != nil {
+				err = fmt.Errorf("invalid section %d (%s) body: %w", i, s.Name, err)
+				if err := e.fail(shs[i].Offset, err); err != nil {
+					return err
+				}
+				s.Raw, s.sr = e.partialBody(shs[i].Offset, shs[i].Size)
+				s.Broken = true
 			}
+		} else if e.Raw != nil {
+			s.Raw = make([]byte, 0)
 		}
+
+		e.Sections[i] = s
 	}
+	if uint64(shstrndx) < uint64(len(e.Sections)) {
+		e.shstrtab = e.Sections[shstrndx]
+	}
+	e.shdrSize = uint64(len(shs)) * uint64(header.Shentsize)
+	e.sections = append([]*Section(nil), e.Sections...)
 
-	if header.Phnum == 0 {
+	return nil
+}
+
+// parseSegments reads the phnum program headers and the bodies of the
+// segments.
+func (e *File) parseSegments(is32 bool, phnum uint64) error {
+	header := e.Header
+	if e.lenient {
+		phnum = e.fitTable("program header", header.Phoff, phnum, header.Phentsize)
+	}
+	if phnum == 0 {
 		e.Segments = make([]*Segment, 0)
-	} else {
-		phs, err := parseProgramHeaders(raw, endianness, is32, header.Phoff, header.Phnum, header.Phentsize)
-		if err != nil {
-			return nil, err
-		}
+		return nil
+	}
 
-		e.Segments = make([]*Segment, header.Phnum)
-		for i := 0; i < len(phs); i++ {
-			sgr, err := safeSlice(raw, phs[i].Offset, phs[i].Filesz)
-			if err != nil {
-				return nil, fmt.Errorf("invalid segment %d body: %w", i, err)
-			}
+	phs, err := e.parseProgramHeaders(is32, header.Phoff, phnum, header.Phentsize)
+	if err != nil {
+		if err := e.fail(header.Phoff, err); err != nil {
+			return err
+		}
+	}
 
-			e.Segments[i] = &Segment{
-				Header: phs[i],
-				Raw:    sgr,
+	e.Segments = make([]*Segment, len(phs))
+	e.phdrSize = uint64(len(phs)) * uint64(header.Phentsize)
+	for i := 0; i < len(phs); i++ {
+		sg := &Segment{
+			Header: phs[i],
+		}
+		sg.Raw, sg.sr, err = e.body(phs[i].Offset, phs[i].Filesz)
+		if err != nil {
+			err = fmt.Errorf("invalid segment %d body: %w", i, err)
+			if err := e.fail(phs[i].Offset, err); err != nil {
+				return err
 			}
+			sg.Raw, sg.sr = e.partialBody(phs[i].Offset, phs[i].Filesz)
+			sg.Broken = true
 		}
+
+		e.Segments[i] = sg
 	}
 
-	return e, nil
+	return nil
+}
+
+// body bounds checks the contents of a section or segment and returns a
+// reader over them, along with the slice of Raw when it is set.
+func (e *File) body(offset, size uint64) ([]byte, *io.SectionReader, error) {
+	if err := checkRange(e.size, offset, size); err != nil {
+		return nil, nil, err
+	}
+
+	if e.Raw != nil {
+		raw := e.Raw[offset : offset+size]
+		return raw, io.NewSectionReader(bytes.NewReader(raw), 0, int64(size)), nil
+	}
+
+	return nil, io.NewSectionReader(e.reader, int64(offset), int64(size)), nil
+}
+
+// partialBody is body for the part of [offset:offset+size] that lies within
+// the file, which lenient parsing keeps of sections and segments that extend
+// past its end.
+func (e *File) partialBody(offset, size uint64) ([]byte, *io.SectionReader) {
+	offset = min(offset, e.size)
+	raw, sr, _ := e.body(offset, min(size, e.size-offset))
+
+	return raw, sr
+}
+
+// Data returns the contents of the section. Compressed sections, either
+// SHF_COMPRESSED or legacy .zdebug ones, are decompressed. It is empty for
+// SHT_NOBITS sections.
+func (s *Section) Data() ([]byte, error) {
+	raw, err := s.stored()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.compression(raw)
+	if err != nil || c == nil {
+		return raw, err
+	}
+
+	return c.decompress(raw)
+}
+
+// Open returns a new reader over the contents of the section, decompressed
+// like Data. Compressed sections are decompressed on the first read.
+func (s *Section) Open() io.ReadSeeker {
+	if c, err := s.Compression(); err != nil || c != nil {
+		return &lazyReader{load: s.Data}
+	}
+
+	if s.sr == nil {
+		return bytes.NewReader(s.Raw)
+	}
+
+	return io.NewSectionReader(s.sr, 0, s.sr.Size())
+}
+
+// stored returns the contents of the section as stored in the file.
+func (s *Section) stored() ([]byte, error) {
+	if s.Raw != nil || s.sr == nil {
+		return s.Raw, nil
+	}
+
+	return readAll(s.sr)
+}
+
+// Data returns the file-backed contents of the segment, Filesz bytes long.
+func (sg *Segment) Data() ([]byte, error) {
+	if sg.Raw != nil || sg.sr == nil {
+		return sg.Raw, nil
+	}
+
+	return readAll(sg.sr)
+}
+
+// Open returns a new reader over the file-backed contents of the segment.
+func (sg *Segment) Open() io.ReadSeeker {
+	if sg.sr == nil {
+		return bytes.NewReader(sg.Raw)
+	}
+
+	return io.NewSectionReader(sg.sr, 0, sg.sr.Size())
+}
+
+func readAll(sr *io.SectionReader) ([]byte, error) {
+	buf := make([]byte, sr.Size())
+	if n, err := sr.ReadAt(buf, 0); n != len(buf) {
+		return nil, fmt.Errorf("failed to read %d bytes: %w", len(buf), err)
+	}
+
+	return buf, nil
 }
 
 // SectionByName get a section by name.
@@ -362,9 +769,9 @@ func (e *File) SectionsByType(sht SectionHeaderType) []*Section {
 }
 
 // SectionAt get a setcion by index.
-func (e *File) SectionAt(n uint16) *Section {
+func (e *File) SectionAt(n uint32) *Section {
 	ss := e.Sections
-	if n >= uint16(len(ss)) {
+	if uint64(n) >= uint64(len(ss)) {
 		return nil
 	}
 
@@ -384,15 +791,49 @@ func (e *File) SegmentsByType(pt ProgramHeaderType) []*Segment {
 }
 
 // SegmentAt get a segment by index.
-func (e *File) SegmentAt(n uint16) *Segment {
+func (e *File) SegmentAt(n uint32) *Segment {
 	sgs := e.Segments
-	if n >= uint16(len(sgs)) {
+	if uint64(n) >= uint64(len(sgs)) {
 		return nil
 	}
 
 	return sgs[n]
 }
 
+// is32 reports whether the file uses the ELFCLASS32 layout.
+func (e *File) is32() bool {
+	return e.Header.Ident[EI_CLASS] == 1
+}
+
+// offsetVersion is the file offset of e_version, the same in both classes.
+const offsetVersion = 20
+
+// offsetShstrndx returns the file offset of e_shstrndx.
+func offsetShstrndx(is32 bool) uint64 {
+	if is32 {
+		return 50
+	}
+	return 62
+}
+
+// checkIdent records warnings for the parts of the identification and
+// version that New does not need but that a valid file has.
+func (e *File) checkIdent() {
+	h := e.Header
+	if v := h.Ident[EI_VERSION]; v != EV_CURRENT {
+		e.warnf(uint64(EI_VERSION), "unknown elf identification version: %d", v)
+	}
+	for i := EI_PADDING; i < uint8(len(h.Ident)); i++ {
+		if h.Ident[i] != 0 {
+			e.warnf(uint64(i), "non-zero elf identification padding: %#x", h.Ident[i])
+			break
+		}
+	}
+	if h.Version != uint32(EV_CURRENT) {
+		e.warnf(offsetVersion, "unknown elf version: %d", h.Version)
+	}
+}
+
 func convertToELFHeader(header32 *elfHeader32) ELFHeader {
 	return ELFHeader{
 		Ident:     header32.Ident,
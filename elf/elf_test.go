@@ -1,7 +1,9 @@
 package elf_test
 
 import (
+	"bytes"
 	"encoding/binary"
+	"io"
 	"os"
 	"reflect"
 	"testing"
@@ -166,7 +168,7 @@ func TestSectionAt(t *testing.T) {
 		}
 
 		for i, ts := range tt.sections {
-			s := e.SectionAt(uint16(i))
+			s := e.SectionAt(uint32(i))
 			if s != nil {
 				if ts.Name != s.Name {
 					t.Errorf("%s:\n\thave %#v\n\twant %#v\n", tt.fileName, s.Name, ts.Name)
@@ -181,9 +183,9 @@ func TestSectionAt(t *testing.T) {
 		}
 
 		idx := len(e.Sections)
-		s := e.SectionAt(uint16(idx))
+		s := e.SectionAt(uint32(idx))
 		if s != nil {
-			t.Errorf("%s: returned value of `e.SectionAt(uint16(%d))` should be nil. \n\thave %#v\n", tt.fileName, idx, s)
+			t.Errorf("%s: returned value of `e.SectionAt(uint32(%d))` should be nil. \n\thave %#v\n", tt.fileName, idx, s)
 		}
 	}
 }
@@ -323,6 +325,93 @@ func TestNewValidSynthetic(t *testing.T) {
 	}
 }
 
+func TestIdent(t *testing.T) {
+	raw, err := os.ReadFile(hello)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := e.Header
+	if h.Class() != elf.ELFCLASS64 || h.Data() != elf.ELFDATA2LSB || h.OSABI() != elf.ELFOSABI_NONE || h.ABIVersion() != 0 {
+		t.Errorf("have %v %v %v %d", h.Class(), h.Data(), h.OSABI(), h.ABIVersion())
+	}
+	if len(e.Diagnostics) != 0 {
+		t.Errorf("unexpected diagnostics: %v", e.Diagnostics)
+	}
+
+	b := elf.NewBuilder(elf.ELFCLASS32, binary.BigEndian, elf.EM_PPC, elf.ET_EXEC)
+	b.Header.Ident[elf.EI_OSABI] = byte(elf.ELFOSABI_FREEBSD)
+	b.Header.Ident[elf.EI_ABIVERSION] = 1
+	if raw, err = b.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if e, err = elf.New(raw); err != nil {
+		t.Fatal(err)
+	}
+	h = e.Header
+	if h.Class() != elf.ELFCLASS32 || h.Data() != elf.ELFDATA2MSB || h.OSABI() != elf.ELFOSABI_FREEBSD || h.ABIVersion() != 1 {
+		t.Errorf("have %v %v %v %d", h.Class(), h.Data(), h.OSABI(), h.ABIVersion())
+	}
+	if s := h.OSABI().String(); s != "ELFOSABI_FREEBSD" {
+		t.Errorf("have %q", s)
+	}
+
+	// A wrong version and padding are reported but do not stop parsing.
+	raw[elf.EI_VERSION] = 2
+	raw[12] = 0x5a
+	binary.BigEndian.PutUint32(raw[20:], 0)
+	if e, err = elf.New(raw); err != nil {
+		t.Fatal(err)
+	}
+	want := []elf.Diagnostic{
+		{Severity: elf.SeverityWarning, Offset: 6, Message: "unknown elf identification version: 2"},
+		{Severity: elf.SeverityWarning, Offset: 12, Message: "non-zero elf identification padding: 0x5a"},
+		{Severity: elf.SeverityWarning, Offset: 20, Message: "unknown elf version: 0"},
+	}
+	if !reflect.DeepEqual(e.Diagnostics, want) {
+		t.Errorf("diagnostics:\n\thave %v\n\twant %v\n", e.Diagnostics, want)
+	}
+	if s := want[0].String(); s != "warning at offset 0x6: unknown elf identification version: 2" {
+		t.Errorf("have %q", s)
+	}
+}
+
+func TestNewExtendedNumbering(t *testing.T) {
+	le := binary.LittleEndian
+	sh0 := uint64(64) // section header 0 of validELF64
+
+	raw := validELF64()
+	le.PutUint16(raw[60:], 0)                // e_shnum = 0
+	le.PutUint16(raw[62:], 0xffff)           // e_shstrndx = SHN_XINDEX
+	le.PutUint64(raw[sh0+32:], 2)            // sh_size = real e_shnum
+	le.PutUint32(raw[sh0+40:], 1)            // sh_link = real e_shstrndx
+	le.PutUint32(raw[sh0+44:], 1)            // sh_info = real e_phnum
+	le.PutUint16(raw[54:], 56)               // e_phentsize
+	le.PutUint16(raw[56:], 0xffff)           // e_phnum = PN_XNUM
+	le.PutUint64(raw[32:], uint64(len(raw))) // e_phoff
+	ph := make([]byte, 56)
+	le.PutUint32(ph[0:], 1) // p_type = PT_LOAD
+	raw = append(raw, ph...)
+
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(e.Sections) != 2 || e.Sections[1].Name != ".shstrtab" {
+		t.Errorf("have %d sections, want 2 with .shstrtab at index 1", len(e.Sections))
+	}
+	if len(e.Segments) != 1 || e.Segments[0].Header.Type != elf.PT_LOAD {
+		t.Errorf("have %d segments, want a single PT_LOAD", len(e.Segments))
+	}
+	if e.Header.Shnum != 0 || e.Header.Shstrndx != elf.SHN_XINDEX || e.Header.Phnum != elf.PN_XNUM {
+		t.Errorf("header fields should be kept as stored in the file: %#v", e.Header)
+	}
+}
+
 func TestNewMalformed(t *testing.T) {
 	le := binary.LittleEndian
 
@@ -340,10 +429,27 @@ func TestNewMalformed(t *testing.T) {
 			},
 		},
 		{
-			name: "shstrndx is SHN_XINDEX",
+			name: "shstrndx is SHN_XINDEX without sh_link",
+			build: func() []byte {
+				raw := validELF64()
+				le.PutUint16(raw[62:], 0xffff) // e_shstrndx = SHN_XINDEX, section 0 sh_link = 0
+				return raw
+			},
+		},
+		{
+			name: "extended section number too large",
 			build: func() []byte {
 				raw := validELF64()
-				le.PutUint16(raw[62:], 0xffff) // e_shstrndx = SHN_XINDEX
+				le.PutUint16(raw[60:], 0)             // e_shnum = 0
+				le.PutUint64(raw[64+32:], 0xffffffff) // section 0 sh_size
+				return raw
+			},
+		},
+		{
+			name: "PN_XNUM without section headers",
+			build: func() []byte {
+				raw := validELF64WithSegment()
+				le.PutUint16(raw[56:], 0xffff) // e_phnum = PN_XNUM
 				return raw
 			},
 		},
@@ -431,6 +537,89 @@ func TestNewMalformed(t *testing.T) {
 	}
 }
 
+func TestNewLenient(t *testing.T) {
+	le := binary.LittleEndian
+	raw, err := os.ReadFile(hello)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lenient := elf.Options{Lenient: true}
+
+	e, err := elf.NewWithOptions(raw, lenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Sections) != 33 || len(e.Segments) != 13 || len(e.Diagnostics) != 0 {
+		t.Errorf("valid file: %d sections, %d segments, diagnostics %v", len(e.Sections), len(e.Segments), e.Diagnostics)
+	}
+
+	// Only the first 10 section headers are left, without .shstrtab.
+	shoff := e.Header.Shoff
+	if e, err = elf.NewWithOptions(raw[:shoff+10*64], lenient); err != nil {
+		t.Fatal(err)
+	}
+	want := []elf.Diagnostic{
+		{Severity: elf.SeverityError, Offset: shoff, Message: "section header table truncated to 10 of 33 entries"},
+		{Severity: elf.SeverityError, Offset: 62, Message: "invalid section header string table index: 32"},
+	}
+	if !reflect.DeepEqual(e.Diagnostics, want) {
+		t.Errorf("cut section headers:\n\thave %v\n\twant %v\n", e.Diagnostics, want)
+	}
+	if len(e.Sections) != 10 || e.Sections[1].Header.Type != elf.SHT_PROGBITS || e.Sections[1].Name != "" || e.Sections[1].Broken {
+		t.Errorf("cut section headers: %d sections, section 1 %#v", len(e.Sections), e.Sections[1])
+	}
+
+	// A download cut in the middle keeps the program headers and the part
+	// of every segment before the cut.
+	const size = 8000
+	if e, err = elf.NewWithOptions(raw[:size], lenient); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Sections) != 0 || len(e.Segments) != 13 {
+		t.Fatalf("cut file: %d sections, %d segments", len(e.Sections), len(e.Segments))
+	}
+	broken := 0
+	for i, sg := range e.Segments {
+		h := sg.Header
+		if wantBroken := h.Offset+h.Filesz > size; sg.Broken != wantBroken {
+			t.Errorf("segment %d: broken %v, want %v", i, sg.Broken, wantBroken)
+		}
+		if sg.Broken {
+			broken++
+			if n := max(size, h.Offset) - h.Offset; uint64(len(sg.Raw)) != n {
+				t.Errorf("segment %d: %d bytes kept, want %d", i, len(sg.Raw), n)
+			}
+		}
+	}
+	if broken == 0 || len(e.Diagnostics) != broken+1 {
+		t.Errorf("cut file: %d broken segments, diagnostics %v", broken, e.Diagnostics)
+	}
+
+	// The problems New rejects in single sections.
+	bad := validELF64()
+	le.PutUint32(bad[64+64:], 1000) // sh_name of section 1
+	if e, err = elf.NewWithOptions(bad, lenient); err != nil {
+		t.Fatal(err)
+	}
+	if s := e.Sections[1]; s.Name != "" || !s.Broken || len(s.Raw) != 11 || len(e.Diagnostics) != 1 || e.Diagnostics[0].Offset != 128 {
+		t.Errorf("bad name: %#v, diagnostics %v", s, e.Diagnostics)
+	}
+
+	bad = validELF64()
+	le.PutUint64(bad[64+64+32:], 0xffffffff) // sh_size of section 1
+	if e, err = elf.NewWithOptions(bad, lenient); err != nil {
+		t.Fatal(err)
+	}
+	// The string table is cut too, but still holds the names.
+	if s := e.Sections[1]; s.Name != ".shstrtab" || !s.Broken || string(s.Raw) != "\x00.shstrtab\x00" || len(e.Diagnostics) != 2 {
+		t.Errorf("bad size: %#v, diagnostics %v", s, e.Diagnostics)
+	}
+
+	if _, err := elf.NewWithOptions([]byte(elf.ELF_MAGIC), lenient); err == nil {
+		t.Errorf("expected error for a file without an ELF header")
+	}
+}
+
 func TestSegmentAt(t *testing.T) {
 	for _, tt := range tests {
 		b, err := os.ReadFile(tt.fileName)
@@ -444,7 +633,7 @@ func TestSegmentAt(t *testing.T) {
 		}
 
 		for i, ts := range tt.segments {
-			s := e.SegmentAt(uint16(i))
+			s := e.SegmentAt(uint32(i))
 			if s != nil {
 				if !reflect.DeepEqual(ts.Header, s.Header) {
 					t.Errorf("%s:\n\thave %#v\n\twant %#v\n", tt.fileName, s.Header, ts.Header)
@@ -455,9 +644,254 @@ func TestSegmentAt(t *testing.T) {
 		}
 
 		idx := len(e.Segments)
-		s := e.SegmentAt(uint16(idx))
+		s := e.SegmentAt(uint32(idx))
 		if s != nil {
-			t.Errorf("%s: returned value of `e.SegmentAt(uint16(%d))` should be nil. \n\thave %#v\n", tt.fileName, idx, s)
+			t.Errorf("%s: returned value of `e.SegmentAt(uint32(%d))` should be nil. \n\thave %#v\n", tt.fileName, idx, s)
 		}
 	}
 }
+
+// synthSection describes a section for synthELF. Sections are numbered from
+// 1 in the order given; synthELF appends .shstrtab as the last section.
+type synthSection struct {
+	name    string
+	typ     elf.SectionHeaderType
+	flags   elf.SectionFlag
+	addr    uint64
+	link    uint32
+	info    uint32
+	align   uint64
+	entsize uint64
+	data    []byte
+}
+
+// synthELF builds a relocatable ELF of the requested class and byte order
+// holding the given sections, so tests can exercise the 32-bit and
+// big-endian decoding paths without binary fixtures.
+func synthELF(is32 bool, bo binary.ByteOrder, secs []synthSection) []byte {
+	ehsize, shentsz := 64, 64
+	if is32 {
+		ehsize, shentsz = 52, 40
+	}
+
+	shstrtab := []byte{0}
+	names := make([]uint32, len(secs)+1)
+	for i, s := range secs {
+		names[i] = uint32(len(shstrtab))
+		shstrtab = append(append(shstrtab, s.name...), 0)
+	}
+	names[len(secs)] = uint32(len(shstrtab))
+	shstrtab = append(shstrtab, ".shstrtab\x00"...)
+	secs = append(secs, synthSection{typ: elf.SHT_STRTAB, data: shstrtab})
+
+	raw := make([]byte, ehsize)
+	offsets := make([]uint64, len(secs))
+	for i, s := range secs {
+		for len(raw)%8 != 0 {
+			raw = append(raw, 0)
+		}
+		offsets[i] = uint64(len(raw))
+		if s.typ != elf.SHT_NOBITS {
+			raw = append(raw, s.data...)
+		}
+	}
+	for len(raw)%8 != 0 {
+		raw = append(raw, 0)
+	}
+	shoff := uint64(len(raw))
+	shnum := len(secs) + 1
+	raw = append(raw, make([]byte, shentsz*shnum)...)
+
+	copy(raw[0:4], []byte(elf.ELF_MAGIC))
+	raw[4] = 2
+	if is32 {
+		raw[4] = 1
+	}
+	raw[5] = 1
+	if bo == binary.BigEndian {
+		raw[5] = 2
+	}
+	raw[6] = 1
+
+	bo.PutUint16(raw[16:], uint16(elf.ET_REL))
+	bo.PutUint16(raw[18:], uint16(elf.EM_X86_64))
+	bo.PutUint32(raw[20:], 1)
+	if is32 {
+		bo.PutUint32(raw[32:], uint32(shoff))
+		bo.PutUint16(raw[40:], uint16(ehsize))
+		bo.PutUint16(raw[46:], uint16(shentsz))
+		bo.PutUint16(raw[48:], uint16(shnum))
+		bo.PutUint16(raw[50:], uint16(shnum-1))
+	} else {
+		bo.PutUint64(raw[40:], shoff)
+		bo.PutUint16(raw[52:], uint16(ehsize))
+		bo.PutUint16(raw[58:], uint16(shentsz))
+		bo.PutUint16(raw[60:], uint16(shnum))
+		bo.PutUint16(raw[62:], uint16(shnum-1))
+	}
+
+	for i, s := range secs {
+		sh := raw[shoff+uint64((i+1)*shentsz):]
+		size := uint64(len(s.data))
+		if is32 {
+			bo.PutUint32(sh[0:], names[i])
+			bo.PutUint32(sh[4:], uint32(s.typ))
+			bo.PutUint32(sh[8:], uint32(s.flags))
+			bo.PutUint32(sh[12:], uint32(s.addr))
+			bo.PutUint32(sh[16:], uint32(offsets[i]))
+			bo.PutUint32(sh[20:], uint32(size))
+			bo.PutUint32(sh[24:], s.link)
+			bo.PutUint32(sh[28:], s.info)
+			bo.PutUint32(sh[32:], uint32(s.align))
+			bo.PutUint32(sh[36:], uint32(s.entsize))
+		} else {
+			bo.PutUint32(sh[0:], names[i])
+			bo.PutUint32(sh[4:], uint32(s.typ))
+			bo.PutUint64(sh[8:], uint64(s.flags))
+			bo.PutUint64(sh[16:], s.addr)
+			bo.PutUint64(sh[24:], offsets[i])
+			bo.PutUint64(sh[32:], size)
+			bo.PutUint32(sh[40:], s.link)
+			bo.PutUint32(sh[44:], s.info)
+			bo.PutUint64(sh[48:], s.align)
+			bo.PutUint64(sh[56:], s.entsize)
+		}
+	}
+
+	return raw
+}
+
+// countingReaderAt records how many bytes are read through it.
+type countingReaderAt struct {
+	r io.ReaderAt
+	n int
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := c.r.ReadAt(p, off)
+	c.n += n
+	return n, err
+}
+
+func TestNewFromReaderAt(t *testing.T) {
+	for _, tt := range tests {
+		b, err := os.ReadFile(tt.fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want, err := elf.New(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cr := &countingReaderAt{r: bytes.NewReader(b)}
+		e, err := elf.NewFromReaderAt(cr, int64(len(b)))
+		if err != nil {
+			t.Fatalf("[%s] expected no error: %s", tt.fileName, err)
+		}
+
+		if cr.n > 4096 {
+			t.Errorf("%s: parsing the headers read %d bytes", tt.fileName, cr.n)
+		}
+		if e.Raw != nil {
+			t.Errorf("%s: Raw should not be set for lazily parsed files", tt.fileName)
+		}
+		if !reflect.DeepEqual(want.Header, e.Header) {
+			t.Errorf("%s:\n\thave %#v\n\twant %#v\n", tt.fileName, e.Header, want.Header)
+		}
+
+		for i, s := range e.Sections {
+			if s.Name != want.Sections[i].Name || s.Raw != nil {
+				t.Errorf("%s: section %d should be %s without Raw", tt.fileName, i, want.Sections[i].Name)
+			}
+
+			// Data and Open decompress .zdebug sections, so compare
+			// them with Data of the eagerly parsed file.
+			wantData, err := want.Sections[i].Data()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := s.Data()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, wantData) {
+				t.Errorf("%s: Data of section %s differs from New", tt.fileName, s.Name)
+			}
+
+			opened, err := io.ReadAll(s.Open())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(opened, wantData) {
+				t.Errorf("%s: Open of section %s differs from New", tt.fileName, s.Name)
+			}
+		}
+
+		for i, sg := range e.Segments {
+			data, err := sg.Data()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, want.Segments[i].Raw) {
+				t.Errorf("%s: Data of segment %d differs from Raw", tt.fileName, i)
+			}
+		}
+
+		syms, err := e.Symbols()
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantSyms, err := want.Symbols()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(syms, wantSyms) {
+			t.Errorf("%s: symbols of lazily parsed file differ", tt.fileName)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	for _, open := range []func(string) (*elf.File, error){elf.Open, elf.OpenMmap} {
+		for _, tt := range tests {
+			e, err := open(tt.fileName)
+			if err != nil {
+				t.Fatalf("[%s] expected no error: %s", tt.fileName, err)
+			}
+
+			if !reflect.DeepEqual(tt.header, e.Header) {
+				t.Errorf("%s:\n\thave %#v\n\twant %#v\n", tt.fileName, e.Header, tt.header)
+			}
+
+			s := e.SectionByName(".note.go.buildid")
+			data, err := s.Data()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if uint64(len(data)) != s.Header.Size {
+				t.Errorf("%s: have %d bytes, want %d", tt.fileName, len(data), s.Header.Size)
+			}
+
+			if err := e.Close(); err != nil {
+				t.Errorf("%s: failed to close: %s", tt.fileName, err)
+			}
+		}
+	}
+
+	if _, err := elf.Open("../testdata/sample.go"); err == nil {
+		t.Error("expected error for a non-ELF file")
+	}
+}
+
+func TestNewFromReaderAtMalformed(t *testing.T) {
+	raw := validELF64()
+	le := binary.LittleEndian
+	le.PutUint64(raw[64+64+32:], 0xffffffff) // .shstrtab sh_size huge
+
+	if _, err := elf.NewFromReaderAt(bytes.NewReader(raw), int64(len(raw))); err == nil {
+		t.Fatal("expected error for section body out of bounds")
+	}
+}
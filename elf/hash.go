@@ -0,0 +1,344 @@
+package elf
+
+import (
+	"fmt"
+)
+
+// HashTable is the SysV symbol hash table of a SHT_HASH section, also
+// located by DT_HASH. Chains has an entry for every dynamic symbol.
+type HashTable struct {
+	Buckets []uint32
+	Chains  []uint32
+}
+
+// GNUHashTable is the GNU symbol hash table of a SHT_GNU_HASH section, also
+// located by DT_GNU_HASH. It only covers the dynamic symbols from SymOffset
+// on, which the linker sorts by bucket.
+type GNUHashTable struct {
+	SymOffset  uint32
+	BloomShift uint32
+	// Bloom holds the words of the bloom filter, which are 32 or 64 bits
+	// wide like the addresses of the file.
+	Bloom   []uint64
+	Buckets []uint32
+	// Chains holds the hashes of the symbols from SymOffset on, with the
+	// lowest bit replaced by 1 on the last symbol of each bucket.
+	Chains []uint32
+
+	wordBits uint32
+}
+
+const gnuHashHeaderSize = 16
+
+// SysVHash returns the hash of name used by SHT_HASH tables.
+func SysVHash(name string) uint32 {
+	var h uint32
+	for i := 0; i < len(name); i++ {
+		h = h<<4 + uint32(name[i])
+		g := h & 0xf0000000
+		h ^= g >> 24
+		h &^= g
+	}
+
+	return h
+}
+
+// GNUHash returns the hash of name used by SHT_GNU_HASH tables.
+func GNUHash(name string) uint32 {
+	h := uint32(5381)
+	for i := 0; i < len(name); i++ {
+		h = h*33 + uint32(name[i])
+	}
+
+	return h
+}
+
+// HashTable decodes the SHT_HASH section, or the table DT_HASH points to in
+// files without one. It returns nil if the file has neither.
+func (e *File) HashTable() (*HashTable, error) {
+	var raw []byte
+	if ss := e.SectionsByType(SHT_HASH); len(ss) > 0 {
+		var err error
+		if raw, err = ss[0].Data(); err != nil {
+			return nil, fmt.Errorf("failed to read hash table %s: %w", ss[0].Name, err)
+		}
+	} else {
+		des, err := e.DynamicEntries()
+		if err != nil {
+			return nil, err
+		}
+		addr, ok := findDynValue(des, DT_HASH)
+		if !ok {
+			return nil, nil
+		}
+		hdr, err := e.ReadAddr(addr, 8)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read hash table: %w", err)
+		}
+		n := 2 + uint64(e.Endianness.Uint32(hdr)) + uint64(e.Endianness.Uint32(hdr[4:]))
+		if raw, err = e.ReadAddr(addr, n*4); err != nil {
+			return nil, fmt.Errorf("failed to read hash table: %w", err)
+		}
+	}
+
+	if len(raw) < 8 {
+		return nil, fmt.Errorf("hash table too short: %d bytes", len(raw))
+	}
+	nbucket := uint64(e.Endianness.Uint32(raw))
+	nchain := uint64(e.Endianness.Uint32(raw[4:]))
+	if (2+nbucket+nchain)*4 > uint64(len(raw)) {
+		return nil, fmt.Errorf("hash table with %d buckets and %d chains exceeds %d bytes", nbucket, nchain, len(raw))
+	}
+
+	return &HashTable{
+		Buckets: e.words32(raw[8:], nbucket),
+		Chains:  e.words32(raw[8+nbucket*4:], nchain),
+	}, nil
+}
+
+// GNUHashTable decodes the SHT_GNU_HASH section, or the table DT_GNU_HASH
+// points to in files without one. It returns nil if the file has neither.
+func (e *File) GNUHashTable() (*GNUHashTable, error) {
+	wordSize := uint64(8)
+	if e.is32() {
+		wordSize = 4
+	}
+
+	var raw []byte
+	if ss := e.SectionsByType(SHT_GNU_HASH); len(ss) > 0 {
+		var err error
+		if raw, err = ss[0].Data(); err != nil {
+			return nil, fmt.Errorf("failed to read hash table %s: %w", ss[0].Name, err)
+		}
+	} else {
+		des, err := e.DynamicEntries()
+		if err != nil {
+			return nil, err
+		}
+		addr, ok := findDynValue(des, DT_GNU_HASH)
+		if !ok {
+			return nil, nil
+		}
+		if raw, err = e.readGNUHashTable(addr, wordSize); err != nil {
+			return nil, fmt.Errorf("failed to read gnu hash table: %w", err)
+		}
+	}
+
+	if len(raw) < gnuHashHeaderSize {
+		return nil, fmt.Errorf("gnu hash table too short: %d bytes", len(raw))
+	}
+	nbucket := uint64(e.Endianness.Uint32(raw))
+	bloomSize := uint64(e.Endianness.Uint32(raw[8:]))
+	t := &GNUHashTable{
+		SymOffset:  e.Endianness.Uint32(raw[4:]),
+		BloomShift: e.Endianness.Uint32(raw[12:]),
+		wordBits:   uint32(wordSize * 8),
+	}
+
+	chains := gnuHashHeaderSize + bloomSize*wordSize + nbucket*4
+	if bloomSize > uint64(len(raw)) || nbucket > uint64(len(raw)) || chains > uint64(len(raw)) {
+		return nil, fmt.Errorf("gnu hash table with %d buckets and %d bloom words exceeds %d bytes", nbucket, bloomSize, len(raw))
+	}
+	t.Bloom = make([]uint64, bloomSize)
+	for i := range t.Bloom {
+		off := gnuHashHeaderSize + uint64(i)*wordSize
+		if wordSize == 4 {
+			t.Bloom[i] = uint64(e.Endianness.Uint32(raw[off:]))
+		} else {
+			t.Bloom[i] = e.Endianness.Uint64(raw[off:])
+		}
+	}
+	t.Buckets = e.words32(raw[chains-nbucket*4:], nbucket)
+	t.Chains = e.words32(raw[chains:], (uint64(len(raw))-chains)/4)
+
+	return t, nil
+}
+
+// readGNUHashTable reads the GNU hash table at addr. Its size is not
+// recorded anywhere, so the chain of the last bucket is followed to its end
+// like tools locating the dynamic symbols without section headers do.
+func (e *File) readGNUHashTable(addr, wordSize uint64) ([]byte, error) {
+	hdr, err := e.ReadAddr(addr, gnuHashHeaderSize)
+	if err != nil {
+		return nil, err
+	}
+	nbucket := uint64(e.Endianness.Uint32(hdr))
+	symOffset := uint64(e.Endianness.Uint32(hdr[4:]))
+	bloomSize := uint64(e.Endianness.Uint32(hdr[8:]))
+
+	chains := addr + gnuHashHeaderSize + bloomSize*wordSize + nbucket*4
+	buckets, err := e.ReadAddr(chains-nbucket*4, nbucket*4)
+	if err != nil {
+		return nil, err
+	}
+	last := uint64(0)
+	for i := uint64(0); i < nbucket; i++ {
+		last = max(last, uint64(e.Endianness.Uint32(buckets[i*4:])))
+	}
+
+	n := uint64(0)
+	if last >= symOffset {
+		// The chains are only followed within the file contents of their
+		// segment, not into the zero-filled rest of it.
+		sg := e.SegmentForAddr(chains)
+		if sg == nil || chains-sg.Header.Vaddr >= sg.Header.Filesz {
+			return nil, fmt.Errorf("gnu hash chains at %#x are not backed by the file", chains)
+		}
+		rest, err := e.ReadAddr(chains, sg.Header.Vaddr+sg.Header.Filesz-chains)
+		if err != nil {
+			return nil, err
+		}
+		for n = last - symOffset; ; n++ {
+			if n >= uint64(len(rest))/4 {
+				return nil, fmt.Errorf("gnu hash chain of symbol %d runs past the end of its segment", last)
+			}
+			if e.Endianness.Uint32(rest[n*4:])&1 != 0 {
+				break
+			}
+		}
+		n++
+	}
+
+	return e.ReadAddr(addr, chains-addr+n*4)
+}
+
+func (e *File) words32(raw []byte, n uint64) []uint32 {
+	ws := make([]uint32, n)
+	for i := range ws {
+		ws[i] = e.Endianness.Uint32(raw[i*4:])
+	}
+
+	return ws
+}
+
+// symbolCount returns the number of dynamic symbols, which the GNU hash
+// table covers up to the end of its last chain.
+func (t *GNUHashTable) symbolCount() uint64 {
+	return uint64(t.SymOffset) + uint64(len(t.Chains))
+}
+
+// Lookup returns the index of the symbol of syms named name that an
+// object with this hash table defines, walking the bucket of its hash the
+// way ld.so does.
+func (t *HashTable) Lookup(name string, syms []*Symbol) (uint32, bool) {
+	if len(t.Buckets) == 0 {
+		return 0, false
+	}
+
+	h := SysVHash(name)
+	i := t.Buckets[h%uint32(len(t.Buckets))]
+	// A chain never visits more entries than there are, unless it loops.
+	for n := 0; i != 0 && n < len(t.Chains); n++ {
+		if int(i) >= len(t.Chains) || int(i) >= len(syms) {
+			return 0, false
+		}
+		if matchSymbol(syms[i], name) {
+			return i, true
+		}
+		i = t.Chains[i]
+	}
+
+	return 0, false
+}
+
+// Lookup returns the index of the symbol of syms named name that an
+// object with this hash table defines. Like ld.so, it first checks the
+// bloom filter, then compares the hashes of the bucket of name before
+// comparing any names.
+func (t *GNUHashTable) Lookup(name string, syms []*Symbol) (uint32, bool) {
+	if len(t.Buckets) == 0 || len(t.Bloom) == 0 {
+		return 0, false
+	}
+
+	bits := t.wordBits
+	if bits == 0 {
+		bits = 64
+	}
+	h := GNUHash(name)
+	word := t.Bloom[(h/bits)%uint32(len(t.Bloom))]
+	mask := uint64(1)<<(h%bits) | uint64(1)<<((h>>t.BloomShift)%bits)
+	if word&mask != mask {
+		return 0, false
+	}
+
+	i := t.Buckets[h%uint32(len(t.Buckets))]
+	if i < t.SymOffset {
+		return 0, false
+	}
+	for ; int(i-t.SymOffset) < len(t.Chains); i++ {
+		h2 := t.Chains[i-t.SymOffset]
+		if h|1 == h2|1 && int(i) < len(syms) && matchSymbol(syms[i], name) {
+			return i, true
+		}
+		if h2&1 != 0 {
+			break
+		}
+	}
+
+	return 0, false
+}
+
+// matchSymbol reports whether sym is a definition of name that ld.so
+// would bind to when no version is asked for. Like ld.so, it skips local
+// symbols and symbols without a value, unless they are absolute or
+// thread-local.
+func matchSymbol(sym *Symbol, name string) bool {
+	if sym.Name != name || sym.Section == SHN_UNDEF || sym.VersionHidden {
+		return false
+	}
+	if sym.Value == 0 && sym.Section != SHN_ABS && sym.Type != STT_TLS {
+		return false
+	}
+	switch sym.Bind {
+	case STB_GLOBAL, STB_WEAK, STB_GNU_UNIQUE:
+	default:
+		return false
+	}
+
+	switch sym.Type {
+	case STT_NOTYPE, STT_OBJECT, STT_FUNC, STT_COMMON, STT_TLS, STT_GNU_IFUNC:
+		return true
+	}
+
+	return false
+}
+
+// LookupDynamicSymbol returns the dynamic symbol named name that the file
+// defines, or nil if it defines none. The GNU hash table is used if there
+// is one, then the SysV one, and otherwise every dynamic symbol is checked.
+func (e *File) LookupDynamicSymbol(name string) (*Symbol, error) {
+	syms, err := e.DynamicSymbols()
+	if err != nil || syms == nil {
+		return nil, err
+	}
+
+	gnu, err := e.GNUHashTable()
+	if err != nil {
+		return nil, err
+	}
+	if gnu != nil {
+		if i, ok := gnu.Lookup(name, syms); ok {
+			return syms[i], nil
+		}
+		return nil, nil
+	}
+
+	sysv, err := e.HashTable()
+	if err != nil {
+		return nil, err
+	}
+	if sysv != nil {
+		if i, ok := sysv.Lookup(name, syms); ok {
+			return syms[i], nil
+		}
+		return nil, nil
+	}
+
+	for _, sym := range syms {
+		if matchSymbol(sym, name) {
+			return sym, nil
+		}
+	}
+
+	return nil, nil
+}
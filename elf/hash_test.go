@@ -0,0 +1,227 @@
+package elf_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+func TestHashFunctions(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		sysv, gnu uint32
+	}{
+		{"", 0, 5381},
+		{"printf", 0x077905a6, 0x156b2bb8},
+		{"exit", 0x0006cf04, 0x7c967e3f},
+	} {
+		if h := elf.SysVHash(tt.name); h != tt.sysv {
+			t.Errorf("SysVHash(%q): have %#x, want %#x", tt.name, h, tt.sysv)
+		}
+		if h := elf.GNUHash(tt.name); h != tt.gnu {
+			t.Errorf("GNUHash(%q): have %#x, want %#x", tt.name, h, tt.gnu)
+		}
+	}
+}
+
+func TestHashTables(t *testing.T) {
+	e, err := elf.New(mustReadFile(t, "../testdata/hello_linux_amd64"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	syms, err := e.DynamicSymbols()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := e.HashTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h == nil || len(h.Buckets) != 3 || len(h.Chains) != len(syms) {
+		t.Fatalf("have hash table %#v for %d symbols", h, len(syms))
+	}
+
+	gh, err := e.GNUHashTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gh == nil || len(gh.Buckets) != 2 || int(gh.SymOffset)+len(gh.Chains) > len(syms) {
+		t.Fatalf("have gnu hash table %#v for %d symbols", gh, len(syms))
+	}
+
+	// The chains of a GNU hash table hold the hashes of the symbols.
+	lib, err := elf.New(mustReadFile(t, libsample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gh, err = lib.GNUHashTable(); err != nil {
+		t.Fatal(err)
+	}
+	if syms, err = lib.DynamicSymbols(); err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range gh.Chains {
+		sym := syms[int(gh.SymOffset)+i]
+		if c|1 != elf.GNUHash(sym.Name)|1 {
+			t.Errorf("%s: have chain hash %#x, want %#x", sym.Name, c, elf.GNUHash(sym.Name))
+		}
+	}
+}
+
+func TestLookupDynamicSymbol(t *testing.T) {
+	for _, name := range []string{libsample, "../testdata/libsample_linux_amd64_sysv.so"} {
+		b := mustReadFile(t, name)
+
+		// Without section headers, the tables and the dynamic symbols are
+		// found through the dynamic section.
+		stripped := append([]byte(nil), b...)
+		le := binary.LittleEndian
+		le.PutUint64(stripped[40:], 0) // e_shoff
+		le.PutUint16(stripped[60:], 0) // e_shnum
+		le.PutUint16(stripped[62:], 0) // e_shstrndx
+
+		for _, raw := range [][]byte{b, stripped} {
+			e, err := elf.New(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for sym, want := range map[string]uint64{
+				"sample_hypot": 0x1130,
+				"sample_print": 0x1150,
+				"printf":       0, // undefined
+				"missing":      0,
+			} {
+				s, err := e.LookupDynamicSymbol(sym)
+				if err != nil {
+					t.Fatalf("%s: %s", name, err)
+				}
+				if want == 0 && s != nil || want != 0 && (s == nil || s.Name != sym || s.Value != want) {
+					t.Errorf("%s: LookupDynamicSymbol(%q): have %#v, want value %#x", name, sym, s, want)
+				}
+			}
+
+			syms, err := e.DynamicSymbols()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(syms) != 9 {
+				t.Errorf("%s: have %d dynamic symbols, want 9", name, len(syms))
+			}
+		}
+	}
+}
+
+func TestLookupDynamicSymbolStatic(t *testing.T) {
+	e, err := elf.New(mustReadFile(t, "../testdata/elf_linux_amd64"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if h, err := e.HashTable(); h != nil || err != nil {
+		t.Errorf("HashTable: have %#v, %v", h, err)
+	}
+	if gh, err := e.GNUHashTable(); gh != nil || err != nil {
+		t.Errorf("GNUHashTable: have %#v, %v", gh, err)
+	}
+	if s, err := e.LookupDynamicSymbol("main"); s != nil || err != nil {
+		t.Errorf("LookupDynamicSymbol: have %#v, %v", s, err)
+	}
+}
+
+func TestHashTableMalformed(t *testing.T) {
+	b := mustReadFile(t, "../testdata/libsample_linux_amd64_sysv.so")
+
+	// Claim more chains than the .hash section at 0x260 holds.
+	binary.LittleEndian.PutUint32(b[0x264:], 0xffff)
+	e, err := elf.New(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.HashTable(); err == nil {
+		t.Errorf("an oversized hash table should fail")
+	}
+}
+
+func TestGNUHashTableUnterminated(t *testing.T) {
+	raw := mustReadFile(t, libsample)
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gh := e.SectionByName(".gnu.hash")
+	sg := e.SegmentForAddr(gh.Header.Addr)
+	i := 0
+	for e.Segments[i] != sg {
+		i++
+	}
+
+	// Clear the chains up to the end of the segment contents, whose zero
+	// filled part is made huge, so that the last chain never ends.
+	bo := binary.LittleEndian
+	nbucket, bloomSize := uint64(bo.Uint32(raw[gh.Header.Offset:])), uint64(bo.Uint32(raw[gh.Header.Offset+8:]))
+	chains := gh.Header.Offset + 16 + bloomSize*8 + nbucket*4
+	clear(raw[chains : sg.Header.Offset+sg.Header.Filesz])
+	bo.PutUint64(raw[e.Header.Phoff+uint64(i)*56+40:], 1<<40) // p_memsz
+	if e, err = elf.New(raw); err != nil {
+		t.Fatal(err)
+	}
+	e.Sections = nil
+
+	if _, err := e.GNUHashTable(); err == nil {
+		t.Errorf("an unterminated chain should fail")
+	}
+}
+
+func TestLookupMatch(t *testing.T) {
+	for _, name := range []string{libsample, "../testdata/libsample_linux_amd64_sysv.so"} {
+		e, err := elf.New(mustReadFile(t, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gnu, err := e.GNUHashTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sysv, err := e.HashTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		lookup := func(syms []*elf.Symbol) bool {
+			if gnu != nil {
+				_, ok := gnu.Lookup("sample_hypot", syms)
+				return ok
+			}
+			_, ok := sysv.Lookup("sample_hypot", syms)
+			return ok
+		}
+
+		for _, tt := range []struct {
+			desc string
+			set  func(*elf.Symbol)
+			want bool
+		}{
+			{"local", func(s *elf.Symbol) { s.Bind = elf.STB_LOCAL }, false},
+			{"weak", func(s *elf.Symbol) { s.Bind = elf.STB_WEAK }, true},
+			{"unique", func(s *elf.Symbol) { s.Bind = elf.STB_GNU_UNIQUE }, true},
+			{"zero value", func(s *elf.Symbol) { s.Value = 0 }, false},
+			{"absolute zero value", func(s *elf.Symbol) { s.Value, s.Section = 0, elf.SHN_ABS }, true},
+			{"thread-local zero value", func(s *elf.Symbol) { s.Value, s.Type = 0, elf.STT_TLS }, true},
+		} {
+			syms, err := e.DynamicSymbols()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range syms {
+				if s.Name == "sample_hypot" {
+					tt.set(s)
+				}
+			}
+			if ok := lookup(syms); ok != tt.want {
+				t.Errorf("%s: %s symbol found: %v, want %v", name, tt.desc, ok, tt.want)
+			}
+		}
+	}
+}
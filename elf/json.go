@@ -0,0 +1,271 @@
+package elf
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonFile is the JSON encoding of a File, described at MarshalJSON.
+type jsonFile struct {
+	Header         jsonHeader    `json:"header"`
+	Sections       []jsonSection `json:"sections"`
+	Segments       []jsonSegment `json:"segments"`
+	Symbols        []jsonSymbol  `json:"symbols"`
+	DynamicSymbols []jsonSymbol  `json:"dynamicSymbols"`
+	Dynamic        []jsonDynamic `json:"dynamic"`
+	Notes          []jsonNote    `json:"notes"`
+}
+
+type jsonHeader struct {
+	Class      string `json:"class"`
+	Data       string `json:"data"`
+	OSABI      string `json:"osabi"`
+	ABIVersion uint8  `json:"abiVersion"`
+	Type       string `json:"type"`
+	Machine    string `json:"machine"`
+	Version    uint32 `json:"version"`
+	Entry      uint64 `json:"entry"`
+	Phoff      uint64 `json:"phoff"`
+	Shoff      uint64 `json:"shoff"`
+	Flags      uint32 `json:"flags"`
+	Ehsize     uint16 `json:"ehsize"`
+	Phentsize  uint16 `json:"phentsize"`
+	Phnum      uint16 `json:"phnum"`
+	Shentsize  uint16 `json:"shentsize"`
+	Shnum      uint16 `json:"shnum"`
+	Shstrndx   uint16 `json:"shstrndx"`
+}
+
+type jsonSection struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Flags     []string `json:"flags"`
+	Addr      uint64   `json:"addr"`
+	Offset    uint64   `json:"offset"`
+	Size      uint64   `json:"size"`
+	Link      uint32   `json:"link"`
+	Info      uint32   `json:"info"`
+	Addralign uint64   `json:"addralign"`
+	EntSize   uint64   `json:"entsize"`
+	Broken    bool     `json:"broken,omitempty"`
+}
+
+type jsonSegment struct {
+	Type     string   `json:"type"`
+	Flags    []string `json:"flags"`
+	Offset   uint64   `json:"offset"`
+	Vaddr    uint64   `json:"vaddr"`
+	Paddr    uint64   `json:"paddr"`
+	Filesz   uint64   `json:"filesz"`
+	Memsz    uint64   `json:"memsz"`
+	Align    uint64   `json:"align"`
+	Sections []string `json:"sections"`
+	Broken   bool     `json:"broken,omitempty"`
+}
+
+type jsonSymbol struct {
+	Name          string `json:"name"`
+	Value         uint64 `json:"value"`
+	Size          uint64 `json:"size"`
+	Type          string `json:"type"`
+	Bind          string `json:"bind"`
+	Visibility    string `json:"visibility"`
+	Section       uint32 `json:"section"`
+	Version       string `json:"version,omitempty"`
+	Library       string `json:"library,omitempty"`
+	VersionHidden bool   `json:"versionHidden,omitempty"`
+}
+
+type jsonDynamic struct {
+	Tag   string `json:"tag"`
+	Value uint64 `json:"value"`
+	// String is set for the tags whose value is an offset into the dynamic
+	// string table.
+	String string `json:"string,omitempty"`
+}
+
+type jsonNote struct {
+	Owner string `json:"owner"`
+	Type  string `json:"type"`
+	// Desc is the descriptor in hexadecimal.
+	Desc string `json:"desc"`
+}
+
+// MarshalJSON encodes the file header, sections, segments, symbols, dynamic
+// entries and notes of e as a JSON object:
+//
+//	{
+//	  "header": {"class": "ELFCLASS64", "data": "ELFDATA2LSB", "osabi": "ELFOSABI_NONE",
+//	             "abiVersion": 0, "type": "ET_DYN", "machine": "EM_X86_64", "version": 1,
+//	             "entry": 4368, "phoff": 64, "shoff": 14088, "flags": 0, "ehsize": 64,
+//	             "phentsize": 56, "phnum": 13, "shentsize": 64, "shnum": 33, "shstrndx": 32},
+//	  "sections": [{"name": ".text", "type": "SHT_PROGBITS", "flags": ["SHF_ALLOC", "SHF_EXECINSTR"],
+//	                "addr": 4256, "offset": 4256, "size": 345, "link": 0, "info": 0,
+//	                "addralign": 16, "entsize": 0}, ...],
+//	  "segments": [{"type": "PT_LOAD", "flags": ["PF_X", "PF_R"], "offset": 4096, "vaddr": 4096,
+//	                "paddr": 4096, "filesz": 517, "memsz": 517, "align": 4096,
+//	                "sections": [".init", ".plt", ".plt.got", ".plt.sec", ".text", ".fini"]}, ...],
+//	  "symbols": [{"name": "main", "value": 4256, "size": 109, "type": "STT_FUNC",
+//	               "bind": "STB_GLOBAL", "visibility": "STV_DEFAULT", "section": 18}, ...],
+//	  "dynamicSymbols": [{"name": "__libc_start_main", ..., "version": "GLIBC_2.34",
+//	                      "library": "libc.so.6"}, ...],
+//	  "dynamic": [{"tag": "DT_NEEDED", "value": 77, "string": "libc.so.6"}, ...],
+//	  "notes": [{"owner": "GNU", "type": "NT_GNU_BUILD_ID", "desc": "905c54dc..."}, ...]
+//	}
+//
+// Sections, segments and symbols are listed in table order, so that the
+// index of an entry is its position; the symbol lists start with the null
+// symbol. The section of a symbol is its section index or one of the
+// reserved SHN_* indices. The dynamic entries stop before DT_NULL, and the
+// version fields of dynamic symbols are left out when they have none.
+// Enumerations are encoded as constant names, or as the value in decimal
+// when it has no name, and flag sets as lists of names, the bits without
+// one following as a single hexadecimal string. Lists are empty rather than
+// null when the file has nothing to put in them. Section and segment
+// contents are not included. Sections and segments marked Broken by lenient
+// parsing have "broken": true, as their offset and size are then those of
+// the header rather than of the data that could be read.
+func (e *File) MarshalJSON() ([]byte, error) {
+	h := e.Header
+	f := jsonFile{
+		Header: jsonHeader{
+			Class:      h.Class().String(),
+			Data:       h.Data().String(),
+			OSABI:      h.OSABI().String(),
+			ABIVersion: uint8(h.ABIVersion()),
+			Type:       h.Type.String(),
+			Machine:    h.Machine.String(),
+			Version:    h.Version,
+			Entry:      h.Entry,
+			Phoff:      h.Phoff,
+			Shoff:      h.Shoff,
+			Flags:      h.Flags,
+			Ehsize:     h.Ehsize,
+			Phentsize:  h.Phentsize,
+			Phnum:      h.Phnum,
+			Shentsize:  h.Shentsize,
+			Shnum:      h.Shnum,
+			Shstrndx:   h.Shstrndx,
+		},
+		Sections: []jsonSection{},
+		Segments: []jsonSegment{},
+		Dynamic:  []jsonDynamic{},
+		Notes:    []jsonNote{},
+	}
+
+	for _, s := range e.Sections {
+		sh := &s.Header
+		f.Sections = append(f.Sections, jsonSection{
+			Name:      s.Name,
+			Type:      sh.Type.String(),
+			Flags:     flagNames(uint64(sh.Flags), sectionFlagNames),
+			Addr:      sh.Addr,
+			Offset:    sh.Offset,
+			Size:      sh.Size,
+			Link:      sh.Link,
+			Info:      sh.Info,
+			Addralign: sh.Addralign,
+			EntSize:   sh.EntSize,
+			Broken:    s.Broken,
+		})
+	}
+
+	for _, sg := range e.Segments {
+		ph := &sg.Header
+		names := []string{}
+		for _, s := range e.SectionsInSegment(sg) {
+			names = append(names, s.Name)
+		}
+		f.Segments = append(f.Segments, jsonSegment{
+			Type:     ph.Type.String(),
+			Flags:    flagNames(uint64(ph.Flags), programFlagNames),
+			Offset:   ph.Offset,
+			Vaddr:    ph.Vaddr,
+			Paddr:    ph.Paddr,
+			Filesz:   ph.Filesz,
+			Memsz:    ph.Memsz,
+			Align:    ph.Align,
+			Sections: names,
+			Broken:   sg.Broken,
+		})
+	}
+
+	syms, err := e.Symbols()
+	if err != nil {
+		return nil, err
+	}
+	f.Symbols = jsonSymbols(syms)
+	dynsyms, err := e.DynamicSymbols()
+	if err != nil {
+		return nil, err
+	}
+	f.DynamicSymbols = jsonSymbols(dynsyms)
+
+	des, err := e.DynamicEntries()
+	if err != nil {
+		return nil, err
+	}
+	var strtab []byte
+	for _, de := range des {
+		jd := jsonDynamic{Tag: de.Tag.String(), Value: de.Value}
+		if dynStringTags[de.Tag] {
+			if strtab == nil {
+				if strtab, err = e.dynamicStringTable(des); err != nil {
+					return nil, err
+				}
+			}
+			if de.Value > 0xffffffff {
+				return nil, fmt.Errorf("invalid dynamic string offset: %d", de.Value)
+			}
+			if jd.String, err = stringAt(strtab, uint32(de.Value)); err != nil {
+				return nil, fmt.Errorf("invalid string of dynamic entry %#x: %w", uint64(de.Tag), err)
+			}
+		}
+		f.Dynamic = append(f.Dynamic, jd)
+	}
+
+	ns, err := e.Notes()
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range ns {
+		f.Notes = append(f.Notes, jsonNote{Owner: n.Name, Type: noteTypeName(n), Desc: hex.EncodeToString(n.Desc)})
+	}
+
+	return json.Marshal(&f)
+}
+
+func jsonSymbols(syms []*Symbol) []jsonSymbol {
+	js := []jsonSymbol{}
+	for _, sym := range syms {
+		js = append(js, jsonSymbol{
+			Name:          sym.Name,
+			Value:         sym.Value,
+			Size:          sym.Size,
+			Type:          sym.Type.String(),
+			Bind:          sym.Bind.String(),
+			Visibility:    sym.Visibility.String(),
+			Section:       sym.Section,
+			Version:       sym.Version,
+			Library:       sym.Library,
+			VersionHidden: sym.VersionHidden,
+		})
+	}
+
+	return js
+}
+
+// dynStringTags are the tags whose value is an offset into the dynamic
+// string table.
+var dynStringTags = map[DynTag]bool{
+	DT_NEEDED:    true,
+	DT_SONAME:    true,
+	DT_RPATH:     true,
+	DT_RUNPATH:   true,
+	DT_CONFIG:    true,
+	DT_DEPAUDIT:  true,
+	DT_AUDIT:     true,
+	DT_AUXILIARY: true,
+	DT_FILTER:    true,
+}
@@ -0,0 +1,205 @@
+package elf_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+// jsonFile mirrors the parts of the schema of File.MarshalJSON the tests
+// check.
+type jsonFile struct {
+	Header struct {
+		Class, Data, OSABI, Type, Machine string
+		Entry                             uint64
+	}
+	Sections []struct {
+		Name, Type string
+		Flags      []string
+		Size       uint64
+		Broken     bool
+	}
+	Segments []struct {
+		Type     string
+		Flags    []string
+		Sections []string
+	}
+	Symbols []struct {
+		Name, Type, Bind, Visibility string
+		Section                      uint32
+	}
+	DynamicSymbols []struct {
+		Name, Version, Library string
+	}
+	Dynamic []struct {
+		Tag    string
+		Value  uint64
+		String string
+	}
+	Notes []struct {
+		Owner, Type, Desc string
+	}
+}
+
+func marshalFile(t *testing.T, e *elf.File) (*jsonFile, []byte) {
+	t.Helper()
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f jsonFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		t.Fatal(err)
+	}
+	return &f, b
+}
+
+func TestMarshalJSON(t *testing.T) {
+	raw, err := os.ReadFile(hello)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, b := marshalFile(t, e)
+
+	// The contents of sections are left out.
+	if len(b) > len(raw) {
+		t.Errorf("JSON of %d bytes for a file of %d bytes", len(b), len(raw))
+	}
+
+	h := f.Header
+	if h.Class != "ELFCLASS64" || h.Data != "ELFDATA2LSB" || h.OSABI != "ELFOSABI_NONE" ||
+		h.Type != "ET_DYN" || h.Machine != "EM_X86_64" || h.Entry != e.Header.Entry {
+		t.Errorf("header: %+v", h)
+	}
+
+	if len(f.Sections) != len(e.Sections) {
+		t.Fatalf("%d sections, want %d", len(f.Sections), len(e.Sections))
+	}
+	for i, s := range f.Sections {
+		if s.Name != e.Sections[i].Name || s.Size != e.Sections[i].Header.Size {
+			t.Errorf("section %d: %+v", i, s)
+		}
+	}
+	text := e.SectionByName(".text")
+	for _, s := range f.Sections {
+		if s.Name != text.Name {
+			continue
+		}
+		if s.Type != "SHT_PROGBITS" || !reflect.DeepEqual(s.Flags, []string{"SHF_ALLOC", "SHF_EXECINSTR"}) {
+			t.Errorf(".text: %+v", s)
+		}
+	}
+
+	if len(f.Segments) != len(e.Segments) {
+		t.Fatalf("%d segments, want %d", len(f.Segments), len(e.Segments))
+	}
+	if sg := f.Segments[1]; sg.Type != "PT_INTERP" || !reflect.DeepEqual(sg.Flags, []string{"PF_R"}) ||
+		!reflect.DeepEqual(sg.Sections, []string{".interp"}) {
+		t.Errorf("segment 1: %+v", sg)
+	}
+
+	syms, err := e.Symbols()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Symbols) != len(syms) {
+		t.Fatalf("%d symbols, want %d", len(f.Symbols), len(syms))
+	}
+	for i, sym := range f.Symbols {
+		if sym.Name != "main" {
+			continue
+		}
+		if sym.Type != "STT_FUNC" || sym.Bind != "STB_GLOBAL" || sym.Visibility != "STV_DEFAULT" || sym.Section != syms[i].Section {
+			t.Errorf("main: %+v", sym)
+		}
+	}
+	if sym := f.DynamicSymbols[1]; sym.Name != "__libc_start_main" || sym.Version != "GLIBC_2.34" || sym.Library != "libc.so.6" {
+		t.Errorf("dynamic symbol 1: %+v", sym)
+	}
+
+	if d := f.Dynamic[0]; d.Tag != "DT_NEEDED" || d.String != "libc.so.6" {
+		t.Errorf("dynamic entry 0: %+v", d)
+	}
+	if d := f.Dynamic[len(f.Dynamic)-1]; d.Tag != "DT_RELACOUNT" || d.Value != 3 || d.String != "" {
+		t.Errorf("last dynamic entry: %+v", d)
+	}
+
+	var types []string
+	for _, n := range f.Notes {
+		types = append(types, n.Owner+" "+n.Type)
+	}
+	want := []string{"GNU NT_GNU_PROPERTY_TYPE_0", "GNU NT_GNU_BUILD_ID", "GNU NT_GNU_ABI_TAG", "FDO NT_FDO_PACKAGING_METADATA"}
+	if !reflect.DeepEqual(types, want) {
+		t.Errorf("notes:\n\thave %v\n\twant %v\n", types, want)
+	}
+	if desc := f.Notes[1].Desc; desc != "905c54dc7623d037eeaae8d9aaa4eac6c43707d7" {
+		t.Errorf("build ID %s", desc)
+	}
+}
+
+func TestMarshalJSONBroken(t *testing.T) {
+	raw, err := os.ReadFile(hello)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := slices.Index(e.Sections, e.SectionByName(".fini_array"))
+	binary.LittleEndian.PutUint64(raw[e.Header.Shoff+uint64(i)*64+24:], uint64(len(raw))) // sh_offset
+	if e, err = elf.NewWithOptions(raw, elf.Options{Lenient: true}); err != nil {
+		t.Fatal(err)
+	}
+	f, _ := marshalFile(t, e)
+
+	// The broken section keeps the size of its header.
+	for j, s := range f.Sections {
+		if s.Broken != (j == i) || s.Size != e.Sections[j].Header.Size {
+			t.Errorf("section %d: %+v", j, s)
+		}
+	}
+}
+
+func TestMarshalJSONUnnamed(t *testing.T) {
+	b := elf.NewBuilder(elf.ELFCLASS32, binary.BigEndian, elf.Machine(0x1234), elf.ET_REL)
+	b.AddSection(".custom", elf.SectionHeader{Type: 0x8000beef, Flags: elf.SHF_ALLOC | 0x00100000, Addralign: 1}, []byte{1, 2, 3})
+	raw, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, out := marshalFile(t, e)
+
+	if h := f.Header; h.Class != "ELFCLASS32" || h.Data != "ELFDATA2MSB" || h.Machine != "4660" || h.Type != "ET_REL" {
+		t.Errorf("header: %+v", h)
+	}
+	s := f.Sections[1]
+	if s.Type != "2147532527" || !reflect.DeepEqual(s.Flags, []string{"SHF_ALLOC", "0x100000"}) {
+		t.Errorf("section: %+v", s)
+	}
+
+	// Files without segments, symbols, dynamic entries or notes encode
+	// them as empty lists.
+	var lists map[string]any
+	if err := json.Unmarshal(out, &lists); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"segments", "symbols", "dynamicSymbols", "dynamic", "notes"} {
+		if l, ok := lists[key].([]any); !ok || len(l) != 0 {
+			t.Errorf("%s: have %#v, want []", key, lists[key])
+		}
+	}
+}
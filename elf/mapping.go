@@ -0,0 +1,98 @@
+package elf
+
+// SectionsInSegment returns the sections contained in the segment sg, in
+// section header order, as listed in the section to segment mapping of
+// readelf -l. Section 0 is never included.
+func (e *File) SectionsInSegment(sg *Segment) []*Section {
+	var ss []*Section
+	for i, s := range e.Sections {
+		if i > 0 && sectionInSegment(&s.Header, &sg.Header) {
+			ss = append(ss, s)
+		}
+	}
+
+	return ss
+}
+
+// SegmentsForSection returns the segments containing the section s, in
+// program header order, using the same rules as SectionsInSegment.
+func (e *File) SegmentsForSection(s *Section) []*Segment {
+	if len(e.Sections) > 0 && s == e.Sections[0] {
+		return nil
+	}
+
+	var sgs []*Segment
+	for _, sg := range e.Segments {
+		if sectionInSegment(&s.Header, &sg.Header) {
+			sgs = append(sgs, sg)
+		}
+	}
+
+	return sgs
+}
+
+// tbssSpecial reports whether sh is a .tbss-like section outside of PT_TLS.
+// Such a section occupies no memory of its own in the segment: its address
+// range overlaps the sections that follow it.
+func tbssSpecial(sh *SectionHeader, ph *ProgramHeader) bool {
+	return sh.Flags&SHF_TLS != 0 && sh.Type == SHT_NOBITS && ph.Type != PT_TLS
+}
+
+// sectionInSegment implements ELF_SECTION_IN_SEGMENT_STRICT of binutils,
+// which readelf uses to map sections to segments.
+func sectionInSegment(sh *SectionHeader, ph *ProgramHeader) bool {
+	// Like readelf, list .tbss in PT_TLS only, although the binutils
+	// macro alone would also place it in the PT_LOAD covering its address.
+	if tbssSpecial(sh, ph) {
+		return false
+	}
+
+	tls := sh.Flags&SHF_TLS != 0
+	alloc := sh.Flags&SHF_ALLOC != 0
+
+	// PT_TLS only holds TLS sections, which may otherwise only be part of
+	// PT_LOAD and PT_GNU_RELRO. PT_PHDR holds no sections at all.
+	if tls && ph.Type != PT_TLS && ph.Type != PT_LOAD && ph.Type != PT_GNU_RELRO {
+		return false
+	}
+	if !tls && (ph.Type == PT_TLS || ph.Type == PT_PHDR) {
+		return false
+	}
+
+	// Loaded segments only hold allocated sections.
+	if !alloc {
+		switch {
+		case ph.Type == PT_LOAD, ph.Type == PT_DYNAMIC, ph.Type == PT_GNU_EH_FRAME,
+			ph.Type == PT_GNU_STACK, ph.Type == PT_GNU_RELRO, ph.Type == PT_GNU_SFRAME,
+			ph.Type >= PT_GNU_MBIND_LO && ph.Type <= PT_GNU_MBIND_HI:
+			return false
+		}
+	}
+
+	// Sections with contents must lie within the file image of the segment.
+	if sh.Type != SHT_NOBITS {
+		if sh.Offset < ph.Offset || sh.Offset-ph.Offset > ph.Filesz-1 || sh.Offset-ph.Offset+sh.Size > ph.Filesz {
+			return false
+		}
+	}
+
+	// Allocated sections must lie within the memory image of the segment.
+	if alloc {
+		if sh.Addr < ph.Vaddr || sh.Addr-ph.Vaddr > ph.Memsz-1 || sh.Addr-ph.Vaddr+sh.Size > ph.Memsz {
+			return false
+		}
+	}
+
+	// Empty sections at the start or end of PT_DYNAMIC and PT_NOTE do not
+	// belong to them.
+	if (ph.Type == PT_DYNAMIC || ph.Type == PT_NOTE) && sh.Size == 0 && ph.Memsz != 0 {
+		if sh.Type != SHT_NOBITS && (sh.Offset <= ph.Offset || sh.Offset-ph.Offset >= ph.Filesz) {
+			return false
+		}
+		if alloc && (sh.Addr <= ph.Vaddr || sh.Addr-ph.Vaddr >= ph.Memsz) {
+			return false
+		}
+	}
+
+	return true
+}
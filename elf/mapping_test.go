@@ -0,0 +1,99 @@
+package elf_test
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+func sectionNames(ss []*elf.Section) []string {
+	var names []string
+	for _, s := range ss {
+		names = append(names, s.Name)
+	}
+
+	return names
+}
+
+func TestSectionsInSegment(t *testing.T) {
+	e, err := elf.New(mustReadFile(t, hello))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The section to segment mapping printed by readelf -lW.
+	want := [][]string{
+		nil,
+		{".interp"},
+		{".interp", ".note.gnu.property", ".note.gnu.build-id", ".note.ABI-tag", ".note.package", ".hash", ".gnu.hash", ".dynsym", ".dynstr", ".gnu.version", ".gnu.version_r", ".rela.dyn", ".rela.plt"},
+		{".init", ".plt", ".plt.got", ".plt.sec", ".text", ".fini"},
+		{".rodata", ".eh_frame_hdr", ".eh_frame"},
+		{".init_array", ".fini_array", ".dynamic", ".got", ".data", ".bss"},
+		{".dynamic"},
+		{".note.gnu.property"},
+		{".note.gnu.build-id", ".note.ABI-tag", ".note.package"},
+		{".note.gnu.property"},
+		{".eh_frame_hdr"},
+		nil,
+		{".init_array", ".fini_array", ".dynamic", ".got"},
+	}
+	if len(e.Segments) != len(want) {
+		t.Fatalf("have %d segments, want %d", len(e.Segments), len(want))
+	}
+	for i, sg := range e.Segments {
+		if have := sectionNames(e.SectionsInSegment(sg)); !reflect.DeepEqual(have, want[i]) {
+			t.Errorf("segment %d:\n\thave %#v\n\twant %#v\n", i, have, want[i])
+		}
+	}
+
+	sgs := e.SegmentsForSection(e.SectionByName(".dynamic"))
+	if len(sgs) != 3 || sgs[0] != e.Segments[5] || sgs[1] != e.Segments[6] || sgs[2] != e.Segments[12] {
+		t.Errorf("have %d segments for .dynamic, want LOAD, DYNAMIC and GNU_RELRO", len(sgs))
+	}
+	if sgs := e.SegmentsForSection(e.SectionByName(".comment")); len(sgs) != 0 {
+		t.Errorf("have %d segments for .comment, want none", len(sgs))
+	}
+	if sgs := e.SegmentsForSection(e.Sections[0]); len(sgs) != 0 {
+		t.Errorf("have %d segments for section 0, want none", len(sgs))
+	}
+}
+
+func TestSectionsInSegmentTLS(t *testing.T) {
+	b := elf.NewBuilder(elf.ELFCLASS64, binary.LittleEndian, elf.EM_X86_64, elf.ET_EXEC)
+	rw := elf.SHF_ALLOC | elf.SHF_WRITE
+	tdata := b.AddSection(".tdata", elf.SectionHeader{Type: elf.SHT_PROGBITS, Flags: rw | elf.SHF_TLS, Addr: 0x403000, Addralign: 8}, make([]byte, 8))
+	tbss := b.AddSection(".tbss", elf.SectionHeader{Type: elf.SHT_NOBITS, Flags: rw | elf.SHF_TLS, Addr: 0x403008, Size: 0x10, Addralign: 8}, nil)
+	data := b.AddSection(".data", elf.SectionHeader{Type: elf.SHT_PROGBITS, Flags: rw, Addr: 0x403008, Addralign: 8}, make([]byte, 8))
+	bss := b.AddSection(".bss", elf.SectionHeader{Type: elf.SHT_NOBITS, Flags: rw, Addr: 0x403010, Size: 0x20, Addralign: 8}, nil)
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_LOAD, Flags: elf.PF_R | elf.PF_W, Align: 0x1000}, tdata, tbss, data, bss)
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_TLS, Flags: elf.PF_R, Align: 8}, tdata, tbss)
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_PHDR, Flags: elf.PF_R, Align: 8})
+
+	raw, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{".tdata", ".data", ".bss"},
+		{".tdata", ".tbss"},
+		nil,
+	}
+	for i, sg := range e.Segments {
+		if have := sectionNames(e.SectionsInSegment(sg)); !reflect.DeepEqual(have, want[i]) {
+			t.Errorf("segment %d:\n\thave %#v\n\twant %#v\n", i, have, want[i])
+		}
+	}
+	if sgs := e.SegmentsForSection(e.SectionByName(".tbss")); len(sgs) != 1 || sgs[0].Header.Type != elf.PT_TLS {
+		t.Errorf(".tbss should only be in PT_TLS, have %d segments", len(sgs))
+	}
+	if load := e.Segments[0].Header; load.Memsz != 0x30 {
+		t.Errorf("have PT_LOAD memsz %#x, want 0x30 without .tbss", load.Memsz)
+	}
+}
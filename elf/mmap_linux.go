@@ -0,0 +1,52 @@
+//go:build linux
+
+package elf
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// mapping is a read-only memory mapping of a file.
+type mapping []byte
+
+func (m mapping) Close() error {
+	return syscall.Munmap(m)
+}
+
+// OpenMmap maps the named file into memory and parses it with New, so Raw and
+// the Raw fields of sections and segments point into the mapping without
+// copying. The mapping is private and read-only and is released by Close,
+// after which none of the Raw slices may be used. The file must not be
+// truncated while it is open: reading the pages past its new end faults and
+// crashes the process.
+func OpenMmap(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() == 0 {
+		return nil, fmt.Errorf("insufficient elf format size: %d", 0)
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(fi.Size()), syscall.PROT_READ, syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mmap %s: %w", path, err)
+	}
+
+	e, err := New(data)
+	if err != nil {
+		syscall.Munmap(data)
+		return nil, err
+	}
+	e.closer = mapping(data)
+
+	return e, nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package elf
+
+// OpenMmap falls back to Open on platforms without mmap support.
+func OpenMmap(path string) (*File, error) {
+	return Open(path)
+}
@@ -0,0 +1,615 @@
+package elf
+
+import "strconv"
+
+// intName pairs a constant value with its symbolic name.
+type intName struct {
+	i uint32
+	s string
+}
+
+// stringName returns the symbolic name of i in names, or i in decimal when
+// names has no entry for it.
+func stringName(i uint32, names []intName) string {
+	for _, n := range names {
+		if n.i == i {
+			return n.s
+		}
+	}
+
+	return strconv.FormatUint(uint64(i), 10)
+}
+
+// flagNames returns the names of the bits of v in names. The bits without a
+// name are returned together as one hexadecimal value at the end.
+func flagNames(v uint64, names []intName) []string {
+	ss := []string{}
+	for _, n := range names {
+		if v&uint64(n.i) != 0 {
+			ss = append(ss, n.s)
+			v &^= uint64(n.i)
+		}
+	}
+	if v != 0 {
+		ss = append(ss, "0x"+strconv.FormatUint(v, 16))
+	}
+
+	return ss
+}
+
+var classNames = []intName{
+	{uint32(ELFCLASSNONE), "ELFCLASSNONE"},
+	{uint32(ELFCLASS32), "ELFCLASS32"},
+	{uint32(ELFCLASS64), "ELFCLASS64"},
+}
+
+func (c Class) String() string { return stringName(uint32(c), classNames) }
+
+var dataNames = []intName{
+	{uint32(ELFDATANONE), "ELFDATANONE"},
+	{uint32(ELFDATA2LSB), "ELFDATA2LSB"},
+	{uint32(ELFDATA2MSB), "ELFDATA2MSB"},
+}
+
+func (d Data) String() string { return stringName(uint32(d), dataNames) }
+
+// osABINames leaves out the aliases ELFOSABI_SYSV and ELFOSABI_GNU, and
+// names the machine specific values after ARM.
+var osABINames = []intName{
+	{uint32(ELFOSABI_NONE), "ELFOSABI_NONE"},
+	{uint32(ELFOSABI_HPUX), "ELFOSABI_HPUX"},
+	{uint32(ELFOSABI_NETBSD), "ELFOSABI_NETBSD"},
+	{uint32(ELFOSABI_LINUX), "ELFOSABI_LINUX"},
+	{uint32(ELFOSABI_SOLARIS), "ELFOSABI_SOLARIS"},
+	{uint32(ELFOSABI_AIX), "ELFOSABI_AIX"},
+	{uint32(ELFOSABI_IRIX), "ELFOSABI_IRIX"},
+	{uint32(ELFOSABI_FREEBSD), "ELFOSABI_FREEBSD"},
+	{uint32(ELFOSABI_TRU64), "ELFOSABI_TRU64"},
+	{uint32(ELFOSABI_MODESTO), "ELFOSABI_MODESTO"},
+	{uint32(ELFOSABI_OPENBSD), "ELFOSABI_OPENBSD"},
+	{uint32(ELFOSABI_OPENVMS), "ELFOSABI_OPENVMS"},
+	{uint32(ELFOSABI_NSK), "ELFOSABI_NSK"},
+	{uint32(ELFOSABI_AROS), "ELFOSABI_AROS"},
+	{uint32(ELFOSABI_FENIXOS), "ELFOSABI_FENIXOS"},
+	{uint32(ELFOSABI_CLOUDABI), "ELFOSABI_CLOUDABI"},
+	{uint32(ELFOSABI_OPENVOS), "ELFOSABI_OPENVOS"},
+	{uint32(ELFOSABI_CUDA), "ELFOSABI_CUDA"},
+	{uint32(ELFOSABI_ARM_AEABI), "ELFOSABI_ARM_AEABI"},
+	{uint32(ELFOSABI_ARM), "ELFOSABI_ARM"},
+	{uint32(ELFOSABI_STANDALONE), "ELFOSABI_STANDALONE"},
+}
+
+func (o OSABI) String() string { return stringName(uint32(o), osABINames) }
+
+var typeNames = []intName{
+	{uint32(ET_NONE), "ET_NONE"},
+	{uint32(ET_REL), "ET_REL"},
+	{uint32(ET_EXEC), "ET_EXEC"},
+	{uint32(ET_DYN), "ET_DYN"},
+	{uint32(ET_CORE), "ET_CORE"},
+}
+
+func (t Type) String() string { return stringName(uint32(t), typeNames) }
+
+var machineNames = []intName{
+	{uint32(EM_NONE), "EM_NONE"},
+	{uint32(EM_M32), "EM_M32"},
+	{uint32(EM_SPARC), "EM_SPARC"},
+	{uint32(EM_386), "EM_386"},
+	{uint32(EM_68K), "EM_68K"},
+	{uint32(EM_88K), "EM_88K"},
+	{uint32(EM_486), "EM_486"},
+	{uint32(EM_860), "EM_860"},
+	{uint32(EM_MIPS), "EM_MIPS"},
+	{uint32(EM_S370), "EM_S370"},
+	{uint32(EM_MIPS_RS3_LE), "EM_MIPS_RS3_LE"},
+	{uint32(EM_PARISC), "EM_PARISC"},
+	{uint32(EM_VPP500), "EM_VPP500"},
+	{uint32(EM_SPARC32PLUS), "EM_SPARC32PLUS"},
+	{uint32(EM_960), "EM_960"},
+	{uint32(EM_PPC), "EM_PPC"},
+	{uint32(EM_PPC64), "EM_PPC64"},
+	{uint32(EM_S390), "EM_S390"},
+	{uint32(EM_SPU), "EM_SPU"},
+	{uint32(EM_V800), "EM_V800"},
+	{uint32(EM_FR20), "EM_FR20"},
+	{uint32(EM_RH32), "EM_RH32"},
+	{uint32(EM_RCE), "EM_RCE"},
+	{uint32(EM_ARM), "EM_ARM"},
+	{uint32(EM_ALPHA_STD), "EM_ALPHA_STD"},
+	{uint32(EM_SH), "EM_SH"},
+	{uint32(EM_SPARCV9), "EM_SPARCV9"},
+	{uint32(EM_TRICORE), "EM_TRICORE"},
+	{uint32(EM_ARC), "EM_ARC"},
+	{uint32(EM_H8_300), "EM_H8_300"},
+	{uint32(EM_H8_300H), "EM_H8_300H"},
+	{uint32(EM_H8S), "EM_H8S"},
+	{uint32(EM_H8_500), "EM_H8_500"},
+	{uint32(EM_IA_64), "EM_IA_64"},
+	{uint32(EM_MIPS_X), "EM_MIPS_X"},
+	{uint32(EM_COLDFIRE), "EM_COLDFIRE"},
+	{uint32(EM_68HC12), "EM_68HC12"},
+	{uint32(EM_MMA), "EM_MMA"},
+	{uint32(EM_PCP), "EM_PCP"},
+	{uint32(EM_NCPU), "EM_NCPU"},
+	{uint32(EM_NDR1), "EM_NDR1"},
+	{uint32(EM_STARCORE), "EM_STARCORE"},
+	{uint32(EM_ME16), "EM_ME16"},
+	{uint32(EM_ST100), "EM_ST100"},
+	{uint32(EM_TINYJ), "EM_TINYJ"},
+	{uint32(EM_X86_64), "EM_X86_64"},
+	{uint32(EM_PDSP), "EM_PDSP"},
+	{uint32(EM_PDP10), "EM_PDP10"},
+	{uint32(EM_PDP11), "EM_PDP11"},
+	{uint32(EM_FX66), "EM_FX66"},
+	{uint32(EM_ST9PLUS), "EM_ST9PLUS"},
+	{uint32(EM_ST7), "EM_ST7"},
+	{uint32(EM_68HC16), "EM_68HC16"},
+	{uint32(EM_68HC11), "EM_68HC11"},
+	{uint32(EM_68HC08), "EM_68HC08"},
+	{uint32(EM_68HC05), "EM_68HC05"},
+	{uint32(EM_SVX), "EM_SVX"},
+	{uint32(EM_ST19), "EM_ST19"},
+	{uint32(EM_VAX), "EM_VAX"},
+	{uint32(EM_CRIS), "EM_CRIS"},
+	{uint32(EM_JAVELIN), "EM_JAVELIN"},
+	{uint32(EM_FIREPATH), "EM_FIREPATH"},
+	{uint32(EM_ZSP), "EM_ZSP"},
+	{uint32(EM_MMIX), "EM_MMIX"},
+	{uint32(EM_HUANY), "EM_HUANY"},
+	{uint32(EM_PRISM), "EM_PRISM"},
+	{uint32(EM_AVR), "EM_AVR"},
+	{uint32(EM_FR30), "EM_FR30"},
+	{uint32(EM_D10V), "EM_D10V"},
+	{uint32(EM_D30V), "EM_D30V"},
+	{uint32(EM_V850), "EM_V850"},
+	{uint32(EM_M32R), "EM_M32R"},
+	{uint32(EM_MN10300), "EM_MN10300"},
+	{uint32(EM_MN10200), "EM_MN10200"},
+	{uint32(EM_PJ), "EM_PJ"},
+	{uint32(EM_OPENRISC), "EM_OPENRISC"},
+	{uint32(EM_ARC_COMPACT), "EM_ARC_COMPACT"},
+	{uint32(EM_XTENSA), "EM_XTENSA"},
+	{uint32(EM_VIDEOCORE), "EM_VIDEOCORE"},
+	{uint32(EM_TMM_GPP), "EM_TMM_GPP"},
+	{uint32(EM_NS32K), "EM_NS32K"},
+	{uint32(EM_TPC), "EM_TPC"},
+	{uint32(EM_SNP1K), "EM_SNP1K"},
+	{uint32(EM_ST200), "EM_ST200"},
+	{uint32(EM_IP2K), "EM_IP2K"},
+	{uint32(EM_MAX), "EM_MAX"},
+	{uint32(EM_CR), "EM_CR"},
+	{uint32(EM_F2MC16), "EM_F2MC16"},
+	{uint32(EM_MSP430), "EM_MSP430"},
+	{uint32(EM_BLACKFIN), "EM_BLACKFIN"},
+	{uint32(EM_SE_C33), "EM_SE_C33"},
+	{uint32(EM_SEP), "EM_SEP"},
+	{uint32(EM_ARCA), "EM_ARCA"},
+	{uint32(EM_UNICORE), "EM_UNICORE"},
+	{uint32(EM_EXCESS), "EM_EXCESS"},
+	{uint32(EM_DXP), "EM_DXP"},
+	{uint32(EM_ALTERA_NIOS2), "EM_ALTERA_NIOS2"},
+	{uint32(EM_CRX), "EM_CRX"},
+	{uint32(EM_XGATE), "EM_XGATE"},
+	{uint32(EM_C166), "EM_C166"},
+	{uint32(EM_M16C), "EM_M16C"},
+	{uint32(EM_DSPIC30F), "EM_DSPIC30F"},
+	{uint32(EM_CE), "EM_CE"},
+	{uint32(EM_M32C), "EM_M32C"},
+	{uint32(EM_TSK3000), "EM_TSK3000"},
+	{uint32(EM_RS08), "EM_RS08"},
+	{uint32(EM_SHARC), "EM_SHARC"},
+	{uint32(EM_ECOG2), "EM_ECOG2"},
+	{uint32(EM_SCORE7), "EM_SCORE7"},
+	{uint32(EM_DSP24), "EM_DSP24"},
+	{uint32(EM_VIDEOCORE3), "EM_VIDEOCORE3"},
+	{uint32(EM_LATTICEMICO32), "EM_LATTICEMICO32"},
+	{uint32(EM_SE_C17), "EM_SE_C17"},
+	{uint32(EM_TI_C6000), "EM_TI_C6000"},
+	{uint32(EM_TI_C2000), "EM_TI_C2000"},
+	{uint32(EM_TI_C5500), "EM_TI_C5500"},
+	{uint32(EM_TI_ARP32), "EM_TI_ARP32"},
+	{uint32(EM_TI_PRU), "EM_TI_PRU"},
+	{uint32(EM_MMDSP_PLUS), "EM_MMDSP_PLUS"},
+	{uint32(EM_CYPRESS_M8C), "EM_CYPRESS_M8C"},
+	{uint32(EM_R32C), "EM_R32C"},
+	{uint32(EM_TRIMEDIA), "EM_TRIMEDIA"},
+	{uint32(EM_QDSP6), "EM_QDSP6"},
+	{uint32(EM_8051), "EM_8051"},
+	{uint32(EM_STXP7X), "EM_STXP7X"},
+	{uint32(EM_NDS32), "EM_NDS32"},
+	{uint32(EM_ECOG1X), "EM_ECOG1X"},
+	{uint32(EM_MAXQ30), "EM_MAXQ30"},
+	{uint32(EM_XIMO16), "EM_XIMO16"},
+	{uint32(EM_MANIK), "EM_MANIK"},
+	{uint32(EM_CRAYNV2), "EM_CRAYNV2"},
+	{uint32(EM_RX), "EM_RX"},
+	{uint32(EM_METAG), "EM_METAG"},
+	{uint32(EM_MCST_ELBRUS), "EM_MCST_ELBRUS"},
+	{uint32(EM_ECOG16), "EM_ECOG16"},
+	{uint32(EM_CR16), "EM_CR16"},
+	{uint32(EM_ETPU), "EM_ETPU"},
+	{uint32(EM_SLE9X), "EM_SLE9X"},
+	{uint32(EM_L10M), "EM_L10M"},
+	{uint32(EM_K10M), "EM_K10M"},
+	{uint32(EM_AARCH64), "EM_AARCH64"},
+	{uint32(EM_AVR32), "EM_AVR32"},
+	{uint32(EM_STM8), "EM_STM8"},
+	{uint32(EM_TILE64), "EM_TILE64"},
+	{uint32(EM_TILEPRO), "EM_TILEPRO"},
+	{uint32(EM_MICROBLAZE), "EM_MICROBLAZE"},
+	{uint32(EM_CUDA), "EM_CUDA"},
+	{uint32(EM_TILEGX), "EM_TILEGX"},
+	{uint32(EM_CLOUDSHIELD), "EM_CLOUDSHIELD"},
+	{uint32(EM_COREA_1ST), "EM_COREA_1ST"},
+	{uint32(EM_COREA_2ND), "EM_COREA_2ND"},
+	{uint32(EM_ARCV2), "EM_ARCV2"},
+	{uint32(EM_OPEN8), "EM_OPEN8"},
+	{uint32(EM_RL78), "EM_RL78"},
+	{uint32(EM_VIDEOCORE5), "EM_VIDEOCORE5"},
+	{uint32(EM_78KOR), "EM_78KOR"},
+	{uint32(EM_56800EX), "EM_56800EX"},
+	{uint32(EM_BA1), "EM_BA1"},
+	{uint32(EM_BA2), "EM_BA2"},
+	{uint32(EM_XCORE), "EM_XCORE"},
+	{uint32(EM_MCHP_PIC), "EM_MCHP_PIC"},
+	{uint32(EM_INTELGT), "EM_INTELGT"},
+	{uint32(EM_KM32), "EM_KM32"},
+	{uint32(EM_KMX32), "EM_KMX32"},
+	{uint32(EM_KMX16), "EM_KMX16"},
+	{uint32(EM_KMX8), "EM_KMX8"},
+	{uint32(EM_KVARC), "EM_KVARC"},
+	{uint32(EM_CDP), "EM_CDP"},
+	{uint32(EM_COGE), "EM_COGE"},
+	{uint32(EM_COOL), "EM_COOL"},
+	{uint32(EM_NORC), "EM_NORC"},
+	{uint32(EM_CSR_KALIMBA), "EM_CSR_KALIMBA"},
+	{uint32(EM_Z80), "EM_Z80"},
+	{uint32(EM_VISIUM), "EM_VISIUM"},
+	{uint32(EM_FT32), "EM_FT32"},
+	{uint32(EM_MOXIE), "EM_MOXIE"},
+	{uint32(EM_AMDGPU), "EM_AMDGPU"},
+	{uint32(EM_RISCV), "EM_RISCV"},
+	{uint32(EM_BPF), "EM_BPF"},
+	{uint32(EM_CSKY), "EM_CSKY"},
+	{uint32(EM_LOONGARCH), "EM_LOONGARCH"},
+	{uint32(EM_ALPHA), "EM_ALPHA"},
+}
+
+func (m Machine) String() string { return stringName(uint32(m), machineNames) }
+
+var sectionTypeNames = []intName{
+	{uint32(SHT_NULL), "SHT_NULL"},
+	{uint32(SHT_PROGBITS), "SHT_PROGBITS"},
+	{uint32(SHT_SYMTAB), "SHT_SYMTAB"},
+	{uint32(SHT_STRTAB), "SHT_STRTAB"},
+	{uint32(SHT_RELA), "SHT_RELA"},
+	{uint32(SHT_HASH), "SHT_HASH"},
+	{uint32(SHT_DYNAMIC), "SHT_DYNAMIC"},
+	{uint32(SHT_NOTE), "SHT_NOTE"},
+	{uint32(SHT_NOBITS), "SHT_NOBITS"},
+	{uint32(SHT_REL), "SHT_REL"},
+	{uint32(SHT_SHLIB), "SHT_SHLIB"},
+	{uint32(SHT_DYNSYM), "SHT_DYNSYM"},
+	{uint32(SHT_INIT_ARRAY), "SHT_INIT_ARRAY"},
+	{uint32(SHT_FINI_ARRAY), "SHT_FINI_ARRAY"},
+	{uint32(SHT_PREINIT_ARRAY), "SHT_PREINIT_ARRAY"},
+	{uint32(SHT_GROUP), "SHT_GROUP"},
+	{uint32(SHT_SYMTAB_SHNDX), "SHT_SYMTAB_SHNDX"},
+	{uint32(SHT_RELR), "SHT_RELR"},
+	{uint32(SHT_ANDROID_REL), "SHT_ANDROID_REL"},
+	{uint32(SHT_ANDROID_RELA), "SHT_ANDROID_RELA"},
+	{uint32(SHT_GNU_INCREMENTAL_INPUTS), "SHT_GNU_INCREMENTAL_INPUTS"},
+	{uint32(SHT_LLVM_ODRTAB), "SHT_LLVM_ODRTAB"},
+	{uint32(SHT_LLVM_LINKER_OPTIONS), "SHT_LLVM_LINKER_OPTIONS"},
+	{uint32(SHT_LLVM_ADDRSIG), "SHT_LLVM_ADDRSIG"},
+	{uint32(SHT_LLVM_DEPENDENT_LIBRARIES), "SHT_LLVM_DEPENDENT_LIBRARIES"},
+	{uint32(SHT_LLVM_SYMPART), "SHT_LLVM_SYMPART"},
+	{uint32(SHT_LLVM_PART_EHDR), "SHT_LLVM_PART_EHDR"},
+	{uint32(SHT_LLVM_PART_PHDR), "SHT_LLVM_PART_PHDR"},
+	{uint32(SHT_LLVM_BB_ADDR_MAP_V0), "SHT_LLVM_BB_ADDR_MAP_V0"},
+	{uint32(SHT_LLVM_CALL_GRAPH_PROFILE), "SHT_LLVM_CALL_GRAPH_PROFILE"},
+	{uint32(SHT_LLVM_BB_ADDR_MAP), "SHT_LLVM_BB_ADDR_MAP"},
+	{uint32(SHT_LLVM_OFFLOADING), "SHT_LLVM_OFFLOADING"},
+	{uint32(SHT_LLVM_LTO), "SHT_LLVM_LTO"},
+	{uint32(SHT_ANDROID_RELR), "SHT_ANDROID_RELR"},
+	{uint32(SHT_GNU_SFRAME), "SHT_GNU_SFRAME"},
+	{uint32(SHT_GNU_ATTRIBUTES), "SHT_GNU_ATTRIBUTES"},
+	{uint32(SHT_GNU_HASH), "SHT_GNU_HASH"},
+	{uint32(SHT_GNU_LIBLIST), "SHT_GNU_LIBLIST"},
+	{uint32(SHT_CHECKSUM), "SHT_CHECKSUM"},
+	{uint32(SHT_SUNW_move), "SHT_SUNW_move"},
+	{uint32(SHT_SUNW_COMDAT), "SHT_SUNW_COMDAT"},
+	{uint32(SHT_SUNW_syminfo), "SHT_SUNW_syminfo"},
+	{uint32(SHT_GNU_verdef), "SHT_GNU_verdef"},
+	{uint32(SHT_GNU_verneed), "SHT_GNU_verneed"},
+	{uint32(SHT_GNU_versym), "SHT_GNU_versym"},
+}
+
+func (t SectionHeaderType) String() string { return stringName(uint32(t), sectionTypeNames) }
+
+var sectionFlagNames = []intName{
+	{uint32(SHF_WRITE), "SHF_WRITE"},
+	{uint32(SHF_ALLOC), "SHF_ALLOC"},
+	{uint32(SHF_EXECINSTR), "SHF_EXECINSTR"},
+	{uint32(SHF_MERGE), "SHF_MERGE"},
+	{uint32(SHF_STRINGS), "SHF_STRINGS"},
+	{uint32(SHF_INFO_LINK), "SHF_INFO_LINK"},
+	{uint32(SHF_LINK_ORDER), "SHF_LINK_ORDER"},
+	{uint32(SHF_OS_NONCONFORMING), "SHF_OS_NONCONFORMING"},
+	{uint32(SHF_GROUP), "SHF_GROUP"},
+	{uint32(SHF_TLS), "SHF_TLS"},
+	{uint32(SHF_COMPRESSED), "SHF_COMPRESSED"},
+	{uint32(SHF_GNU_RETAIN), "SHF_GNU_RETAIN"},
+	{uint32(SHF_GNU_MBIND), "SHF_GNU_MBIND"},
+	{uint32(SHF_ORDERED), "SHF_ORDERED"},
+	{uint32(SHF_EXCLUDE), "SHF_EXCLUDE"},
+}
+
+// sectionFlagKeys are the letters readelf shows for section flags.
+var sectionFlagKeys = map[SectionFlag]byte{
+	SHF_WRITE:            'W',
+	SHF_ALLOC:            'A',
+	SHF_EXECINSTR:        'X',
+	SHF_MERGE:            'M',
+	SHF_STRINGS:          'S',
+	SHF_INFO_LINK:        'I',
+	SHF_LINK_ORDER:       'L',
+	SHF_OS_NONCONFORMING: 'O',
+	SHF_GROUP:            'G',
+	SHF_TLS:              'T',
+	SHF_COMPRESSED:       'C',
+	SHF_GNU_RETAIN:       'R',
+	SHF_GNU_MBIND:        'D',
+	SHF_EXCLUDE:          'E',
+}
+
+// String returns the flags as the key letters of readelf, from the lowest
+// bit up, such as "WA" for SHF_WRITE|SHF_ALLOC. Without the EI_OSABI of the
+// file, SHF_GNU_RETAIN and SHF_GNU_MBIND are taken to have their GNU
+// meanings and show as 'R' and 'D', as readelf shows them for GNU and
+// FreeBSD files. The remaining OS-specific bits show as a single 'o', the
+// processor-specific ones as a single 'p', which like in readelf hides
+// SHF_EXCLUDE, and any others as 'x' each.
+func (f SectionFlag) String() string {
+	var b []byte
+	for f != 0 {
+		bit := f & -f
+		f &^= bit
+		if k, ok := sectionFlagKeys[bit]; ok {
+			b = append(b, k)
+			continue
+		}
+		switch {
+		case bit&SHF_MASKOS != 0:
+			b = append(b, 'o')
+			f &^= SHF_MASKOS
+		case bit&SHF_MASKPROC != 0:
+			b = append(b, 'p')
+			f &^= SHF_MASKPROC
+		default:
+			b = append(b, 'x')
+		}
+	}
+
+	return string(b)
+}
+
+var segmentTypeNames = []intName{
+	{uint32(PT_NULL), "PT_NULL"},
+	{uint32(PT_LOAD), "PT_LOAD"},
+	{uint32(PT_DYNAMIC), "PT_DYNAMIC"},
+	{uint32(PT_INTERP), "PT_INTERP"},
+	{uint32(PT_NOTE), "PT_NOTE"},
+	{uint32(PT_SHLIB), "PT_SHLIB"},
+	{uint32(PT_PHDR), "PT_PHDR"},
+	{uint32(PT_TLS), "PT_TLS"},
+	{uint32(PT_GNU_EH_FRAME), "PT_GNU_EH_FRAME"},
+	{uint32(PT_GNU_STACK), "PT_GNU_STACK"},
+	{uint32(PT_GNU_RELRO), "PT_GNU_RELRO"},
+	{uint32(PT_GNU_PROPERTY), "PT_GNU_PROPERTY"},
+	{uint32(PT_GNU_SFRAME), "PT_GNU_SFRAME"},
+	{uint32(PT_SUNW_UNWIND), "PT_SUNW_UNWIND"},
+	{uint32(PT_SUNWBSS), "PT_SUNWBSS"},
+	{uint32(PT_SUNWSTACK), "PT_SUNWSTACK"},
+	{uint32(PT_OPENBSD_MUTABLE), "PT_OPENBSD_MUTABLE"},
+	{uint32(PT_OPENBSD_RANDOMIZE), "PT_OPENBSD_RANDOMIZE"},
+	{uint32(PT_OPENBSD_WXNEEDED), "PT_OPENBSD_WXNEEDED"},
+	{uint32(PT_OPENBSD_NOBTCFI), "PT_OPENBSD_NOBTCFI"},
+	{uint32(PT_OPENBSD_SYSCALLS), "PT_OPENBSD_SYSCALLS"},
+	{uint32(PT_OPENBSD_BOOTDATA), "PT_OPENBSD_BOOTDATA"},
+}
+
+func (t ProgramHeaderType) String() string { return stringName(uint32(t), segmentTypeNames) }
+
+var compressionTypeNames = []intName{
+	{uint32(ELFCOMPRESS_ZLIB), "ELFCOMPRESS_ZLIB"},
+	{uint32(ELFCOMPRESS_ZSTD), "ELFCOMPRESS_ZSTD"},
+}
+
+func (t CompressionType) String() string { return stringName(uint32(t), compressionTypeNames) }
+
+var programFlagNames = []intName{
+	{uint32(PF_X), "PF_X"},
+	{uint32(PF_W), "PF_W"},
+	{uint32(PF_R), "PF_R"},
+}
+
+// String returns the flags the way readelf shows them: R, W and E in fixed
+// columns, with spaces for the flags that are not set, such as "R E".
+func (f ProgramFlag) String() string {
+	b := []byte("   ")
+	if f&PF_R != 0 {
+		b[0] = 'R'
+	}
+	if f&PF_W != 0 {
+		b[1] = 'W'
+	}
+	if f&PF_X != 0 {
+		b[2] = 'E'
+	}
+
+	return string(b)
+}
+
+var symbolBindNames = []intName{
+	{uint32(STB_LOCAL), "STB_LOCAL"},
+	{uint32(STB_GLOBAL), "STB_GLOBAL"},
+	{uint32(STB_WEAK), "STB_WEAK"},
+	{uint32(STB_GNU_UNIQUE), "STB_GNU_UNIQUE"},
+}
+
+func (b SymbolBind) String() string { return stringName(uint32(b), symbolBindNames) }
+
+var symbolTypeNames = []intName{
+	{uint32(STT_NOTYPE), "STT_NOTYPE"},
+	{uint32(STT_OBJECT), "STT_OBJECT"},
+	{uint32(STT_FUNC), "STT_FUNC"},
+	{uint32(STT_SECTION), "STT_SECTION"},
+	{uint32(STT_FILE), "STT_FILE"},
+	{uint32(STT_COMMON), "STT_COMMON"},
+	{uint32(STT_TLS), "STT_TLS"},
+	{uint32(STT_GNU_IFUNC), "STT_GNU_IFUNC"},
+}
+
+func (t SymbolType) String() string { return stringName(uint32(t), symbolTypeNames) }
+
+var symbolVisibilityNames = []intName{
+	{uint32(STV_DEFAULT), "STV_DEFAULT"},
+	{uint32(STV_INTERNAL), "STV_INTERNAL"},
+	{uint32(STV_HIDDEN), "STV_HIDDEN"},
+	{uint32(STV_PROTECTED), "STV_PROTECTED"},
+}
+
+func (v SymbolVisibility) String() string { return stringName(uint32(v), symbolVisibilityNames) }
+
+// dynTagNames leaves out the aliases of DT_PREINIT_ARRAY, DT_SYMINENT,
+// DT_SYMINFO and DT_FILTER, which share their values.
+var dynTagNames = []intName{
+	{uint32(DT_NULL), "DT_NULL"},
+	{uint32(DT_NEEDED), "DT_NEEDED"},
+	{uint32(DT_PLTRELSZ), "DT_PLTRELSZ"},
+	{uint32(DT_PLTGOT), "DT_PLTGOT"},
+	{uint32(DT_HASH), "DT_HASH"},
+	{uint32(DT_STRTAB), "DT_STRTAB"},
+	{uint32(DT_SYMTAB), "DT_SYMTAB"},
+	{uint32(DT_RELA), "DT_RELA"},
+	{uint32(DT_RELASZ), "DT_RELASZ"},
+	{uint32(DT_RELAENT), "DT_RELAENT"},
+	{uint32(DT_STRSZ), "DT_STRSZ"},
+	{uint32(DT_SYMENT), "DT_SYMENT"},
+	{uint32(DT_INIT), "DT_INIT"},
+	{uint32(DT_FINI), "DT_FINI"},
+	{uint32(DT_SONAME), "DT_SONAME"},
+	{uint32(DT_RPATH), "DT_RPATH"},
+	{uint32(DT_SYMBOLIC), "DT_SYMBOLIC"},
+	{uint32(DT_REL), "DT_REL"},
+	{uint32(DT_RELSZ), "DT_RELSZ"},
+	{uint32(DT_RELENT), "DT_RELENT"},
+	{uint32(DT_PLTREL), "DT_PLTREL"},
+	{uint32(DT_DEBUG), "DT_DEBUG"},
+	{uint32(DT_TEXTREL), "DT_TEXTREL"},
+	{uint32(DT_JMPREL), "DT_JMPREL"},
+	{uint32(DT_BIND_NOW), "DT_BIND_NOW"},
+	{uint32(DT_INIT_ARRAY), "DT_INIT_ARRAY"},
+	{uint32(DT_FINI_ARRAY), "DT_FINI_ARRAY"},
+	{uint32(DT_INIT_ARRAYSZ), "DT_INIT_ARRAYSZ"},
+	{uint32(DT_FINI_ARRAYSZ), "DT_FINI_ARRAYSZ"},
+	{uint32(DT_RUNPATH), "DT_RUNPATH"},
+	{uint32(DT_FLAGS), "DT_FLAGS"},
+	{uint32(DT_PREINIT_ARRAY), "DT_PREINIT_ARRAY"},
+	{uint32(DT_PREINIT_ARRAYSZ), "DT_PREINIT_ARRAYSZ"},
+	{uint32(DT_SYMTAB_SHNDX), "DT_SYMTAB_SHNDX"},
+	{uint32(DT_RELRSZ), "DT_RELRSZ"},
+	{uint32(DT_RELR), "DT_RELR"},
+	{uint32(DT_RELRENT), "DT_RELRENT"},
+	{uint32(DT_GNU_PRELINKED), "DT_GNU_PRELINKED"},
+	{uint32(DT_GNU_CONFLICTSZ), "DT_GNU_CONFLICTSZ"},
+	{uint32(DT_GNU_LIBLISTSZ), "DT_GNU_LIBLISTSZ"},
+	{uint32(DT_CHECKSUM), "DT_CHECKSUM"},
+	{uint32(DT_PLTPADSZ), "DT_PLTPADSZ"},
+	{uint32(DT_MOVEENT), "DT_MOVEENT"},
+	{uint32(DT_MOVESZ), "DT_MOVESZ"},
+	{uint32(DT_FEATURE_1), "DT_FEATURE_1"},
+	{uint32(DT_POSFLAG_1), "DT_POSFLAG_1"},
+	{uint32(DT_SYMINSZ), "DT_SYMINSZ"},
+	{uint32(DT_SYMINENT), "DT_SYMINENT"},
+	{uint32(DT_GNU_HASH), "DT_GNU_HASH"},
+	{uint32(DT_TLSDESC_PLT), "DT_TLSDESC_PLT"},
+	{uint32(DT_TLSDESC_GOT), "DT_TLSDESC_GOT"},
+	{uint32(DT_GNU_CONFLICT), "DT_GNU_CONFLICT"},
+	{uint32(DT_GNU_LIBLIST), "DT_GNU_LIBLIST"},
+	{uint32(DT_CONFIG), "DT_CONFIG"},
+	{uint32(DT_DEPAUDIT), "DT_DEPAUDIT"},
+	{uint32(DT_AUDIT), "DT_AUDIT"},
+	{uint32(DT_PLTPAD), "DT_PLTPAD"},
+	{uint32(DT_MOVETAB), "DT_MOVETAB"},
+	{uint32(DT_SYMINFO), "DT_SYMINFO"},
+	{uint32(DT_VERSYM), "DT_VERSYM"},
+	{uint32(DT_RELACOUNT), "DT_RELACOUNT"},
+	{uint32(DT_RELCOUNT), "DT_RELCOUNT"},
+	{uint32(DT_FLAGS_1), "DT_FLAGS_1"},
+	{uint32(DT_VERDEF), "DT_VERDEF"},
+	{uint32(DT_VERDEFNUM), "DT_VERDEFNUM"},
+	{uint32(DT_VERNEED), "DT_VERNEED"},
+	{uint32(DT_VERNEEDNUM), "DT_VERNEEDNUM"},
+	{uint32(DT_AUXILIARY), "DT_AUXILIARY"},
+	{uint32(DT_FILTER), "DT_FILTER"},
+}
+
+// String names t. Tags that do not fit the names table are always unnamed.
+func (t DynTag) String() string {
+	if t < 0 || t > 0xffffffff {
+		return strconv.FormatInt(int64(t), 10)
+	}
+
+	return stringName(uint32(t), dynTagNames)
+}
+
+// Note types only have a meaning together with the owner of the note.
+var (
+	gnuNoteTypeNames = []intName{
+		{uint32(NT_GNU_ABI_TAG), "NT_GNU_ABI_TAG"},
+		{uint32(NT_GNU_HWCAP), "NT_GNU_HWCAP"},
+		{uint32(NT_GNU_BUILD_ID), "NT_GNU_BUILD_ID"},
+		{uint32(NT_GNU_GOLD_VERSION), "NT_GNU_GOLD_VERSION"},
+		{uint32(NT_GNU_PROPERTY_TYPE_0), "NT_GNU_PROPERTY_TYPE_0"},
+	}
+	goNoteTypeNames   = []intName{{uint32(NT_GO_BUILDID), "NT_GO_BUILDID"}}
+	fdoNoteTypeNames  = []intName{{uint32(NT_FDO_PACKAGING_METADATA), "NT_FDO_PACKAGING_METADATA"}}
+	coreNoteTypeNames = []intName{
+		{uint32(NT_PRSTATUS), "NT_PRSTATUS"},
+		{uint32(NT_FPREGSET), "NT_FPREGSET"},
+		{uint32(NT_PRPSINFO), "NT_PRPSINFO"},
+		{uint32(NT_TASKSTRUCT), "NT_TASKSTRUCT"},
+		{uint32(NT_AUXV), "NT_AUXV"},
+		{uint32(NT_X86_XSTATE), "NT_X86_XSTATE"},
+		{uint32(NT_ARM_VFP), "NT_ARM_VFP"},
+		{uint32(NT_SIGINFO), "NT_SIGINFO"},
+		{uint32(NT_FILE), "NT_FILE"},
+		{uint32(NT_PRXFPREG), "NT_PRXFPREG"},
+	}
+)
+
+// String names t as the type of a core file note, whose owner is
+// ELF_NOTE_CORE; the notes of other owners reuse the same values for other
+// types.
+func (t NoteType) String() string { return stringName(uint32(t), coreNoteTypeNames) }
+
+// noteTypeName returns the name of the type of n, which depends on its
+// owner.
+func noteTypeName(n *Note) string {
+	var names []intName
+	switch n.Name {
+	case ELF_NOTE_GNU:
+		names = gnuNoteTypeNames
+	case ELF_NOTE_GO:
+		names = goNoteTypeNames
+	case ELF_NOTE_FDO:
+		names = fdoNoteTypeNames
+	case ELF_NOTE_CORE, ELF_NOTE_LINUX:
+		names = coreNoteTypeNames
+	}
+
+	return stringName(uint32(n.Type), names)
+}
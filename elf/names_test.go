@@ -0,0 +1,66 @@
+package elf_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+func TestString(t *testing.T) {
+	cases := []struct {
+		v    fmt.Stringer
+		want string
+	}{
+		{elf.ET_DYN, "ET_DYN"},
+		{elf.Type(0xfe01), "65025"},
+		{elf.EM_X86_64, "EM_X86_64"},
+		{elf.EM_486, "EM_486"},
+		{elf.EM_S390, "EM_S390"},
+		{elf.EM_SPARCV9, "EM_SPARCV9"},
+		{elf.ELFOSABI_GNU, "ELFOSABI_LINUX"},
+		{elf.ELFOSABI_STANDALONE, "ELFOSABI_STANDALONE"},
+		{elf.EM_BPF, "EM_BPF"},
+		{elf.EM_LOONGARCH, "EM_LOONGARCH"},
+		{elf.EM_ALPHA, "EM_ALPHA"},
+		{elf.Machine(0x1234), "4660"},
+		{elf.SHT_PROGBITS, "SHT_PROGBITS"},
+		{elf.SHT_GNU_versym, "SHT_GNU_versym"},
+		{elf.SHT_LLVM_ADDRSIG, "SHT_LLVM_ADDRSIG"},
+		{elf.SHT_ANDROID_RELR, "SHT_ANDROID_RELR"},
+		{elf.SectionHeaderType(0x70000001), "1879048193"},
+		{elf.PT_LOAD, "PT_LOAD"},
+		{elf.PT_GNU_STACK, "PT_GNU_STACK"},
+		{elf.PT_OPENBSD_RANDOMIZE, "PT_OPENBSD_RANDOMIZE"},
+		{elf.SHF_WRITE | elf.SHF_ALLOC, "WA"},
+		{elf.SHF_ALLOC | elf.SHF_EXECINSTR, "AX"},
+		{elf.SHF_MERGE | elf.SHF_STRINGS | elf.SHF_INFO_LINK, "MSI"},
+		{elf.SHF_ALLOC | elf.SHF_EXCLUDE, "AE"},
+		{elf.SHF_ALLOC | elf.SHF_GNU_RETAIN | elf.SHF_GNU_MBIND | 0x10000000 | 0x1000, "AxRDp"},
+		// As in readelf, the 'p' stands for SHF_EXCLUDE too.
+		{elf.SHF_ALLOC | elf.SHF_COMPRESSED | elf.SHF_EXCLUDE | 0x00c00000 | 0x60000000 | 0x3000, "ACxxop"},
+		{elf.SectionFlag(0), ""},
+		{elf.PF_R | elf.PF_X, "R E"},
+		{elf.PF_R | elf.PF_W, "RW "},
+		{elf.PF_R | elf.PF_W | elf.PF_X, "RWE"},
+		{elf.ProgramFlag(0), "   "},
+		{elf.STB_WEAK, "STB_WEAK"},
+		{elf.SymbolBind(12), "12"},
+		{elf.STT_GNU_IFUNC, "STT_GNU_IFUNC"},
+		{elf.STV_HIDDEN, "STV_HIDDEN"},
+		{elf.DT_GNU_HASH, "DT_GNU_HASH"},
+		{elf.DT_FILTER, "DT_FILTER"},
+		{elf.DynTag(-1), "-1"},
+		{elf.DynTag(0x100000000), "4294967296"},
+		{elf.NT_PRSTATUS, "NT_PRSTATUS"},
+		{elf.NT_FILE, "NT_FILE"},
+		{elf.ELFCOMPRESS_ZSTD, "ELFCOMPRESS_ZSTD"},
+		{elf.CompressionType(7), "7"},
+	}
+
+	for _, tc := range cases {
+		if s := tc.v.String(); s != tc.want {
+			t.Errorf("%#v:\n\thave %q\n\twant %q\n", tc.v, s, tc.want)
+		}
+	}
+}
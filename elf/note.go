@@ -0,0 +1,282 @@
+package elf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Note represents an entry of a SHT_NOTE section or PT_NOTE segment. The
+// meaning of Type depends on the owner Name.
+type Note struct {
+	Name string
+	Type NoteType
+	Desc []byte
+}
+
+// GNUABITag is the descriptor of a NT_GNU_ABI_TAG note: the OS and the
+// earliest kernel version the object is compatible with.
+type GNUABITag struct {
+	OS       GNUABIOS
+	Major    uint32
+	Minor    uint32
+	Subminor uint32
+}
+
+// GNUProperty is an entry of a NT_GNU_PROPERTY_TYPE_0 note. Value holds Data
+// decoded as a 4-byte word when the property carries one, as the x86 and
+// AArch64 feature and ISA bitmasks do.
+type GNUProperty struct {
+	Type  GNUPropertyType
+	Data  []byte
+	Value uint32
+}
+
+// PackageMetadata is the JSON descriptor of the FDO packaging metadata note
+// (.note.package) described by the systemd ELF package metadata spec.
+type PackageMetadata struct {
+	Type         string `json:"type,omitempty"`
+	OS           string `json:"os,omitempty"`
+	OSVersion    string `json:"osVersion,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Version      string `json:"version,omitempty"`
+	Architecture string `json:"architecture,omitempty"`
+	OSCPE        string `json:"osCpe,omitempty"`
+	DebugInfoURL string `json:"debugInfoUrl,omitempty"`
+}
+
+const noteHeaderSize = 12
+
+// Notes returns the notes of every SHT_NOTE section followed by those of
+// the PT_NOTE segments that no SHT_NOTE section overlaps. Sections take
+// precedence because they are what linkers and strip keep up to date, and
+// they usually cover the same notes as the segments; a segment is still
+// read when only it holds notes, as in files without section headers. When
+// a note is malformed, the notes before it are returned along with the
+// error.
+func (e *File) Notes() ([]*Note, error) {
+	ss := e.SectionsByType(SHT_NOTE)
+	var ns []*Note
+	for _, s := range ss {
+		sns, err := e.SectionNotes(s)
+		ns = append(ns, sns...)
+		if err != nil {
+			return ns, err
+		}
+	}
+
+	for _, sg := range e.SegmentsByType(PT_NOTE) {
+		if noteSectionOverlaps(ss, sg) {
+			continue
+		}
+		sgns, err := e.SegmentNotes(sg)
+		ns = append(ns, sgns...)
+		if err != nil {
+			return ns, err
+		}
+	}
+
+	return ns, nil
+}
+
+// noteSectionOverlaps reports whether any of the SHT_NOTE sections ss shares
+// file bytes with the segment sg.
+func noteSectionOverlaps(ss []*Section, sg *Segment) bool {
+	start, end := sg.Header.Offset, sg.Header.Offset+sg.Header.Filesz
+	for _, s := range ss {
+		if s.Header.Offset < end && start < s.Header.Offset+s.Header.Size {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SectionNotes decodes the notes of a SHT_NOTE section. When a note is
+// malformed, the notes before it are returned along with the error.
+func (e *File) SectionNotes(s *Section) ([]*Note, error) {
+	raw, err := s.Data()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read note section %s: %w", s.Name, err)
+	}
+
+	ns, err := e.parseNotes(raw, s.Header.Addralign)
+	if err != nil {
+		return ns, fmt.Errorf("invalid note section %s: %w", s.Name, err)
+	}
+
+	return ns, nil
+}
+
+// SegmentNotes decodes the notes of a PT_NOTE segment. When a note is
+// malformed, the notes before it are returned along with the error.
+func (e *File) SegmentNotes(sg *Segment) ([]*Note, error) {
+	raw, err := sg.Data()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read note segment at offset %#x: %w", sg.Header.Offset, err)
+	}
+
+	ns, err := e.parseNotes(raw, sg.Header.Align)
+	if err != nil {
+		return ns, fmt.Errorf("invalid note segment at offset %#x: %w", sg.Header.Offset, err)
+	}
+
+	return ns, nil
+}
+
+// parseNotes decodes consecutive notes. The name and descriptor are padded
+// to align, which is 8 for notes such as NT_GNU_PROPERTY_TYPE_0 in 64-bit
+// objects and 4 otherwise. On error the notes decoded so far are returned
+// with it.
+func (e *File) parseNotes(raw []byte, align uint64) ([]*Note, error) {
+	if align != 8 {
+		align = 4
+	}
+
+	var ns []*Note
+	for off := uint64(0); off+noteHeaderSize <= uint64(len(raw)); {
+		namesz := uint64(e.Endianness.Uint32(raw[off:]))
+		descsz := uint64(e.Endianness.Uint32(raw[off+4:]))
+		typ := NoteType(e.Endianness.Uint32(raw[off+8:]))
+
+		name, err := safeSlice(raw, off+noteHeaderSize, namesz)
+		if err != nil {
+			return ns, fmt.Errorf("invalid name of note at offset %#x: %w", off, err)
+		}
+
+		descOff := off + alignUp(noteHeaderSize+namesz, align)
+		desc, err := safeSlice(raw, descOff, descsz)
+		if err != nil {
+			return ns, fmt.Errorf("invalid descriptor of note at offset %#x: %w", off, err)
+		}
+
+		ns = append(ns, &Note{
+			Name: string(bytes.TrimRight(name, "\x00")),
+			Type: typ,
+			Desc: desc,
+		})
+
+		off = descOff + alignUp(descsz, align)
+	}
+
+	return ns, nil
+}
+
+func alignUp(v, align uint64) uint64 {
+	return (v + align - 1) &^ (align - 1)
+}
+
+// findNote returns the first note with the given owner and type, or nil if
+// there is none.
+func (e *File) findNote(name string, typ NoteType) (*Note, error) {
+	ns, err := e.Notes()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range ns {
+		if n.Name == name && n.Type == typ {
+			return n, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// GNUBuildID returns the descriptor of the NT_GNU_BUILD_ID note, or nil if
+// there is none.
+func (e *File) GNUBuildID() ([]byte, error) {
+	n, err := e.findNote(ELF_NOTE_GNU, NT_GNU_BUILD_ID)
+	if err != nil || n == nil {
+		return nil, err
+	}
+
+	return n.Desc, nil
+}
+
+// GNUABITag decodes the NT_GNU_ABI_TAG note, or returns nil if there is none.
+func (e *File) GNUABITag() (*GNUABITag, error) {
+	n, err := e.findNote(ELF_NOTE_GNU, NT_GNU_ABI_TAG)
+	if err != nil || n == nil {
+		return nil, err
+	}
+
+	if len(n.Desc) < 16 {
+		return nil, fmt.Errorf("invalid NT_GNU_ABI_TAG descriptor size: %d", len(n.Desc))
+	}
+
+	return &GNUABITag{
+		OS:       GNUABIOS(e.Endianness.Uint32(n.Desc[0:])),
+		Major:    e.Endianness.Uint32(n.Desc[4:]),
+		Minor:    e.Endianness.Uint32(n.Desc[8:]),
+		Subminor: e.Endianness.Uint32(n.Desc[12:]),
+	}, nil
+}
+
+// GNUProperties decodes the NT_GNU_PROPERTY_TYPE_0 note, or returns nil if
+// there is none.
+func (e *File) GNUProperties() ([]*GNUProperty, error) {
+	n, err := e.findNote(ELF_NOTE_GNU, NT_GNU_PROPERTY_TYPE_0)
+	if err != nil || n == nil {
+		return nil, err
+	}
+
+	return e.decodeGNUProperties(n.Desc)
+}
+
+// decodeGNUProperties decodes an array of pr_type, pr_datasz and pr_data
+// entries whose data is padded to the word size of the file.
+func (e *File) decodeGNUProperties(desc []byte) ([]*GNUProperty, error) {
+	align := uint64(8)
+	if e.is32() {
+		align = 4
+	}
+
+	var ps []*GNUProperty
+	for off := uint64(0); off+8 <= uint64(len(desc)); {
+		typ := GNUPropertyType(e.Endianness.Uint32(desc[off:]))
+		datasz := uint64(e.Endianness.Uint32(desc[off+4:]))
+
+		data, err := safeSlice(desc, off+8, datasz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid data of GNU property %#x: %w", uint32(typ), err)
+		}
+
+		p := &GNUProperty{Type: typ, Data: data}
+		if datasz == 4 {
+			p.Value = e.Endianness.Uint32(data)
+		}
+		ps = append(ps, p)
+
+		off += 8 + alignUp(datasz, align)
+	}
+
+	return ps, nil
+}
+
+// GoBuildID returns the build ID recorded by the Go linker in the
+// NT_GO_BUILDID note (.note.go.buildid), or an empty string if there is none.
+func (e *File) GoBuildID() (string, error) {
+	n, err := e.findNote(ELF_NOTE_GO, NT_GO_BUILDID)
+	if err != nil || n == nil {
+		return "", err
+	}
+
+	return string(n.Desc), nil
+}
+
+// PackageMetadata decodes the NT_FDO_PACKAGING_METADATA note, or returns nil
+// if there is none.
+func (e *File) PackageMetadata() (*PackageMetadata, error) {
+	n, err := e.findNote(ELF_NOTE_FDO, NT_FDO_PACKAGING_METADATA)
+	if err != nil || n == nil {
+		return nil, err
+	}
+
+	var pm PackageMetadata
+	if err := json.Unmarshal(bytes.TrimRight(n.Desc, "\x00"), &pm); err != nil {
+		return nil, fmt.Errorf("invalid package metadata: %w", err)
+	}
+
+	return &pm, nil
+}
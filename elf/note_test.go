@@ -0,0 +1,181 @@
+package elf_test
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+const hello = "../testdata/hello_linux_amd64"
+
+func TestNotes(t *testing.T) {
+	b, err := os.ReadFile(hello)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A copy without section headers checks the PT_NOTE fallback.
+	stripped := append([]byte(nil), b...)
+	le := binary.LittleEndian
+	le.PutUint64(stripped[40:], 0) // e_shoff
+	le.PutUint16(stripped[60:], 0) // e_shnum
+	le.PutUint16(stripped[62:], 0) // e_shstrndx
+
+	for name, raw := range map[string][]byte{"original": b, "stripped": stripped} {
+		t.Run(name, func(t *testing.T) {
+			e, err := elf.New(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ns, err := e.Notes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var types []elf.NoteType
+			for _, n := range ns {
+				types = append(types, n.Type)
+			}
+			want := []elf.NoteType{elf.NT_GNU_PROPERTY_TYPE_0, elf.NT_GNU_BUILD_ID, elf.NT_GNU_ABI_TAG, elf.NT_FDO_PACKAGING_METADATA}
+			if !reflect.DeepEqual(types, want) {
+				t.Errorf("have %#v, want %#v", types, want)
+			}
+
+			id, err := e.GNUBuildID()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := hex.EncodeToString(id); s != "905c54dc7623d037eeaae8d9aaa4eac6c43707d7" {
+				t.Errorf("GNUBuildID: have %s", s)
+			}
+
+			tag, err := e.GNUABITag()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := (elf.GNUABITag{OS: elf.ELF_NOTE_OS_LINUX, Major: 3, Minor: 2, Subminor: 0}); tag == nil || *tag != want {
+				t.Errorf("GNUABITag: have %#v, want %#v", tag, want)
+			}
+
+			ps, err := e.GNUProperties()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ps) != 2 {
+				t.Fatalf("have %d properties, want 2", len(ps))
+			}
+			if ps[0].Type != elf.GNU_PROPERTY_X86_FEATURE_1_AND ||
+				elf.X86Feature1(ps[0].Value) != elf.GNU_PROPERTY_X86_FEATURE_1_IBT|elf.GNU_PROPERTY_X86_FEATURE_1_SHSTK {
+				t.Errorf("have %#v, want IBT and SHSTK", ps[0])
+			}
+			if ps[1].Type != elf.GNU_PROPERTY_X86_ISA_1_NEEDED || elf.X86ISA1(ps[1].Value) != elf.GNU_PROPERTY_X86_ISA_1_BASELINE {
+				t.Errorf("have %#v, want x86-64-baseline", ps[1])
+			}
+
+			pm, err := e.PackageMetadata()
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantPM := elf.PackageMetadata{Type: "deb", OS: "debian", OSVersion: "12", Name: "hello", Version: "1.0-1", Architecture: "amd64"}
+			if pm == nil || *pm != wantPM {
+				t.Errorf("PackageMetadata:\n\thave %#v\n\twant %#v\n", pm, wantPM)
+			}
+		})
+	}
+}
+
+func TestGoBuildID(t *testing.T) {
+	b, err := os.ReadFile("../testdata/elf_linux_amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := elf.New(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := e.GoBuildID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "cRp_pf6NUY9l_jQZIZfM/BUu5__jq3YzS8qx5nMck/lPt4QTlYaDNV6G9yOfY4/RLSXUfyeILcEnd6h-dlf"; id != want {
+		t.Errorf("have %q, want %q", id, want)
+	}
+
+	gnu, err := e.GNUBuildID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gnu != nil {
+		t.Errorf("Go binary should have no GNU build ID, have %x", gnu)
+	}
+}
+
+func TestNotesMalformed(t *testing.T) {
+	le := binary.LittleEndian
+	// A valid empty NT_GNU_ABI_TAG note before the malformed one.
+	note := make([]byte, 32)
+	le.PutUint32(note[0:], 4) // namesz
+	le.PutUint32(note[8:], 1)
+	copy(note[12:], "GNU\x00")
+	le.PutUint32(note[16:], 4)   // namesz
+	le.PutUint32(note[20:], 100) // descsz beyond the section
+	le.PutUint32(note[24:], 3)
+	copy(note[28:], "GNU\x00")
+
+	raw := synthELF(false, le, []synthSection{
+		{name: ".note.gnu.build-id", typ: elf.SHT_NOTE, align: 4, data: note},
+	})
+
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both the sections and the segments return the notes before the
+	// malformed one with the error.
+	ns, err := e.Notes()
+	if err == nil {
+		t.Fatal("expected error for note descriptor past the end of the section")
+	}
+	if len(ns) != 1 || ns[0].Type != elf.NT_GNU_ABI_TAG {
+		t.Errorf("have notes %v", ns)
+	}
+	if ns, err := e.SectionNotes(e.Sections[1]); err == nil || len(ns) != 1 {
+		t.Errorf("SectionNotes: have %d notes, error %v", len(ns), err)
+	}
+}
+
+func TestNotesSegmentOnly(t *testing.T) {
+	b := elf.NewBuilder(elf.ELFCLASS64, binary.LittleEndian, elf.EM_X86_64, elf.ET_EXEC)
+	b.AddNotes(".note.a", &elf.Note{Name: elf.ELF_NOTE_GNU, Type: elf.NT_GNU_BUILD_ID, Desc: []byte{1, 2, 3, 4}})
+	covered := b.AddNotes(".note.b", &elf.Note{Name: elf.ELF_NOTE_GNU, Type: elf.NT_GNU_ABI_TAG, Desc: make([]byte, 16)})
+	b.AddSegment(elf.ProgramHeader{Type: elf.PT_NOTE, Align: 4}, covered)
+	// Only the segment still tells that .note.b holds notes.
+	b.SectionHeader(covered).Type = elf.SHT_PROGBITS
+	raw, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ns, err := e.Notes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []elf.NoteType
+	for _, n := range ns {
+		types = append(types, n.Type)
+	}
+	if want := []elf.NoteType{elf.NT_GNU_BUILD_ID, elf.NT_GNU_ABI_TAG}; !reflect.DeepEqual(types, want) {
+		t.Errorf("have %v, want %v", types, want)
+	}
+}
@@ -0,0 +1,335 @@
+package elf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// RelocationType is a machine specific relocation type. Its String method
+// returns names such as R_X86_64_GLOB_DAT for the supported machines.
+type RelocationType struct {
+	Machine Machine
+	Value   uint32
+}
+
+func (t RelocationType) String() string {
+	switch t.Machine {
+	case EM_X86_64:
+		return R_X86_64(t.Value).String()
+	case EM_386, EM_486:
+		return R_386(t.Value).String()
+	case EM_AARCH64:
+		return R_AARCH64(t.Value).String()
+	case EM_ARM:
+		return R_ARM(t.Value).String()
+	case EM_RISCV:
+		return R_RISCV(t.Value).String()
+	case EM_PPC64:
+		return R_PPC64(t.Value).String()
+	case EM_MIPS:
+		return R_MIPS(t.Value).String()
+	}
+
+	return stringName(t.Value, nil)
+}
+
+// Relocation represents an entry of a SHT_REL, SHT_RELA or SHT_RELR table.
+// Symbol is an index into the symbol table the relocation section links to.
+// Addend is only stored in the entry for SHT_RELA; for SHT_REL and SHT_RELR
+// the addend is the value already present at Offset and Addend is 0.
+type Relocation struct {
+	Offset uint64
+	Symbol uint32
+	Type   RelocationType
+	Addend int64
+}
+
+// RelocationTable is a decoded relocation section together with the section
+// its relocations apply to (sh_info) and the symbol table they reference
+// (sh_link). Target and SymbolTable are nil when the corresponding field is
+// 0, as it is for dynamic relocations.
+type RelocationTable struct {
+	Section     *Section
+	Target      *Section
+	SymbolTable *Section
+	Relocations []*Relocation
+}
+
+type rel32 struct {
+	Offset uint32
+	Info   uint32
+}
+
+type rela32 struct {
+	Offset uint32
+	Info   uint32
+	Addend int32
+}
+
+type rel64 struct {
+	Offset uint64
+	Info   uint64
+}
+
+type rela64 struct {
+	Offset uint64
+	Info   uint64
+	Addend int64
+}
+
+var (
+	sizeRel32  = uint64(binary.Size(rel32{}))
+	sizeRela32 = uint64(binary.Size(rela32{}))
+	sizeRel64  = uint64(binary.Size(rel64{}))
+	sizeRela64 = uint64(binary.Size(rela64{}))
+)
+
+// relativeTypes is the R_*_RELATIVE type implied by each SHT_RELR entry.
+var relativeTypes = map[Machine]uint32{
+	EM_X86_64:  uint32(R_X86_64_RELATIVE),
+	EM_386:     uint32(R_386_RELATIVE),
+	EM_486:     uint32(R_386_RELATIVE),
+	EM_AARCH64: uint32(R_AARCH64_RELATIVE),
+	EM_ARM:     uint32(R_ARM_RELATIVE),
+	EM_RISCV:   uint32(R_RISCV_RELATIVE),
+	EM_PPC64:   uint32(R_PPC64_RELATIVE),
+	EM_MIPS:    uint32(R_MIPS_REL32),
+}
+
+// RelocationTables decodes every SHT_REL, SHT_RELA and SHT_RELR section.
+func (e *File) RelocationTables() ([]*RelocationTable, error) {
+	var rts []*RelocationTable
+	for _, s := range e.Sections {
+		t := s.Header.Type
+		if t != SHT_REL && t != SHT_RELA && t != SHT_RELR {
+			continue
+		}
+
+		rels, err := e.Relocations(s)
+		if err != nil {
+			return nil, err
+		}
+
+		rt := &RelocationTable{Section: s, Relocations: rels}
+		if s.Header.Info != 0 {
+			rt.Target = e.SectionAt(s.Header.Info)
+		}
+		if s.Header.Link != 0 {
+			rt.SymbolTable = e.SectionAt(s.Header.Link)
+		}
+		rts = append(rts, rt)
+	}
+
+	return rts, nil
+}
+
+// Relocations decodes the relocation section s, which must be of type
+// SHT_REL, SHT_RELA or SHT_RELR.
+func (e *File) Relocations(s *Section) ([]*Relocation, error) {
+	raw, err := s.Data()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read relocation section %s: %w", s.Name, err)
+	}
+
+	rels, err := e.decodeRelocations(raw, s.Header.Type, s.Header.EntSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid relocation section %s: %w", s.Name, err)
+	}
+
+	return rels, nil
+}
+
+// DynamicRelocations decodes the relocations the dynamic loader processes,
+// located through DT_RELA, DT_REL, DT_JMPREL and DT_RELR. It works on files
+// without section headers.
+func (e *File) DynamicRelocations() ([]*Relocation, error) {
+	des, err := e.DynamicEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	tables := []struct {
+		addr, size, ent DynTag
+		typ             SectionHeaderType
+	}{
+		{DT_RELA, DT_RELASZ, DT_RELAENT, SHT_RELA},
+		{DT_REL, DT_RELSZ, DT_RELENT, SHT_REL},
+		{DT_RELR, DT_RELRSZ, DT_RELRENT, SHT_RELR},
+	}
+
+	var rels []*Relocation
+	for _, t := range tables {
+		addr, ok := findDynValue(des, t.addr)
+		if !ok {
+			continue
+		}
+		size, _ := findDynValue(des, t.size)
+		ent, _ := findDynValue(des, t.ent)
+
+		rs, err := e.dynamicRelocations(addr, size, ent, t.typ)
+		if err != nil {
+			return nil, err
+		}
+		rels = append(rels, rs...)
+	}
+
+	if addr, ok := findDynValue(des, DT_JMPREL); ok {
+		size, _ := findDynValue(des, DT_PLTRELSZ)
+		typ := SHT_RELA
+		if pltrel, _ := findDynValue(des, DT_PLTREL); DynTag(pltrel) == DT_REL {
+			typ = SHT_REL
+		}
+
+		rs, err := e.dynamicRelocations(addr, size, 0, typ)
+		if err != nil {
+			return nil, err
+		}
+		rels = append(rels, rs...)
+	}
+
+	return rels, nil
+}
+
+func (e *File) dynamicRelocations(addr, size, entSize uint64, typ SectionHeaderType) ([]*Relocation, error) {
+	raw, err := e.ReadAddr(addr, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dynamic relocations at %#x: %w", addr, err)
+	}
+
+	rels, err := e.decodeRelocations(raw, typ, entSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dynamic relocations at %#x: %w", addr, err)
+	}
+
+	return rels, nil
+}
+
+func (e *File) decodeRelocations(raw []byte, typ SectionHeaderType, entSize uint64) ([]*Relocation, error) {
+	if typ == SHT_RELR {
+		return e.decodeRelr(raw, entSize)
+	}
+
+	is32 := e.is32()
+	var structSize uint64
+	switch {
+	case typ == SHT_REL && is32:
+		structSize = sizeRel32
+	case typ == SHT_REL:
+		structSize = sizeRel64
+	case typ == SHT_RELA && is32:
+		structSize = sizeRela32
+	case typ == SHT_RELA:
+		structSize = sizeRela64
+	default:
+		return nil, fmt.Errorf("unsupported relocation section type: %d", typ)
+	}
+	if entSize == 0 {
+		entSize = structSize
+	}
+	if entSize < structSize {
+		return nil, fmt.Errorf("invalid relocation entry size: %d", entSize)
+	}
+
+	n := uint64(len(raw)) / entSize
+	rels := make([]*Relocation, n)
+	for i := uint64(0); i < n; i++ {
+		r := bytes.NewReader(raw[i*entSize : i*entSize+structSize])
+
+		var off, info uint64
+		var addend int64
+		var err error
+		switch {
+		case typ == SHT_REL && is32:
+			var rl rel32
+			err = binary.Read(r, e.Endianness, &rl)
+			off, info = uint64(rl.Offset), uint64(rl.Info)
+		case typ == SHT_REL:
+			var rl rel64
+			err = binary.Read(r, e.Endianness, &rl)
+			off, info = rl.Offset, rl.Info
+		case is32:
+			var rl rela32
+			err = binary.Read(r, e.Endianness, &rl)
+			off, info, addend = uint64(rl.Offset), uint64(rl.Info), int64(rl.Addend)
+		default:
+			var rl rela64
+			err = binary.Read(r, e.Endianness, &rl)
+			off, info, addend = rl.Offset, rl.Info, rl.Addend
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read relocation %d: %w", i, err)
+		}
+
+		sym, t := e.splitRelocationInfo(info)
+		rels[i] = &Relocation{
+			Offset: off,
+			Symbol: sym,
+			Type:   RelocationType{Machine: e.Header.Machine, Value: t},
+			Addend: addend,
+		}
+	}
+
+	return rels, nil
+}
+
+// splitRelocationInfo splits r_info into the symbol index and the type. For
+// 64-bit MIPS only the first of the three packed types is returned.
+func (e *File) splitRelocationInfo(info uint64) (uint32, uint32) {
+	if e.is32() {
+		return uint32(info >> 8), uint32(info & 0xff)
+	}
+
+	if e.Header.Machine == EM_MIPS {
+		if e.Endianness == binary.LittleEndian {
+			// r_info is laid out as r_sym followed by single byte fields,
+			// so reading it as a little-endian word reverses the bytes.
+			return uint32(info), uint32(info >> 56)
+		}
+		return uint32(info >> 32), uint32(info & 0xff)
+	}
+
+	return uint32(info >> 32), uint32(info)
+}
+
+// decodeRelr expands the packed relative relocations of a SHT_RELR table.
+// An even entry is an address to relocate; an odd entry is a bitmap whose
+// bits select the following words after the last address.
+func (e *File) decodeRelr(raw []byte, entSize uint64) ([]*Relocation, error) {
+	wordSize := uint64(8)
+	if e.is32() {
+		wordSize = 4
+	}
+	if entSize != 0 && entSize != wordSize {
+		return nil, fmt.Errorf("invalid relr entry size: %d", entSize)
+	}
+
+	t := RelocationType{Machine: e.Header.Machine, Value: relativeTypes[e.Header.Machine]}
+	bits := wordSize*8 - 1
+
+	var rels []*Relocation
+	var base uint64
+	for i := uint64(0); i+wordSize <= uint64(len(raw)); i += wordSize {
+		var entry uint64
+		if wordSize == 4 {
+			entry = uint64(e.Endianness.Uint32(raw[i:]))
+		} else {
+			entry = e.Endianness.Uint64(raw[i:])
+		}
+
+		if entry&1 == 0 {
+			rels = append(rels, &Relocation{Offset: entry, Type: t})
+			base = entry + wordSize
+			continue
+		}
+
+		for b := uint64(0); b < bits; b++ {
+			if entry&(2<<b) != 0 {
+				rels = append(rels, &Relocation{Offset: base + b*wordSize, Type: t})
+			}
+		}
+		base += bits * wordSize
+	}
+
+	return rels, nil
+}
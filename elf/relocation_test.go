@@ -0,0 +1,155 @@
+package elf_test
+
+import (
+	"encoding/binary"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+func TestRelocationTables(t *testing.T) {
+	b, err := os.ReadFile("../testdata/libsample_linux_amd64.o")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := elf.New(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rts, err := e.RelocationTables()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, len(rts))
+	for i, rt := range rts {
+		names[i] = rt.Section.Name
+	}
+	if want := []string{".rela.text", ".rela.text.startup", ".rela.init_array", ".rela.eh_frame"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("have %#v, want %#v", names, want)
+	}
+
+	text := rts[0]
+	if text.Target == nil || text.Target.Name != ".text" {
+		t.Errorf("target of .rela.text should be .text, have %#v", text.Target)
+	}
+	if text.SymbolTable == nil || text.SymbolTable.Name != ".symtab" {
+		t.Errorf("symbol table of .rela.text should be .symtab, have %#v", text.SymbolTable)
+	}
+
+	want := []elf.Relocation{
+		{Offset: 0x22, Symbol: 3, Type: elf.RelocationType{Machine: elf.EM_X86_64, Value: uint32(elf.R_X86_64_PC32)}, Addend: -4},
+		{Offset: 0x2e, Symbol: 7, Type: elf.RelocationType{Machine: elf.EM_X86_64, Value: uint32(elf.R_X86_64_PC32)}, Addend: -4},
+		{Offset: 0x1c, Symbol: 9, Type: elf.RelocationType{Machine: elf.EM_X86_64, Value: uint32(elf.R_X86_64_PLT32)}, Addend: -4},
+		{Offset: 0x33, Symbol: 11, Type: elf.RelocationType{Machine: elf.EM_X86_64, Value: uint32(elf.R_X86_64_PLT32)}, Addend: -4},
+	}
+	if len(text.Relocations) != len(want) {
+		t.Fatalf("have %d relocations, want %d", len(text.Relocations), len(want))
+	}
+	for i, w := range want {
+		if !reflect.DeepEqual(*text.Relocations[i], w) {
+			t.Errorf("relocation %d:\n\thave %#v\n\twant %#v\n", i, *text.Relocations[i], w)
+		}
+	}
+
+	if s := text.Relocations[2].Type.String(); s != "R_X86_64_PLT32" {
+		t.Errorf("have %q, want %q", s, "R_X86_64_PLT32")
+	}
+}
+
+func TestDynamicRelocations(t *testing.T) {
+	b, err := os.ReadFile(libsample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := elf.New(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rels, err := e.DynamicRelocations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rels) != 10 {
+		t.Fatalf("have %d relocations, want %d", len(rels), 10)
+	}
+
+	wants := map[int]struct {
+		offset uint64
+		symbol uint32
+		typ    string
+		addend int64
+	}{
+		0: {0x3d90, 0, "R_X86_64_RELATIVE", 0x1120},
+		4: {0x3fe0, 1, "R_X86_64_GLOB_DAT", 0},
+		9: {0x3fd8, 5, "R_X86_64_JMP_SLOT", 0},
+	}
+	for i, w := range wants {
+		r := rels[i]
+		if r.Offset != w.offset || r.Symbol != w.symbol || r.Type.String() != w.typ || r.Addend != w.addend {
+			t.Errorf("relocation %d: have %#x %d %s %d, want %#x %d %s %d", i,
+				r.Offset, r.Symbol, r.Type, r.Addend, w.offset, w.symbol, w.typ, w.addend)
+		}
+	}
+}
+
+func TestRelocationsRelr(t *testing.T) {
+	le := binary.LittleEndian
+	relr := make([]byte, 16)
+	le.PutUint64(relr[0:], 0x10000) // address entry
+	le.PutUint64(relr[8:], 0x7)     // bitmap selecting the next two words
+
+	raw := synthELF(false, le, []synthSection{
+		{name: ".relr.dyn", typ: elf.SHT_RELR, flags: elf.SHF_ALLOC, entsize: 8, data: relr},
+	})
+
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rels, err := e.Relocations(e.SectionByName(".relr.dyn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var offsets []uint64
+	for _, r := range rels {
+		if r.Type.String() != "R_X86_64_RELATIVE" {
+			t.Errorf("have type %s, want R_X86_64_RELATIVE", r.Type)
+		}
+		offsets = append(offsets, r.Offset)
+	}
+	if want := []uint64{0x10000, 0x10008, 0x10010}; !reflect.DeepEqual(offsets, want) {
+		t.Errorf("have %#v, want %#v", offsets, want)
+	}
+}
+
+func TestRelocationTypeString(t *testing.T) {
+	cases := []struct {
+		typ  elf.RelocationType
+		want string
+	}{
+		{elf.RelocationType{Machine: elf.EM_X86_64, Value: 6}, "R_X86_64_GLOB_DAT"},
+		{elf.RelocationType{Machine: elf.EM_386, Value: 1}, "R_386_32"},
+		{elf.RelocationType{Machine: elf.EM_AARCH64, Value: 1025}, "R_AARCH64_GLOB_DAT"},
+		{elf.RelocationType{Machine: elf.EM_ARM, Value: 22}, "R_ARM_JUMP_SLOT"},
+		{elf.RelocationType{Machine: elf.EM_RISCV, Value: 3}, "R_RISCV_RELATIVE"},
+		{elf.RelocationType{Machine: elf.EM_PPC64, Value: 38}, "R_PPC64_ADDR64"},
+		{elf.RelocationType{Machine: elf.EM_MIPS, Value: 2}, "R_MIPS_32"},
+		{elf.RelocationType{Machine: elf.EM_X86_64, Value: 9999}, "9999"},
+	}
+
+	for _, tc := range cases {
+		if s := tc.typ.String(); s != tc.want {
+			t.Errorf("have %q, want %q", s, tc.want)
+		}
+	}
+}
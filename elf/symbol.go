@@ -0,0 +1,252 @@
+package elf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// Symbol represents an entry of a symbol table such as .symtab or .dynsym.
+type Symbol struct {
+	Name       string
+	Value      uint64
+	Size       uint64
+	Bind       SymbolBind
+	Type       SymbolType
+	Visibility SymbolVisibility
+	// Section is the index of the section the symbol is defined in, or one
+	// of the reserved SHN_* indices. A st_shndx of SHN_XINDEX is resolved
+	// through the SHT_SYMTAB_SHNDX table associated with the symbol table.
+	Section uint32
+	// Version is the symbol version of dynamic symbols, such as GLIBC_2.34,
+	// and Library the needed library an undefined symbol requires it from.
+	// VersionHidden is set for definitions that are only bound to when
+	// their version is asked for, written sym@VERSION rather than
+	// sym@@VERSION.
+	Version       string
+	Library       string
+	VersionHidden bool
+}
+
+type symbol32 struct {
+	Name  uint32
+	Value uint32
+	Size  uint32
+	Info  uint8
+	Other uint8
+	Shndx uint16
+}
+
+type symbol64 struct {
+	Name  uint32
+	Info  uint8
+	Other uint8
+	Shndx uint16
+	Value uint64
+	Size  uint64
+}
+
+var (
+	sizeSymbol32 = uint64(binary.Size(symbol32{}))
+	sizeSymbol64 = uint64(binary.Size(symbol64{}))
+)
+
+// Symbols returns the entries of the .symtab section. The returned slice is
+// indexed by symbol table index, so the first element is always the
+// reserved null symbol. It returns nil if the file has no SHT_SYMTAB section.
+func (e *File) Symbols() ([]*Symbol, error) {
+	return e.symbolsByType(SHT_SYMTAB)
+}
+
+// DynamicSymbols returns the entries of the .dynsym section, indexed the same
+// way as Symbols, with their versions from the .gnu.version sections. Files
+// without section headers fall back to the table DT_SYMTAB points to, whose
+// size is taken from the hash tables. It returns nil if the file has no
+// dynamic symbols. When the version sections are malformed, the symbols are
+// returned along with the error, left unversioned as far as the versions
+// could not be read.
+func (e *File) DynamicSymbols() ([]*Symbol, error) {
+	syms, err := e.dynamicSymbols()
+	if err != nil || syms == nil {
+		return nil, err
+	}
+	if err := e.setVersions(syms); err != nil {
+		return syms, fmt.Errorf("invalid symbol versions: %w", err)
+	}
+
+	return syms, nil
+}
+
+func (e *File) dynamicSymbols() ([]*Symbol, error) {
+	if len(e.SectionsByType(SHT_DYNSYM)) > 0 {
+		return e.symbolsByType(SHT_DYNSYM)
+	}
+
+	des, err := e.DynamicEntries()
+	if err != nil {
+		return nil, err
+	}
+	addr, ok := findDynValue(des, DT_SYMTAB)
+	if !ok {
+		return nil, nil
+	}
+
+	var n uint64
+	if h, err := e.HashTable(); err != nil {
+		return nil, err
+	} else if h != nil {
+		n = uint64(len(h.Chains))
+	} else if gh, err := e.GNUHashTable(); err != nil {
+		return nil, err
+	} else if gh != nil {
+		n = gh.symbolCount()
+	} else {
+		return nil, fmt.Errorf("no hash table to size the dynamic symbol table")
+	}
+
+	entSize := sizeSymbol64
+	if e.is32() {
+		entSize = sizeSymbol32
+	}
+	if v, ok := findDynValue(des, DT_SYMENT); ok && v >= entSize {
+		entSize = v
+	}
+	if n > e.size/entSize {
+		return nil, fmt.Errorf("dynamic symbol table with %d entries exceeds the file", n)
+	}
+	raw, err := e.ReadAddr(addr, n*entSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dynamic symbol table: %w", err)
+	}
+	strs, err := e.dynamicStringTable(des)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.decodeSymbols(raw, strs, nil, entSize)
+}
+
+func (e *File) symbolsByType(sht SectionHeaderType) ([]*Symbol, error) {
+	ss := e.SectionsByType(sht)
+	if len(ss) == 0 {
+		return nil, nil
+	}
+
+	return e.parseSymbols(ss[0])
+}
+
+// parseSymbols decodes every entry of the symbol table section s, resolving
+// names through the string table referenced by its sh_link.
+func (e *File) parseSymbols(s *Section) ([]*Symbol, error) {
+	link := s.Header.Link
+	strtab := e.SectionAt(link)
+	if strtab == nil {
+		return nil, fmt.Errorf("invalid string table index %d for symbol table %s", link, s.Name)
+	}
+	if strtab.Header.Type != SHT_STRTAB {
+		return nil, fmt.Errorf("section %d linked from symbol table %s is not a string table", link, s.Name)
+	}
+
+	is32 := e.is32()
+	structSize := sizeSymbol64
+	if is32 {
+		structSize = sizeSymbol32
+	}
+	entSize := s.Header.EntSize
+	if entSize == 0 {
+		entSize = structSize
+	}
+	if entSize < structSize {
+		return nil, fmt.Errorf("invalid symbol entry size: %d", entSize)
+	}
+
+	raw, err := s.Data()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read symbol table %s: %w", s.Name, err)
+	}
+	strs, err := strtab.Data()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read string table %s: %w", strtab.Name, err)
+	}
+	shndx, err := e.extendedSectionIndices(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.decodeSymbols(raw, strs, shndx, entSize)
+}
+
+// decodeSymbols decodes the symbol table raw with entries entSize bytes
+// apart. Names are resolved in strs and SHN_XINDEX section indices in shndx.
+func (e *File) decodeSymbols(raw, strs, shndx []byte, entSize uint64) ([]*Symbol, error) {
+	is32 := e.is32()
+	structSize := sizeSymbol64
+	if is32 {
+		structSize = sizeSymbol32
+	}
+
+	n := uint64(len(raw)) / entSize
+	syms := make([]*Symbol, n)
+	for i := uint64(0); i < n; i++ {
+		r := bytes.NewReader(raw[i*entSize : i*entSize+structSize])
+
+		var nameOff uint32
+		var info, other uint8
+		var sectionIndex uint16
+		sym := &Symbol{}
+		if is32 {
+			var sym32 symbol32
+			if err := binary.Read(r, e.Endianness, &sym32); err != nil {
+				return nil, fmt.Errorf("failed to read symbol %d: %w", i, err)
+			}
+			nameOff, info, other, sectionIndex = sym32.Name, sym32.Info, sym32.Other, sym32.Shndx
+			sym.Value = uint64(sym32.Value)
+			sym.Size = uint64(sym32.Size)
+		} else {
+			var sym64 symbol64
+			if err := binary.Read(r, e.Endianness, &sym64); err != nil {
+				return nil, fmt.Errorf("failed to read symbol %d: %w", i, err)
+			}
+			nameOff, info, other, sectionIndex = sym64.Name, sym64.Info, sym64.Other, sym64.Shndx
+			sym.Value = sym64.Value
+			sym.Size = sym64.Size
+		}
+
+		sym.Section = uint32(sectionIndex)
+		if sectionIndex == SHN_XINDEX {
+			if (i+1)*4 > uint64(len(shndx)) {
+				return nil, fmt.Errorf("symbol %d has SHN_XINDEX but no extended section index", i)
+			}
+			sym.Section = e.Endianness.Uint32(shndx[i*4:])
+		}
+
+		name, err := stringAt(strs, nameOff)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name of symbol %d: %w", i, err)
+		}
+		sym.Name = name
+		sym.Bind = SymbolBind(info >> 4)
+		sym.Type = SymbolType(info & 0xf)
+		sym.Visibility = SymbolVisibility(other & 0x3)
+		syms[i] = sym
+	}
+
+	return syms, nil
+}
+
+// extendedSectionIndices returns the contents of the SHT_SYMTAB_SHNDX section
+// associated with the symbol table s, or nil if there is none.
+func (e *File) extendedSectionIndices(s *Section) ([]byte, error) {
+	for i, ss := range e.Sections {
+		if ss == s {
+			for _, x := range e.SectionsByType(SHT_SYMTAB_SHNDX) {
+				if x.Header.Link == uint32(i) {
+					return x.Data()
+				}
+			}
+			break
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,130 @@
+package elf_test
+
+import (
+	"encoding/binary"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+func TestSymbols(t *testing.T) {
+	b, err := os.ReadFile("../testdata/elf_linux_amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := elf.New(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	syms, err := e.Symbols()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(syms) != 2171 {
+		t.Fatalf("have %d symbols, want %d", len(syms), 2171)
+	}
+
+	want := map[int]elf.Symbol{
+		0:    {Name: "", Section: elf.SHN_UNDEF},
+		1:    {Name: "go.go", Type: elf.STT_FILE, Section: elf.SHN_ABS},
+		2:    {Name: "runtime.text", Value: 0x401000, Type: elf.STT_FUNC, Section: 1},
+		1561: {Name: "main.main", Value: 0x497760, Size: 138, Bind: elf.STB_GLOBAL, Type: elf.STT_FUNC, Section: 1},
+	}
+	for i, ws := range want {
+		if !reflect.DeepEqual(*syms[i], ws) {
+			t.Errorf("symbol %d:\n\thave %#v\n\twant %#v\n", i, *syms[i], ws)
+		}
+	}
+
+	dsyms, err := e.DynamicSymbols()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dsyms != nil {
+		t.Errorf("statically linked binary should have no dynamic symbols, have %d", len(dsyms))
+	}
+}
+
+func TestSymbols32BigEndian(t *testing.T) {
+	be := binary.BigEndian
+	strtab := []byte("\x00foo\x00")
+	symtab := make([]byte, 32)
+	be.PutUint32(symtab[16:], 1)      // st_name
+	be.PutUint32(symtab[20:], 0x1234) // st_value
+	be.PutUint32(symtab[24:], 8)      // st_size
+	symtab[28] = 0x11                 // STB_GLOBAL, STT_OBJECT
+	symtab[29] = 0x2                  // STV_HIDDEN
+	be.PutUint16(symtab[30:], 1)      // st_shndx
+
+	raw := synthELF(true, be, []synthSection{
+		{name: ".symtab", typ: elf.SHT_SYMTAB, link: 2, info: 1, entsize: 16, data: symtab},
+		{name: ".strtab", typ: elf.SHT_STRTAB, data: strtab},
+	})
+
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	syms, err := e.Symbols()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := elf.Symbol{Name: "foo", Value: 0x1234, Size: 8, Bind: elf.STB_GLOBAL, Type: elf.STT_OBJECT, Visibility: elf.STV_HIDDEN, Section: 1}
+	if len(syms) != 2 || !reflect.DeepEqual(*syms[1], want) {
+		t.Fatalf("have %#v, want %#v", syms, want)
+	}
+}
+
+func TestSymbolsMalformed(t *testing.T) {
+	le := binary.LittleEndian
+	symtab := make([]byte, 48)
+	le.PutUint32(symtab[24:], 100) // st_name beyond .strtab
+
+	raw := synthELF(false, le, []synthSection{
+		{name: ".symtab", typ: elf.SHT_SYMTAB, link: 2, entsize: 24, data: symtab},
+		{name: ".strtab", typ: elf.SHT_STRTAB, data: []byte("\x00")},
+	})
+
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := e.Symbols(); err == nil {
+		t.Fatal("expected error for symbol name outside of string table")
+	}
+}
+
+func TestSymbolsExtendedSectionIndex(t *testing.T) {
+	le := binary.LittleEndian
+	symtab := make([]byte, 48)
+	le.PutUint16(symtab[24+6:], 0xffff) // st_shndx = SHN_XINDEX
+	shndx := make([]byte, 8)
+	le.PutUint32(shndx[4:], 0x12345) // real section index of symbol 1
+
+	raw := synthELF(false, le, []synthSection{
+		{name: ".symtab", typ: elf.SHT_SYMTAB, link: 2, entsize: 24, data: symtab},
+		{name: ".strtab", typ: elf.SHT_STRTAB, data: []byte("\x00")},
+		{name: ".symtab_shndx", typ: elf.SHT_SYMTAB_SHNDX, link: 1, entsize: 4, data: shndx},
+	})
+
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	syms, err := e.Symbols()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if syms[1].Section != 0x12345 {
+		t.Errorf("have section %#x, want %#x", syms[1].Section, 0x12345)
+	}
+}
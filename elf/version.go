@@ -0,0 +1,288 @@
+package elf
+
+import (
+	"fmt"
+)
+
+// VersionDefinition is an entry of the .gnu.version_d section, a version the
+// file defines for its symbols. The entry flagged VER_FLG_BASE names the
+// file itself.
+type VersionDefinition struct {
+	Index uint16
+	Flags VersionFlag
+	Hash  uint32
+	Name  string
+	// Parents names the versions this one inherits from.
+	Parents []string
+}
+
+// VersionRequirement is an entry of the .gnu.version_r section, the
+// versions the file requires from one of its needed libraries.
+type VersionRequirement struct {
+	Library  string
+	Versions []*NeededVersion
+}
+
+// NeededVersion is a version required from a library.
+type NeededVersion struct {
+	Name  string
+	Hash  uint32
+	Flags VersionFlag
+	// Index is the version index the symbols bound to this version are
+	// given in .gnu.version.
+	Index uint16
+}
+
+const (
+	sizeVerdef  = 20
+	sizeVerdaux = 8
+	sizeVerneed = 16
+	sizeVernaux = 16
+)
+
+// VersionDefinitions decodes the SHT_GNU_verdef section, or the table
+// DT_VERDEF points to in files without one. It returns nil if the file
+// defines no versions.
+func (e *File) VersionDefinitions() ([]*VersionDefinition, error) {
+	data, strs, count, err := e.versionTable(SHT_GNU_verdef, DT_VERDEF, DT_VERDEFNUM)
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var defs []*VersionDefinition
+	off := uint64(0)
+	for i := uint64(0); i < count; i++ {
+		vd, err := safeSlice(data, off, sizeVerdef)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version definition %d: %w", i, err)
+		}
+		if v := e.Endianness.Uint16(vd); v != 1 {
+			return nil, fmt.Errorf("unsupported version definition revision: %d", v)
+		}
+		def := &VersionDefinition{
+			Flags: VersionFlag(e.Endianness.Uint16(vd[2:])),
+			Index: e.Endianness.Uint16(vd[4:]),
+			Hash:  e.Endianness.Uint32(vd[8:]),
+		}
+
+		aux := off + uint64(e.Endianness.Uint32(vd[12:]))
+		for j := uint16(0); j < e.Endianness.Uint16(vd[6:]); j++ {
+			vda, err := safeSlice(data, aux, sizeVerdaux)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auxiliary entry of version definition %d: %w", i, err)
+			}
+			name, err := stringAt(strs, e.Endianness.Uint32(vda))
+			if err != nil {
+				return nil, fmt.Errorf("invalid name of version definition %d: %w", i, err)
+			}
+			if j == 0 {
+				def.Name = name
+			} else {
+				def.Parents = append(def.Parents, name)
+			}
+			aux += uint64(e.Endianness.Uint32(vda[4:]))
+		}
+		defs = append(defs, def)
+
+		next := uint64(e.Endianness.Uint32(vd[16:]))
+		if next == 0 {
+			break
+		}
+		off += next
+	}
+
+	return defs, nil
+}
+
+// RequiredVersions decodes the SHT_GNU_verneed section, or the table
+// DT_VERNEED points to in files without one, listing the versions required
+// from each needed library. It returns nil if the file requires no versions.
+func (e *File) RequiredVersions() ([]*VersionRequirement, error) {
+	data, strs, count, err := e.versionTable(SHT_GNU_verneed, DT_VERNEED, DT_VERNEEDNUM)
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var reqs []*VersionRequirement
+	off := uint64(0)
+	for i := uint64(0); i < count; i++ {
+		vn, err := safeSlice(data, off, sizeVerneed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version requirement %d: %w", i, err)
+		}
+		if v := e.Endianness.Uint16(vn); v != 1 {
+			return nil, fmt.Errorf("unsupported version requirement revision: %d", v)
+		}
+		lib, err := stringAt(strs, e.Endianness.Uint32(vn[4:]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid library of version requirement %d: %w", i, err)
+		}
+		req := &VersionRequirement{Library: lib}
+
+		aux := off + uint64(e.Endianness.Uint32(vn[8:]))
+		for j := uint16(0); j < e.Endianness.Uint16(vn[2:]); j++ {
+			vna, err := safeSlice(data, aux, sizeVernaux)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auxiliary entry of version requirement %d: %w", i, err)
+			}
+			name, err := stringAt(strs, e.Endianness.Uint32(vna[8:]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid name of version requirement %d: %w", i, err)
+			}
+			req.Versions = append(req.Versions, &NeededVersion{
+				Name:  name,
+				Hash:  e.Endianness.Uint32(vna),
+				Flags: VersionFlag(e.Endianness.Uint16(vna[4:])),
+				Index: e.Endianness.Uint16(vna[6:]),
+			})
+			next := uint64(e.Endianness.Uint32(vna[12:]))
+			if next == 0 {
+				break
+			}
+			aux += next
+		}
+		reqs = append(reqs, req)
+
+		next := uint64(e.Endianness.Uint32(vn[12:]))
+		if next == 0 {
+			break
+		}
+		off += next
+	}
+
+	return reqs, nil
+}
+
+// versionTable returns the contents of the version section of type sht
+// along with the string table its names are in and its number of entries.
+// Files without one fall back to the dynamic entries tag and numTag, in
+// which case the contents run to the end of the segment holding them.
+func (e *File) versionTable(sht SectionHeaderType, tag, numTag DynTag) (data, strs []byte, count uint64, err error) {
+	if ss := e.SectionsByType(sht); len(ss) > 0 {
+		s := ss[0]
+		strtab := e.SectionAt(s.Header.Link)
+		if strtab == nil || strtab.Header.Type != SHT_STRTAB {
+			return nil, nil, 0, fmt.Errorf("invalid string table index %d for version section %s", s.Header.Link, s.Name)
+		}
+		if data, err = s.Data(); err != nil {
+			return nil, nil, 0, fmt.Errorf("failed to read version section %s: %w", s.Name, err)
+		}
+		if strs, err = strtab.Data(); err != nil {
+			return nil, nil, 0, fmt.Errorf("failed to read string table %s: %w", strtab.Name, err)
+		}
+		return data, strs, uint64(s.Header.Info), nil
+	}
+
+	des, err := e.DynamicEntries()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	addr, ok := findDynValue(des, tag)
+	if !ok {
+		return nil, nil, 0, nil
+	}
+	count, _ = findDynValue(des, numTag)
+	if data, err = e.segmentData(addr); err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to read version table: %w", err)
+	}
+	if strs, err = e.dynamicStringTable(des); err != nil {
+		return nil, nil, 0, err
+	}
+
+	return data, strs, count, nil
+}
+
+// segmentData returns the file contents of the PT_LOAD segment holding the
+// virtual address vaddr, from vaddr on.
+func (e *File) segmentData(vaddr uint64) ([]byte, error) {
+	off, err := e.OffsetForAddr(vaddr)
+	if err != nil {
+		return nil, err
+	}
+	h := e.SegmentForAddr(vaddr).Header
+
+	return e.readAt(off, h.Offset+h.Filesz-off)
+}
+
+// versionIndices decodes up to n entries of the SHT_GNU_versym section, or
+// of the table DT_VERSYM points to in files without one, fewer if the table
+// is shorter. It returns nil if the file has neither.
+func (e *File) versionIndices(n uint64) ([]uint16, error) {
+	var raw []byte
+	if ss := e.SectionsByType(SHT_GNU_versym); len(ss) > 0 {
+		var err error
+		if raw, err = ss[0].Data(); err != nil {
+			return nil, fmt.Errorf("failed to read version section %s: %w", ss[0].Name, err)
+		}
+	} else {
+		des, err := e.DynamicEntries()
+		if err != nil {
+			return nil, err
+		}
+		addr, ok := findDynValue(des, DT_VERSYM)
+		if !ok {
+			return nil, nil
+		}
+		if raw, err = e.segmentData(addr); err != nil {
+			return nil, fmt.Errorf("failed to read version table: %w", err)
+		}
+	}
+
+	idx := make([]uint16, min(n, uint64(len(raw))/2))
+	for i := range idx {
+		idx[i] = e.Endianness.Uint16(raw[i*2:])
+	}
+
+	return idx, nil
+}
+
+// setVersions sets the versions of the dynamic symbols syms from the
+// version sections. Symbols with the reserved indices VER_NDX_LOCAL and
+// VER_NDX_GLOBAL are left unversioned, as are those with an index that no
+// version is defined or required for. Malformed version sections leave all
+// the symbols unversioned, and a version table shorter than syms the
+// symbols past its end; both are returned as errors.
+func (e *File) setVersions(syms []*Symbol) error {
+	idx, err := e.versionIndices(uint64(len(syms)))
+	if err != nil || idx == nil {
+		return err
+	}
+
+	defs, err := e.VersionDefinitions()
+	if err != nil {
+		return err
+	}
+	reqs, err := e.RequiredVersions()
+	if err != nil {
+		return err
+	}
+
+	type version struct{ name, library string }
+	versions := map[uint16]version{}
+	for _, d := range defs {
+		versions[d.Index] = version{name: d.Name}
+	}
+	for _, r := range reqs {
+		for _, v := range r.Versions {
+			versions[v.Index&^VERSYM_HIDDEN] = version{name: v.Name, library: r.Library}
+		}
+	}
+
+	for i, sym := range syms[:len(idx)] {
+		ndx := idx[i] &^ VERSYM_HIDDEN
+		if ndx == VER_NDX_LOCAL || ndx == VER_NDX_GLOBAL {
+			continue
+		}
+		v, ok := versions[ndx]
+		if !ok {
+			continue
+		}
+		sym.Version, sym.Library = v.name, v.library
+		sym.VersionHidden = idx[i]&VERSYM_HIDDEN != 0
+	}
+	if len(idx) < len(syms) {
+		return fmt.Errorf("version table has %d entries for %d symbols", len(idx), len(syms))
+	}
+
+	return nil
+}
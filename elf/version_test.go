@@ -0,0 +1,211 @@
+package elf_test
+
+import (
+	stdelf "debug/elf"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+const libversioned = "../testdata/libversioned_linux_amd64.so"
+
+func TestVersionDefinitions(t *testing.T) {
+	e, err := elf.New(mustReadFile(t, libversioned))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defs, err := e.VersionDefinitions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*elf.VersionDefinition{
+		{Index: 1, Flags: elf.VER_FLG_BASE, Hash: elf.SysVHash("libversioned.so.1"), Name: "libversioned.so.1"},
+		{Index: 2, Hash: elf.SysVHash("LIBV_1.0"), Name: "LIBV_1.0"},
+		{Index: 3, Hash: elf.SysVHash("LIBV_2.0"), Name: "LIBV_2.0", Parents: []string{"LIBV_1.0"}},
+	}
+	if !reflect.DeepEqual(defs, want) {
+		t.Errorf("VersionDefinitions:\n\thave %#v\n\twant %#v\n", defs, want)
+	}
+}
+
+func TestRequiredVersions(t *testing.T) {
+	e, err := elf.New(mustReadFile(t, "../testdata/hello_linux_amd64"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reqs, err := e.RequiredVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []*elf.NeededVersion
+	for i, name := range []string{"GLIBC_2.2.5", "GLIBC_2.3.4", "GLIBC_2.4", "GLIBC_2.34"} {
+		versions = append(versions, &elf.NeededVersion{Name: name, Hash: elf.SysVHash(name), Index: uint16(5 - i)})
+	}
+	want := []*elf.VersionRequirement{{Library: "libc.so.6", Versions: versions}}
+	if !reflect.DeepEqual(reqs, want) {
+		t.Errorf("RequiredVersions:\n\thave %#v\n\twant %#v\n", reqs, want)
+	}
+
+	if e, err = elf.New(mustReadFile(t, "../testdata/elf_linux_amd64")); err != nil {
+		t.Fatal(err)
+	}
+	if reqs, err := e.RequiredVersions(); reqs != nil || err != nil {
+		t.Errorf("a static executable has requirements %#v (%v)", reqs, err)
+	}
+}
+
+func TestSymbolVersions(t *testing.T) {
+	for _, name := range []string{libversioned, libsample, "../testdata/hello_linux_amd64"} {
+		std, err := stdelf.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer std.Close()
+		stdSyms, err := std.DynamicSymbols()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The versions are found through the dynamic section without
+		// section headers.
+		b := mustReadFile(t, name)
+		stripped := append([]byte(nil), b...)
+		le := binary.LittleEndian
+		le.PutUint64(stripped[40:], 0) // e_shoff
+		le.PutUint16(stripped[60:], 0) // e_shnum
+		le.PutUint16(stripped[62:], 0) // e_shstrndx
+
+		for _, raw := range [][]byte{b, stripped} {
+			e, err := elf.New(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			syms, err := e.DynamicSymbols()
+			if err != nil {
+				t.Fatalf("%s: %s", name, err)
+			}
+
+			// debug/elf leaves out the null symbol.
+			if len(syms) != len(stdSyms)+1 {
+				t.Fatalf("%s: have %d symbols, want %d", name, len(syms), len(stdSyms)+1)
+			}
+			for i, ss := range stdSyms {
+				sym := syms[i+1]
+				hidden := ss.HasVersion && ss.VersionIndex.IsHidden()
+				if sym.Version != ss.Version || sym.Library != ss.Library || sym.VersionHidden != hidden {
+					t.Errorf("%s: %s: have %q %q %v, want %q %q %v", name, sym.Name,
+						sym.Version, sym.Library, sym.VersionHidden, ss.Version, ss.Library, hidden)
+				}
+			}
+		}
+	}
+}
+
+func TestSymbolVersionsHidden(t *testing.T) {
+	e, err := elf.New(mustReadFile(t, libversioned))
+	if err != nil {
+		t.Fatal(err)
+	}
+	syms, err := e.DynamicSymbols()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var have []string
+	for _, sym := range syms {
+		if sym.Name != "counter" {
+			continue
+		}
+		sep := "@@"
+		if sym.VersionHidden {
+			sep = "@"
+		}
+		have = append(have, sym.Name+sep+sym.Version)
+	}
+	if want := []string{"counter@@LIBV_2.0", "counter@LIBV_1.0"}; !reflect.DeepEqual(have, want) {
+		t.Errorf("have %q, want %q", have, want)
+	}
+
+	// Only the default version is bound to.
+	s, err := e.LookupDynamicSymbol("counter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s == nil || s.Version != "LIBV_2.0" {
+		t.Errorf("LookupDynamicSymbol: have %#v", s)
+	}
+}
+
+func TestVersionsMalformed(t *testing.T) {
+	b := mustReadFile(t, "../testdata/hello_linux_amd64")
+
+	// Point the library name of the .gnu.version_r entry at 0x640 past the
+	// end of the string table.
+	binary.LittleEndian.PutUint32(b[0x644:], 0xffffff)
+	e, err := elf.New(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.RequiredVersions(); err == nil {
+		t.Errorf("an invalid library name should fail")
+	}
+
+	// The symbols are still read, without versions, along with the error.
+	syms, err := e.DynamicSymbols()
+	if err == nil {
+		t.Errorf("DynamicSymbols should return the invalid library name")
+	}
+	if len(syms) == 0 {
+		t.Fatal("no symbols returned")
+	}
+	for _, sym := range syms {
+		if sym.Version != "" || sym.Library != "" {
+			t.Errorf("%s has version %s of %s", sym.Name, sym.Version, sym.Library)
+		}
+	}
+}
+
+func TestSymbolVersionsUnknown(t *testing.T) {
+	b := mustReadFile(t, "../testdata/hello_linux_amd64")
+
+	// Give the second symbol the version index 9, which is neither defined
+	// nor required.
+	binary.LittleEndian.PutUint16(b[0x62a+2:], 9)
+	e, err := elf.New(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syms, err := e.DynamicSymbols()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(syms) != 9 || syms[1].Version != "" || syms[3].Version != "GLIBC_2.4" {
+		t.Errorf("have %d symbols with versions %q and %q", len(syms), syms[1].Version, syms[3].Version)
+	}
+}
+
+func TestSymbolVersionsTruncated(t *testing.T) {
+	b := mustReadFile(t, "../testdata/hello_linux_amd64")
+
+	// Cut .gnu.version, section 10, to 8 entries for the 9 symbols.
+	binary.LittleEndian.PutUint64(b[0x3708+10*64+32:], 16)
+	e, err := elf.New(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The symbols the table covers keep their versions, and the cut is
+	// reported rather than passed off as an unversioned symbol.
+	syms, err := e.DynamicSymbols()
+	if err == nil || !strings.Contains(err.Error(), "version table has 8 entries for 9 symbols") {
+		t.Errorf("have error %v", err)
+	}
+	if len(syms) != 9 || syms[3].Version != "GLIBC_2.4" || syms[8].Version != "" {
+		t.Errorf("have %d symbols with versions %q and %q", len(syms), syms[3].Version, syms[8].Version)
+	}
+}
@@ -0,0 +1,803 @@
+package elf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// layoutBlock is a range of the output file that is placed as a unit. The
+// contents of a PT_LOAD segment form one block so that the sections inside
+// keep their offsets relative to the segment; every other section, the
+// section header table and a program header table outside of any segment
+// are blocks of their own.
+type layoutBlock struct {
+	// start and end delimit the block in the original file. For blocks
+	// without an original position start is math.MaxUint64.
+	start, end uint64
+	// align and congruence constrain the new offset to
+	// offset%align == congruence%align.
+	align, congruence uint64
+	fixed             bool
+
+	sections []int
+	segments []int
+	// isSHT and isPHT mark the blocks of the header tables; hasSHT and
+	// hasPHT mark a segment block containing one at its original offset.
+	isSHT, isPHT   bool
+	hasSHT, hasPHT bool
+
+	offset  uint64
+	content []byte
+}
+
+func (b *layoutBlock) size() uint64 {
+	return uint64(len(b.content))
+}
+
+func (b *layoutBlock) moved() bool {
+	return b.start != b.offset
+}
+
+// intact reports whether the block is where it was and ends where it
+// ended, so that the original bytes following it are still in place.
+func (b *layoutBlock) intact() bool {
+	return !b.moved() && b.offset+b.size() == b.end
+}
+
+// WriteTo serializes the file to w, implementing io.WriterTo.
+//
+// Header fields, section and segment headers are written as they are found
+// in the File, except for the values derived from the layout: e_phoff,
+// e_shoff, the header counts (using extended numbering when needed),
+// sh_offset, sh_size of sections with contents, sh_name when the names no
+// longer match .shstrtab, and p_offset. The contents of a section are taken
+// from Raw, or from the file if Raw is nil, and written without being
+// decompressed, so assigning a new slice to Raw resizes or rewrites it.
+//
+// Sections keep their original offsets when possible. Sections added with a
+// zero Offset are appended after the existing contents. Sections inside a
+// PT_LOAD segment move only together with the segment, preserving
+// p_offset%p_align == p_vaddr%p_align, and cannot grow into the next section
+// of the segment because their addresses are fixed.
+//
+// Sections read from the file may be removed or reordered: the section
+// indices in their sh_link, in the sh_info of relocation sections and of
+// sections with SHF_INFO_LINK, in the st_shndx of symbols and in section
+// groups are renumbered, and referring to a removed section is an error.
+// The headers and contents of added sections are written as they are. An
+// unmodified file is written back byte-for-byte.
+//
+// Files with sections or segments marked Broken by lenient parsing, with
+// sections whose names lenient parsing could not read for want of a
+// section header string table, or whose section or segment headers place
+// contents past the end of the parsed file, are refused.
+func (e *File) WriteTo(w io.Writer) (int64, error) {
+	b, err := e.serialize()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+func (e *File) serialize() ([]byte, error) {
+	if err := e.checkExtents(); err != nil {
+		return nil, err
+	}
+
+	is32 := e.is32()
+	ehsize, shentsize, phentsize, wordSize := uint64(64), uint64(64), uint64(56), uint64(8)
+	if is32 {
+		ehsize, shentsize, phentsize, wordSize = 52, 40, 32, 4
+	}
+
+	hdr := *e.Header
+	shs := make([]SectionHeader, len(e.Sections))
+	origSizes := make([]uint64, len(e.Sections))
+	names := make([]string, len(e.Sections))
+	datas := make([][]byte, len(e.Sections))
+	for i, s := range e.Sections {
+		shs[i] = s.Header
+		origSizes[i] = s.Header.Size
+		names[i] = s.Name
+		if hasFileContents(s.Header.Type) {
+			d, err := s.stored()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read section %d (%s): %w", i, s.Name, err)
+			}
+			datas[i] = d
+		}
+	}
+
+	if err := e.renumberSections(shs, datas); err != nil {
+		return nil, err
+	}
+
+	shstrndx := -1
+	for i, s := range e.Sections {
+		if s == e.shstrtab {
+			shstrndx = i
+		}
+	}
+	if shstrndx < 0 && len(shs) > 0 {
+		shstrndx = len(shs)
+		shs = append(shs, SectionHeader{Type: SHT_STRTAB, Addralign: 1})
+		origSizes = append(origSizes, 0)
+		names = append(names, ".shstrtab")
+		datas = append(datas, nil)
+	}
+	if shstrndx >= 0 {
+		layoutSectionNames(shs, names, datas, shstrndx)
+	}
+
+	for i := range shs {
+		if hasFileContents(shs[i].Type) {
+			shs[i].Size = uint64(len(datas[i]))
+		}
+	}
+
+	phs := make([]ProgramHeader, len(e.Segments))
+	for i, sg := range e.Segments {
+		phs[i] = sg.Header
+	}
+
+	shnum := uint64(len(shs))
+	phnum := uint64(len(phs))
+	if phnum >= PN_XNUM && shnum == 0 {
+		return nil, fmt.Errorf("extended program header number requires section headers")
+	}
+
+	blocks, err := e.layoutBlocks(shs, origSizes, datas, phs, ehsize, wordSize, shnum*shentsize, phnum*phentsize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Place the blocks in their original order, keeping each at its
+	// original offset when nothing before it has grown into that space.
+	sort.SliceStable(blocks, func(i, j int) bool { return blocks[i].start < blocks[j].start })
+	var cursor uint64
+	for _, b := range blocks {
+		off := b.start
+		if b.fixed {
+			off = 0
+		} else if off == math.MaxUint64 || off < cursor || off%b.align != b.congruence%b.align {
+			off = alignCongruent(cursor, b.align, b.congruence)
+		}
+		b.offset = off
+		cursor = off + b.size()
+	}
+
+	// Apply the new layout to the headers.
+	for _, b := range blocks {
+		delta := b.offset - b.start
+		for _, i := range b.sections {
+			shs[i].Offset = e.sectionOffset(i, b.start) + delta
+		}
+		for _, i := range b.segments {
+			phs[i].Offset += delta
+		}
+		switch {
+		case b.isSHT:
+			hdr.Shoff = b.offset
+		case b.hasSHT:
+			hdr.Shoff = e.Header.Shoff + delta
+		}
+		switch {
+		case b.isPHT:
+			hdr.Phoff = b.offset
+		case b.hasPHT:
+			hdr.Phoff = e.Header.Phoff + delta
+		}
+	}
+	for i := range shs {
+		if shs[i].Type != SHT_NOBITS || i >= len(e.Sections) {
+			continue
+		}
+		for _, b := range blocks {
+			if !b.isSHT && !b.isPHT && b.start != math.MaxUint64 && shs[i].Offset >= b.start && shs[i].Offset <= b.end {
+				shs[i].Offset += b.offset - b.start
+				break
+			}
+		}
+	}
+	for i := range phs {
+		if phs[i].Type == PT_PHDR {
+			phs[i].Offset = hdr.Phoff
+		}
+	}
+
+	setHeaderCounts(&hdr, shs, uint64(shstrndx), phnum, is32)
+	if is32 {
+		if err := checkClass32(&hdr, shs, phs); err != nil {
+			return nil, err
+		}
+	}
+
+	var shtBuf, phtBuf, hdrBuf bytes.Buffer
+	if err := writeStruct(&hdrBuf, e.Endianness, is32, &hdr); err != nil {
+		return nil, err
+	}
+	for i := range shs {
+		if err := writeStruct(&shtBuf, e.Endianness, is32, &shs[i]); err != nil {
+			return nil, err
+		}
+	}
+	for i := range phs {
+		if err := writeStruct(&phtBuf, e.Endianness, is32, &phs[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	// Assemble the output, filling the gaps between blocks that have not
+	// moved with the original bytes.
+	if cursor > math.MaxInt {
+		return nil, fmt.Errorf("file size %#x out of range", cursor)
+	}
+	out := make([]byte, 0, cursor)
+	var prev *layoutBlock
+	for _, b := range blocks {
+		if b.offset < uint64(len(out)) {
+			return nil, fmt.Errorf("contents at offset %#x overlap the preceding ones ending at %#x", b.offset, len(out))
+		}
+		if gap := b.offset - uint64(len(out)); gap > 0 {
+			if prev != nil && prev.intact() && !b.moved() && e.hasImage() {
+				g, err := e.readAt(uint64(len(out)), gap)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, g...)
+			} else {
+				out = append(out, make([]byte, gap)...)
+			}
+		}
+
+		out = append(out, b.content...)
+		prev = b
+	}
+	if prev != nil && prev.intact() && e.hasImage() && e.size > uint64(len(out)) {
+		tail, err := e.readAt(uint64(len(out)), e.size-uint64(len(out)))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, tail...)
+	}
+
+	copy(out, hdrBuf.Bytes())
+	if phnum > 0 {
+		copy(out[hdr.Phoff:], phtBuf.Bytes())
+	}
+	if shnum > 0 {
+		copy(out[hdr.Shoff:], shtBuf.Bytes())
+	}
+
+	return out, nil
+}
+
+// checkExtents checks that the sections and segments placed at their
+// original offsets can be copied from the parsed file, so that the layout
+// is not built from headers that lenient parsing kept as they were read,
+// and that the section names were read, so that they are not replaced by
+// empty ones.
+func (e *File) checkExtents() error {
+	if e.unnamed {
+		return fmt.Errorf("section names are missing: invalid section header string table index")
+	}
+	for i, s := range e.Sections {
+		h := s.Header
+		if s.Broken {
+			return fmt.Errorf("section %d (%s) is broken", i, s.Name)
+		}
+		if e.hasImage() && hasFileContents(h.Type) && h.Offset != 0 && (h.Offset > e.size || h.Size > e.size-h.Offset) {
+			return fmt.Errorf("section %d (%s) at offset %#x with size %#x extends past the end of the file", i, s.Name, h.Offset, h.Size)
+		}
+	}
+	for i, sg := range e.Segments {
+		h := sg.Header
+		if sg.Broken {
+			return fmt.Errorf("segment %d is broken", i)
+		}
+		if e.hasImage() && h.Filesz > 0 && (h.Offset > e.size || h.Filesz > e.size-h.Offset) {
+			return fmt.Errorf("segment %d at offset %#x with size %#x extends past the end of the file", i, h.Offset, h.Filesz)
+		}
+	}
+
+	return nil
+}
+
+// renumberSections rewrites the section indices in the headers shs and
+// contents datas of the sections read from the file for their positions in
+// e.Sections. Section 0, whose fields hold the extended header counts, is
+// left to setHeaderCounts.
+func (e *File) renumberSections(shs []SectionHeader, datas [][]byte) error {
+	newIndex := make(map[*Section]uint32, len(e.Sections))
+	for i, s := range e.Sections {
+		newIndex[s] = uint32(i)
+	}
+	original := make(map[*Section]bool, len(e.sections))
+	moved := len(e.Sections) != len(e.sections)
+	for i, s := range e.sections {
+		original[s] = true
+		if n, ok := newIndex[s]; !ok || n != uint32(i) {
+			moved = true
+		}
+	}
+	if !moved {
+		return nil
+	}
+
+	// renumber maps the original index n, leaving the values outside of
+	// the original table as they are.
+	renumber := func(n uint32) (uint32, bool) {
+		if n == 0 || uint64(n) >= uint64(len(e.sections)) {
+			return n, true
+		}
+		m, ok := newIndex[e.sections[n]]
+		return m, ok
+	}
+
+	for i, s := range e.Sections {
+		if i == 0 || !original[s] {
+			continue
+		}
+		sh := &shs[i]
+		var ok bool
+		if sh.Link, ok = renumber(sh.Link); !ok {
+			return fmt.Errorf("section %d (%s) links to removed section %d", i, s.Name, s.Header.Link)
+		}
+		if sh.Flags&SHF_INFO_LINK != 0 || sh.Type == SHT_REL || sh.Type == SHT_RELA {
+			if sh.Info, ok = renumber(sh.Info); !ok {
+				return fmt.Errorf("section %d (%s) applies to removed section %d", i, s.Name, s.Header.Info)
+			}
+		}
+
+		d := datas[i]
+		switch sh.Type {
+		case SHT_SYMTAB, SHT_DYNSYM:
+			// st_shndx follows st_name, st_info and st_other in 64-bit
+			// symbols, and st_value and st_size too in 32-bit ones.
+			d = append([]byte(nil), d...)
+			stride, field := max(sh.EntSize, sizeSymbol64), uint64(6)
+			if e.is32() {
+				stride, field = max(sh.EntSize, sizeSymbol32), 14
+			}
+			for off := uint64(0); off+stride <= uint64(len(d)); off += stride {
+				n := uint32(e.Endianness.Uint16(d[off+field:]))
+				if n >= SHN_LORESERVE {
+					continue
+				}
+				m, ok := renumber(n)
+				if !ok {
+					return fmt.Errorf("symbol %d of section %d (%s) is defined in removed section %d", off/stride, i, s.Name, n)
+				}
+				if m >= SHN_LORESERVE {
+					return fmt.Errorf("symbol %d of section %d (%s): section index %d requires SHT_SYMTAB_SHNDX", off/stride, i, s.Name, m)
+				}
+				e.Endianness.PutUint16(d[off+field:], uint16(m))
+			}
+		case SHT_SYMTAB_SHNDX, SHT_GROUP:
+			// Both hold 32-bit section indices, after the GRP_* flags in a
+			// section group.
+			d = append([]byte(nil), d...)
+			start := uint64(0)
+			if sh.Type == SHT_GROUP {
+				start = 4
+			}
+			for off := start; off+4 <= uint64(len(d)); off += 4 {
+				n := e.Endianness.Uint32(d[off:])
+				m, ok := renumber(n)
+				if !ok {
+					return fmt.Errorf("entry %d of section %d (%s) refers to removed section %d", off/4, i, s.Name, n)
+				}
+				e.Endianness.PutUint32(d[off:], m)
+			}
+		}
+		datas[i] = d
+	}
+
+	return nil
+}
+
+// layoutBlocks groups the contents of the file into blocks holding their
+// original positions, ready to be placed.
+func (e *File) layoutBlocks(shs []SectionHeader, origSizes []uint64, datas [][]byte, phs []ProgramHeader, ehsize, wordSize, shtSize, phtSize uint64) ([]*layoutBlock, error) {
+	var blocks []*layoutBlock
+
+	// File ranges of PT_LOAD segments, and of other segments with contents
+	// outside of them, merged where they overlap.
+	type span struct {
+		start, end        uint64
+		align, congruence uint64
+		segments          []int
+	}
+	var spans []*span
+	addSpan := func(i int) {
+		h := e.Segments[i].Header
+		align := h.Align
+		if align == 0 || align&(align-1) != 0 {
+			align = 1
+		}
+		spans = append(spans, &span{h.Offset, h.Offset + h.Filesz, align, h.Vaddr % align, nil})
+	}
+	for i, sg := range e.Segments {
+		if sg.Header.Type == PT_LOAD && sg.Header.Filesz > 0 {
+			addSpan(i)
+		}
+	}
+	for i, sg := range e.Segments {
+		h := sg.Header
+		if h.Type == PT_LOAD || h.Filesz == 0 {
+			continue
+		}
+		inside := false
+		for _, sp := range spans {
+			if h.Offset >= sp.start && h.Offset+h.Filesz <= sp.end {
+				inside = true
+			}
+		}
+		if !inside {
+			addSpan(i)
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	var merged []*span
+	for _, sp := range spans {
+		if n := len(merged); n > 0 && sp.start < merged[n-1].end {
+			last := merged[n-1]
+			last.end = max(last.end, sp.end)
+			last.align = max(last.align, sp.align)
+			continue
+		}
+		merged = append(merged, sp)
+	}
+
+	// Assign every segment to the span containing its offset so that it
+	// moves along.
+	for i, sg := range e.Segments {
+		h := sg.Header
+		for _, sp := range merged {
+			if h.Offset >= sp.start && h.Offset < sp.end || h.Filesz == 0 && h.Offset == sp.start {
+				sp.segments = append(sp.segments, i)
+				break
+			}
+		}
+	}
+
+	headerInSpan := len(merged) > 0 && merged[0].start == 0
+	if !headerInSpan {
+		blocks = append(blocks, &layoutBlock{start: 0, end: ehsize, align: 1, fixed: true, offset: 0, content: make([]byte, ehsize)})
+	}
+
+	var phtInSpan, shtInSpan bool
+	for _, sp := range merged {
+		b := &layoutBlock{start: sp.start, end: sp.end, align: sp.align, congruence: sp.congruence, segments: sp.segments, fixed: sp.start == 0}
+
+		if e.hasImage() {
+			img, err := e.readAt(sp.start, sp.end-sp.start)
+			if err != nil {
+				return nil, err
+			}
+			b.content = append([]byte(nil), img...)
+		} else {
+			b.content = make([]byte, sp.end-sp.start)
+			for _, i := range sp.segments {
+				d, err := e.Segments[i].Data()
+				if err != nil {
+					return nil, fmt.Errorf("failed to read segment %d: %w", i, err)
+				}
+				copy(b.content[e.Segments[i].Header.Offset-sp.start:], d)
+			}
+		}
+
+		if len(phs) > 0 && e.Header.Phoff >= sp.start && e.Header.Phoff < sp.end {
+			if phtSize > e.phdrSize || e.Header.Phoff+phtSize > sp.end {
+				return nil, fmt.Errorf("program header table cannot grow inside a segment")
+			}
+			b.hasPHT, phtInSpan = true, true
+		}
+		// Section headers are not loaded, so a table that no longer fits
+		// in its segment is moved to the end of the file instead.
+		if shtSize > 0 && e.Header.Shoff >= sp.start && e.Header.Shoff < sp.end {
+			if shtSize <= e.shdrSize && e.Header.Shoff+shtSize <= sp.end {
+				b.hasSHT = true
+			}
+			shtInSpan = true
+		}
+
+		blocks = append(blocks, b)
+	}
+
+	// Sections inside a span keep their position within it; the others
+	// become blocks of their own.
+	for i := range shs {
+		if !hasFileContents(shs[i].Type) {
+			continue
+		}
+
+		var owner *layoutBlock
+		if i < len(e.Sections) && e.Sections[i].Header.Offset != 0 {
+			off := e.Sections[i].Header.Offset
+			for _, b := range blocks {
+				if b.fixed && !headerInSpan && b.start == 0 {
+					continue
+				}
+				if off >= b.start && off < b.end || origSizes[i] == 0 && off == b.end && off != 0 {
+					owner = b
+					break
+				}
+			}
+		}
+
+		if owner == nil {
+			start, end := uint64(math.MaxUint64), uint64(math.MaxUint64)
+			if i < len(e.Sections) && e.Sections[i].Header.Offset != 0 {
+				start, end = e.Sections[i].Header.Offset, e.Sections[i].Header.Offset+origSizes[i]
+			}
+			align := shs[i].Addralign
+			if align == 0 || align&(align-1) != 0 {
+				align = 1
+			}
+			blocks = append(blocks, &layoutBlock{start: start, end: end, align: align, sections: []int{i}, content: datas[i]})
+			continue
+		}
+
+		rel := e.Sections[i].Header.Offset - owner.start
+		newSize := uint64(len(datas[i]))
+		if rel+newSize > uint64(len(owner.content)) || newSize > origSizes[i] && e.nextSectionStart(i, owner) < e.Sections[i].Header.Offset+newSize {
+			return nil, fmt.Errorf("section %s cannot grow inside a segment", e.Sections[i].Name)
+		}
+		copy(owner.content[rel:], datas[i])
+		if newSize < origSizes[i] {
+			clear(owner.content[rel+newSize : min(rel+origSizes[i], uint64(len(owner.content)))])
+		}
+		owner.sections = append(owner.sections, i)
+	}
+
+	if len(phs) > 0 && !phtInSpan {
+		start, end := e.Header.Phoff, e.Header.Phoff+e.phdrSize
+		if start == 0 || e.phdrSize == 0 {
+			start, end = math.MaxUint64, math.MaxUint64
+		}
+		blocks = append(blocks, &layoutBlock{start: start, end: end, align: wordSize, isPHT: true, content: make([]byte, phtSize)})
+	}
+
+	if shtSize > 0 && !hasSHT(blocks) {
+		start, end := e.Header.Shoff, e.Header.Shoff+e.shdrSize
+		if start == 0 || shtInSpan {
+			start, end = math.MaxUint64, math.MaxUint64
+		}
+		blocks = append(blocks, &layoutBlock{start: start, end: end, align: wordSize, isSHT: true, content: make([]byte, shtSize)})
+	}
+
+	return blocks, nil
+}
+
+func hasSHT(blocks []*layoutBlock) bool {
+	for _, b := range blocks {
+		if b.hasSHT {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sectionOffset returns the original offset of section i, or base for
+// sections that did not exist in the parsed file.
+func (e *File) sectionOffset(i int, base uint64) uint64 {
+	if i < len(e.Sections) && e.Sections[i].Header.Offset != 0 {
+		return e.Sections[i].Header.Offset
+	}
+
+	return base
+}
+
+// nextSectionStart returns the original offset of the first section in the
+// block b that starts after section i, or the end of b.
+func (e *File) nextSectionStart(i int, b *layoutBlock) uint64 {
+	next := b.end
+	off := e.Sections[i].Header.Offset
+	for j, s := range e.Sections {
+		h := s.Header
+		if j != i && hasFileContents(h.Type) && h.Offset > off && h.Offset < next {
+			next = h.Offset
+		}
+	}
+
+	return next
+}
+
+// setHeaderCounts stores the number of section and program headers and the
+// index of .shstrtab in the ELF header, moving the values that do not fit
+// into section 0 as extended numbering requires.
+func setHeaderCounts(hdr *ELFHeader, shs []SectionHeader, shstrndx, phnum uint64, is32 bool) {
+	ehsize, shentsize, phentsize := uint16(64), uint16(64), uint16(56)
+	if is32 {
+		ehsize, shentsize, phentsize = 52, 40, 32
+	}
+	hdr.Ehsize = ehsize
+
+	shnum := uint64(len(shs))
+	if shnum == 0 {
+		hdr.Shoff = 0
+		hdr.Shnum = 0
+		hdr.Shstrndx = 0
+	} else {
+		hdr.Shentsize = shentsize
+		hdr.Shnum = uint16(shnum)
+		if shnum >= SHN_LORESERVE {
+			hdr.Shnum = 0
+			shs[0].Size = shnum
+		}
+		hdr.Shstrndx = uint16(shstrndx)
+		if shstrndx >= SHN_LORESERVE {
+			hdr.Shstrndx = SHN_XINDEX
+			shs[0].Link = uint32(shstrndx)
+		}
+	}
+
+	if phnum == 0 {
+		// Keep e_phoff and e_phentsize as they were, which is what most
+		// linkers leave behind for files without program headers.
+		return
+	}
+	hdr.Phentsize = phentsize
+	hdr.Phnum = uint16(phnum)
+	if phnum >= PN_XNUM {
+		hdr.Phnum = PN_XNUM
+		shs[0].Info = uint32(phnum)
+	}
+}
+
+// layoutSectionNames keeps the section header string table when every name
+// still resolves through it, and rebuilds it otherwise.
+func layoutSectionNames(shs []SectionHeader, names []string, datas [][]byte, shstrndx int) {
+	strtab := datas[shstrndx]
+	ok := strtab != nil
+	for i := range shs {
+		if !ok {
+			break
+		}
+		name, err := stringAt(strtab, shs[i].Name)
+		ok = err == nil && name == names[i]
+	}
+	if ok {
+		return
+	}
+
+	st := NewStringTable()
+	for i := range shs {
+		shs[i].Name = st.Add(names[i])
+	}
+	datas[shstrndx] = st.Bytes()
+}
+
+// hasImage reports whether the original bytes of the file are available.
+func (e *File) hasImage() bool {
+	return e.Raw != nil || e.reader != nil
+}
+
+func hasFileContents(t SectionHeaderType) bool {
+	return t != SHT_NULL && t != SHT_NOBITS
+}
+
+// alignCongruent returns the smallest offset >= off with
+// offset%align == congruence%align.
+func alignCongruent(off, align, congruence uint64) uint64 {
+	if align <= 1 {
+		return off
+	}
+
+	want := congruence % align
+	if cur := off % align; cur <= want {
+		return off + want - cur
+	} else {
+		return off + align - cur + want
+	}
+}
+
+// checkClass32 checks that the headers of a 32-bit file, as laid out, fit
+// in its 32-bit fields, which writeStruct would otherwise truncate.
+func checkClass32(hdr *ELFHeader, shs []SectionHeader, phs []ProgramHeader) error {
+	fits := func(vs ...uint64) bool {
+		for _, v := range vs {
+			if v > math.MaxUint32 {
+				return false
+			}
+		}
+		return true
+	}
+
+	shtEnd := hdr.Shoff + uint64(len(shs))*40
+	phtEnd := hdr.Phoff + uint64(len(phs))*32
+	if !fits(hdr.Entry, hdr.Phoff, hdr.Shoff, shtEnd, phtEnd) {
+		return fmt.Errorf("elf header does not fit in a 32-bit file: entry %#x, phoff %#x, shoff %#x", hdr.Entry, hdr.Phoff, hdr.Shoff)
+	}
+	for i, sh := range shs {
+		end := sh.Offset
+		if hasFileContents(sh.Type) {
+			end += sh.Size
+		}
+		if !fits(uint64(sh.Flags), sh.Addr, sh.Offset, sh.Size, end, sh.Addralign, sh.EntSize) {
+			return fmt.Errorf("section %d at offset %#x with size %#x does not fit in a 32-bit file", i, sh.Offset, sh.Size)
+		}
+	}
+	for i, ph := range phs {
+		if !fits(ph.Offset, ph.Offset+ph.Filesz, ph.Vaddr, ph.Paddr, ph.Memsz, ph.Align) {
+			return fmt.Errorf("segment %d at offset %#x with size %#x does not fit in a 32-bit file", i, ph.Offset, ph.Filesz)
+		}
+	}
+
+	return nil
+}
+
+// writeStruct encodes a header in the layout of the file class.
+func writeStruct(w io.Writer, order binary.ByteOrder, is32 bool, v any) error {
+	if is32 {
+		switch h := v.(type) {
+		case *ELFHeader:
+			v = convertToELFHeader32(h)
+		case *SectionHeader:
+			v = convertToSectionHeader32(h)
+		case *ProgramHeader:
+			v = convertToProgramHeader32(h)
+		}
+	}
+
+	if err := binary.Write(w, order, v); err != nil {
+		return fmt.Errorf("failed to write %T: %w", v, err)
+	}
+
+	return nil
+}
+
+func convertToELFHeader32(header *ELFHeader) *elfHeader32 {
+	return &elfHeader32{
+		Ident:     header.Ident,
+		Type:      uint16(header.Type),
+		Machine:   uint16(header.Machine),
+		Version:   header.Version,
+		Entry:     uint32(header.Entry),
+		Phoff:     uint32(header.Phoff),
+		Shoff:     uint32(header.Shoff),
+		Flags:     header.Flags,
+		Ehsize:    header.Ehsize,
+		Phentsize: header.Phentsize,
+		Phnum:     header.Phnum,
+		Shentsize: header.Shentsize,
+		Shnum:     header.Shnum,
+		Shstrndx:  header.Shstrndx,
+	}
+}
+
+func convertToSectionHeader32(header *SectionHeader) *sectionHeader32 {
+	return &sectionHeader32{
+		Name:      header.Name,
+		Type:      uint32(header.Type),
+		Flags:     uint32(header.Flags),
+		Addr:      uint32(header.Addr),
+		Offset:    uint32(header.Offset),
+		Size:      uint32(header.Size),
+		Link:      header.Link,
+		Info:      header.Info,
+		Addralign: uint32(header.Addralign),
+		Entsize:   uint32(header.EntSize),
+	}
+}
+
+func convertToProgramHeader32(header *ProgramHeader) *programHeader32 {
+	return &programHeader32{
+		Type:   uint32(header.Type),
+		Offset: uint32(header.Offset),
+		Vaddr:  uint32(header.Vaddr),
+		Paddr:  uint32(header.Paddr),
+		Filesz: uint32(header.Filesz),
+		Memsz:  uint32(header.Memsz),
+		Flags:  uint32(header.Flags),
+		Align:  uint32(header.Align),
+	}
+}
@@ -0,0 +1,366 @@
+package elf_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+func writeFile(t *testing.T, e *elf.File) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	n, err := e.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo returned %d, wrote %d bytes", n, buf.Len())
+	}
+
+	return buf.Bytes()
+}
+
+func TestWriteToRoundTrip(t *testing.T) {
+	files := []string{
+		"../testdata/elf_linux_amd64",
+		hello,
+		libsample,
+		"../testdata/libsample_linux_amd64.o",
+		libsampleDebugZstd,
+	}
+
+	for _, name := range files {
+		raw, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		e, err := elf.New(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out := writeFile(t, e); !bytes.Equal(out, raw) {
+			t.Errorf("%s: output differs from the input (%d bytes, want %d)", name, len(out), len(raw))
+		}
+
+		// Files read on demand have no Raw but must be written the same.
+		f, err := elf.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out := writeFile(t, f); !bytes.Equal(out, raw) {
+			t.Errorf("%s: output of Open differs from the input", name)
+		}
+		f.Close()
+	}
+
+	for _, bo := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		for _, is32 := range []bool{true, false} {
+			raw := synthELF(is32, bo, []synthSection{
+				{name: ".data", typ: elf.SHT_PROGBITS, align: 4, data: []byte{1, 2, 3, 4, 5, 6}},
+				{name: ".comment", typ: elf.SHT_PROGBITS, align: 1, data: []byte("writer\x00")},
+			})
+			e, err := elf.New(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out := writeFile(t, e); !bytes.Equal(out, raw) {
+				t.Errorf("synthetic %v/%v: output differs from the input", is32, bo)
+			}
+		}
+	}
+}
+
+func TestWriteToModified(t *testing.T) {
+	raw, err := os.ReadFile(hello)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comment := e.SectionByName(".comment")
+	comment.Raw = append(bytes.Repeat([]byte("goelftools\x00"), 100), comment.Raw...)
+
+	debuglink := &elf.Section{
+		Name:   ".gnu_debuglink",
+		Header: elf.SectionHeader{Type: elf.SHT_PROGBITS, Addralign: 4},
+		Raw:    []byte("hello.debug\x00\x01\x02\x03\x04"),
+	}
+	e.Sections = append(e.Sections, debuglink)
+
+	var symtab int
+	for i, s := range e.Sections {
+		if s.Name == ".symtab" {
+			symtab = i
+		}
+	}
+	e.Sections = append(e.Sections[:symtab], e.Sections[symtab+1:]...)
+
+	e.Header.Entry = 0x1234
+	e.SegmentsByType(elf.PT_GNU_STACK)[0].Header.Flags = elf.PF_R | elf.PF_W | elf.PF_X
+
+	out := writeFile(t, e)
+	f, err := elf.New(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Header.Entry != 0x1234 {
+		t.Errorf("have entry %#x, want 0x1234", f.Header.Entry)
+	}
+	if flags := f.SegmentsByType(elf.PT_GNU_STACK)[0].Header.Flags; flags != elf.PF_R|elf.PF_W|elf.PF_X {
+		t.Errorf("have PT_GNU_STACK flags %#x, want RWX", flags)
+	}
+	if f.SectionByName(".symtab") != nil {
+		t.Errorf(".symtab should have been removed")
+	}
+	if len(f.Sections) != len(e.Sections) {
+		t.Errorf("have %d sections, want %d", len(f.Sections), len(e.Sections))
+	}
+	for _, want := range []*elf.Section{comment, debuglink} {
+		s := f.SectionByName(want.Name)
+		if s == nil {
+			t.Errorf("%s not found", want.Name)
+			continue
+		}
+		if !bytes.Equal(s.Raw, want.Raw) || s.Header.Size != uint64(len(want.Raw)) {
+			t.Errorf("%s: contents were not written", want.Name)
+		}
+	}
+	if off := f.SectionByName(".gnu_debuglink").Header.Offset; off%4 != 0 {
+		t.Errorf("have .gnu_debuglink at %#x, want 4 byte alignment", off)
+	}
+
+	// The loadable contents must be untouched, except for the headers that
+	// the first segment maps.
+	for i, sg := range e.Segments {
+		if sg.Header.Type != elf.PT_LOAD || sg.Header.Offset == 0 {
+			continue
+		}
+		if !bytes.Equal(f.Segments[i].Raw, sg.Raw) {
+			t.Errorf("segment %d contents changed", i)
+		}
+	}
+	needed, err := f.Needed()
+	if err != nil || len(needed) != 1 || needed[0] != "libc.so.6" {
+		t.Errorf("have needed %v (%v), want [libc.so.6]", needed, err)
+	}
+}
+
+// removeSection removes the section called name from e.
+func removeSection(t *testing.T, e *elf.File, name string) {
+	t.Helper()
+
+	for i, s := range e.Sections {
+		if s.Name == name {
+			e.Sections = append(e.Sections[:i:i], e.Sections[i+1:]...)
+			return
+		}
+	}
+	t.Fatalf("%s not found", name)
+}
+
+// sectionRefs describes the sections that the sections and symbols of e
+// refer to by name.
+func sectionRefs(t *testing.T, e *elf.File) []string {
+	t.Helper()
+
+	name := func(n uint32) string {
+		if n == 0 || n >= elf.SHN_LORESERVE {
+			return ""
+		}
+		return e.Sections[n].Name
+	}
+	var refs []string
+	for _, s := range e.Sections {
+		info := ""
+		if s.Header.Flags&elf.SHF_INFO_LINK != 0 {
+			info = name(s.Header.Info)
+		}
+		refs = append(refs, s.Name+" -> "+name(s.Header.Link)+" "+info)
+	}
+	syms, err := e.Symbols()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sym := range syms {
+		refs = append(refs, sym.Name+" in "+name(sym.Section))
+	}
+
+	return refs
+}
+
+func TestWriteToRemoved(t *testing.T) {
+	for _, tt := range []struct {
+		file, section string
+	}{
+		{hello, ".comment"},
+		// The sections after .data hold symbols and relocations.
+		{"../testdata/libsample_linux_amd64.o", ".data"},
+	} {
+		e, err := elf.New(mustReadFile(t, tt.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want []string
+		for _, ref := range sectionRefs(t, e) {
+			if !strings.HasPrefix(ref, tt.section+" -> ") {
+				want = append(want, ref)
+			}
+		}
+		removeSection(t, e, tt.section)
+
+		f, err := elf.New(writeFile(t, e))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have := sectionRefs(t, f); !reflect.DeepEqual(have, want) {
+			t.Errorf("%s without %s:\n\thave %q\n\twant %q\n", tt.file, tt.section, have, want)
+		}
+	}
+
+	// Sections that are still referred to cannot be removed.
+	for _, tt := range []struct {
+		file, section string
+	}{
+		{hello, ".strtab"},
+		{hello, ".dynstr"},
+		{"../testdata/libsample_linux_amd64.o", ".bss"},
+		{"../testdata/libsample_linux_amd64.o", ".text.startup"},
+	} {
+		e, err := elf.New(mustReadFile(t, tt.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		removeSection(t, e, tt.section)
+		if _, err := e.WriteTo(io.Discard); err == nil {
+			t.Errorf("%s: removing %s should fail", tt.file, tt.section)
+		}
+	}
+}
+
+func TestWriteToRenamed(t *testing.T) {
+	raw := synthELF(true, binary.BigEndian, []synthSection{
+		{name: ".data", typ: elf.SHT_PROGBITS, align: 4, data: []byte{1, 2, 3, 4}},
+	})
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e.SectionByName(".data").Name = ".renamed_data"
+	f, err := elf.New(writeFile(t, e))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := f.SectionByName(".renamed_data")
+	if s == nil || !bytes.Equal(s.Raw, []byte{1, 2, 3, 4}) {
+		t.Errorf("renamed section not found in %v", f.Sections)
+	}
+	if f.SectionByName(".shstrtab") == nil {
+		t.Errorf(".shstrtab not found")
+	}
+}
+
+func TestWriteToGrowInSegment(t *testing.T) {
+	e, err := elf.New(mustReadFile(t, hello))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := e.SectionByName(".text")
+	text.Raw = append(text.Raw, make([]byte, 0x10000)...)
+	if _, err := e.WriteTo(new(bytes.Buffer)); err == nil {
+		t.Errorf("growing .text should fail")
+	}
+}
+
+func TestWriteToClass32Overflow(t *testing.T) {
+	if math.MaxInt == math.MaxInt32 {
+		t.Skip("needs a 4 GiB slice")
+	}
+	raw := synthELF(true, binary.LittleEndian, []synthSection{
+		{name: ".data", typ: elf.SHT_PROGBITS, align: 4, data: []byte{1, 2, 3, 4}},
+	})
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The grown .data ends past 4 GiB, which 32-bit offsets cannot reach.
+	// The slice is never written, so its pages are not touched.
+	size := uint64(math.MaxUint32)
+	e.SectionByName(".data").Raw = make([]byte, size)
+	if _, err := e.WriteTo(io.Discard); err == nil || !strings.Contains(err.Error(), "32-bit") {
+		t.Errorf("have %v, want an error for offsets past 4 GiB", err)
+	}
+}
+
+func TestWriteToBroken(t *testing.T) {
+	raw := mustReadFile(t, hello)
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := slices.Index(e.Sections, e.SectionByName(".fini_array"))
+
+	// A .fini_array placed far past the end of the file, as lenient
+	// parsing keeps it, must not be laid out there.
+	bad := append([]byte(nil), raw...)
+	binary.LittleEndian.PutUint64(bad[e.Header.Shoff+uint64(i)*64+24:], 0x9b0000000000)
+	if _, err := elf.New(bad); err == nil {
+		t.Fatal("expected error for a section past the end of the file")
+	}
+	lenient, err := elf.NewWithOptions(bad, elf.Options{Lenient: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lenient.Sections[i].Broken {
+		t.Fatalf("section %d not broken", i)
+	}
+	if _, err := lenient.WriteTo(io.Discard); err == nil {
+		t.Errorf("writing a broken section should fail")
+	}
+
+	// Headers moved past the end of the file are refused too.
+	e.Sections[i].Header.Offset = 0x9b0000000000
+	if _, err := e.WriteTo(io.Discard); err == nil {
+		t.Errorf("writing a section past the end of the file should fail")
+	}
+
+	// Without a section header string table no section has its name, and
+	// writing would replace them all with empty ones.
+	bad = mustReadFile(t, "../testdata/elf_linux_amd64")
+	binary.LittleEndian.PutUint16(bad[62:], 0xfff0) // e_shstrndx
+	if lenient, err = elf.NewWithOptions(bad, elf.Options{Lenient: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lenient.WriteTo(io.Discard); err == nil || !strings.Contains(err.Error(), "section names are missing") {
+		t.Errorf("writing unnamed sections: have %v", err)
+	}
+}
+
+func mustReadFile(t *testing.T, name string) []byte {
+	t.Helper()
+
+	raw, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return raw
+}
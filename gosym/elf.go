@@ -0,0 +1,184 @@
+package gosym
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+// moduledataWords is the number of leading words of runtime.moduledata
+// needed to reach its text, minpc and maxpc fields in every layout.
+const moduledataWords = 23
+
+// New locates and decodes the pclntab of the Go binary e. The table is read
+// from the .gopclntab section. In binaries without one, such as those whose
+// section headers were stripped, it is found through runtime.firstmoduledata,
+// which points at it, or failing that by scanning the read-only PT_LOAD
+// segments for its magic number.
+func New(e *elf.File) (*Table, error) {
+	m := &image{e: e, order: e.Endianness, ptrSize: 8}
+	if e.Header.Class() == elf.ELFCLASS32 {
+		m.ptrSize = 4
+	}
+
+	data, addr, err := m.findPclntab()
+	if err != nil {
+		return nil, err
+	}
+
+	// The moduledata records where the text starts, which the entries of
+	// Go 1.18 and later tables are relative to.
+	_, text, _ := m.findModuledata(func(md []byte) (uint64, bool) {
+		if m.word(md, 0) != addr {
+			return 0, false
+		}
+		return m.moduledataText(md, data, addr)
+	})
+
+	t, err := Parse(data, text)
+	if err != nil {
+		return nil, err
+	}
+	if t.Version >= Version118 && t.TextStart == 0 {
+		if s := e.SectionByName(".text"); s != nil {
+			return Parse(data, s.Header.Addr)
+		}
+	}
+
+	return t, nil
+}
+
+// image reads the memory image of a Go binary.
+type image struct {
+	e       *elf.File
+	order   binary.ByteOrder
+	ptrSize int
+}
+
+func (m *image) word(b []byte, i int) uint64 {
+	if m.ptrSize == 4 {
+		return uint64(m.order.Uint32(b[i*4:]))
+	}
+
+	return m.order.Uint64(b[i*8:])
+}
+
+// findPclntab returns the contents of the pclntab and its address. Without
+// a .gopclntab section the contents run to the end of the segment holding
+// the table.
+func (m *image) findPclntab() ([]byte, uint64, error) {
+	if s := m.e.SectionByName(".gopclntab"); s != nil && s.Header.Type != elf.SHT_NOBITS {
+		data, err := s.Data()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read .gopclntab: %w", err)
+		}
+		return data, s.Header.Addr, nil
+	}
+
+	md, _, ok := m.findModuledata(func(md []byte) (uint64, bool) {
+		addr := m.word(md, 0)
+		sg := m.e.SegmentForAddr(addr)
+		if sg == nil || sg.Header.Flags&elf.PF_W != 0 {
+			return 0, false
+		}
+		data, err := m.segmentFrom(addr)
+		if err != nil {
+			return 0, false
+		}
+		return m.moduledataText(md, data, addr)
+	})
+	if ok {
+		addr := m.word(md, 0)
+		data, err := m.segmentFrom(addr)
+		return data, addr, err
+	}
+
+	for _, sg := range m.e.SegmentsByType(elf.PT_LOAD) {
+		if sg.Header.Flags&(elf.PF_W|elf.PF_X) != 0 {
+			continue
+		}
+		data, err := sg.Data()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read segment: %w", err)
+		}
+		for off := 0; off+pcHeaderSize <= len(data); off += 4 {
+			if v, _ := detectVersion(data[off:]); v == VersionUnknown {
+				continue
+			}
+			if _, err := Parse(data[off:], 0); err == nil {
+				return data[off:], sg.Header.Vaddr + uint64(off), nil
+			}
+		}
+	}
+
+	return nil, 0, fmt.Errorf("no pclntab found")
+}
+
+// segmentFrom returns the file-backed contents of the segment containing
+// addr, starting at addr.
+func (m *image) segmentFrom(addr uint64) ([]byte, error) {
+	sg := m.e.SegmentForAddr(addr)
+	if sg == nil || addr-sg.Header.Vaddr >= sg.Header.Filesz {
+		return nil, fmt.Errorf("address %#x is not backed by the file", addr)
+	}
+
+	return m.e.ReadAddr(addr, sg.Header.Vaddr+sg.Header.Filesz-addr)
+}
+
+// findModuledata scans the writable PT_LOAD segments for the moduledata
+// recognized by match, which returns the start of the text it records.
+func (m *image) findModuledata(match func(md []byte) (uint64, bool)) ([]byte, uint64, bool) {
+	for _, sg := range m.e.SegmentsByType(elf.PT_LOAD) {
+		if sg.Header.Flags&elf.PF_W == 0 {
+			continue
+		}
+		data, err := sg.Data()
+		if err != nil {
+			continue
+		}
+		for off := 0; off+moduledataWords*m.ptrSize <= len(data); off += m.ptrSize {
+			if text, ok := match(data[off:]); ok {
+				return data[off:], text, true
+			}
+		}
+	}
+
+	return nil, 0, false
+}
+
+// moduledataText checks that md is a moduledata pointing at the pclntab
+// data at addr and returns the start of the text it records.
+func (m *image) moduledataText(md, data []byte, addr uint64) (uint64, bool) {
+	v, _ := detectVersion(data)
+	if v == VersionUnknown || int(data[7]) != m.ptrSize || uint64(len(data)) < pcHeaderSize+8*uint64(m.ptrSize) {
+		return 0, false
+	}
+	hdr := &Table{ByteOrder: m.order, PtrSize: m.ptrSize, data: data}
+
+	// Before Go 1.16 the moduledata starts with the pclntable slice, then
+	// with a pointer to the header followed by the funcnametab slice.
+	textWord := 22
+	switch v {
+	case Version12:
+		textWord = 12
+		if n := m.word(md, 1); n == 0 || n != m.word(md, 2) {
+			return 0, false
+		}
+	case Version116:
+		if m.word(md, 1) != addr+hdr.word(2) {
+			return 0, false
+		}
+	default:
+		if m.word(md, 1) != addr+hdr.word(3) {
+			return 0, false
+		}
+	}
+
+	minpc, maxpc, text := m.word(md, textWord-2), m.word(md, textWord-1), m.word(md, textWord)
+	if text == 0 || text > minpc || minpc >= maxpc {
+		return 0, false
+	}
+
+	return text, true
+}
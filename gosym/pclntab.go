@@ -0,0 +1,386 @@
+// Package gosym decodes the pclntab of Go binaries, the table the Go runtime
+// uses to map program counters to functions, source files and lines.
+package gosym
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// Version is the layout of a pclntab, named after the Go release that
+// introduced it.
+type Version int
+
+const (
+	VersionUnknown Version = iota
+	Version12              // Go 1.2 to 1.15
+	Version116             // Go 1.16 and 1.17
+	Version118             // Go 1.18 and 1.19
+	Version120             // Go 1.20 and later
+)
+
+var versionStrings = map[Version]string{
+	VersionUnknown: "unknown",
+	Version12:      "1.2",
+	Version116:     "1.16",
+	Version118:     "1.18",
+	Version120:     "1.20",
+}
+
+func (v Version) String() string {
+	if s, ok := versionStrings[v]; ok {
+		return s
+	}
+
+	return fmt.Sprintf("Version(%d)", int(v))
+}
+
+// Magic numbers at the start of each pclntab version. They read differently
+// in the other byte order, which gives away the endianness of the table.
+const (
+	go12Magic  = 0xfffffffb
+	go116Magic = 0xfffffffa
+	go118Magic = 0xfffffff0
+	go120Magic = 0xfffffff1
+)
+
+var magics = map[uint32]Version{
+	go12Magic:  Version12,
+	go116Magic: Version116,
+	go118Magic: Version118,
+	go120Magic: Version120,
+}
+
+// pcHeaderSize is the size of the fixed part of the header: the magic, two
+// zero bytes, the instruction size quantum and the pointer size.
+const pcHeaderSize = 8
+
+// Func is a function listed in the pclntab.
+type Func struct {
+	Name string
+	// Entry is the address of the first instruction of the function and End
+	// the address following its last one.
+	Entry uint64
+	End   uint64
+
+	// off is the offset of the runtime._func structure in funcdata.
+	off uint64
+}
+
+// Table is a decoded pclntab.
+type Table struct {
+	Version   Version
+	ByteOrder binary.ByteOrder
+	PtrSize   int
+	Quantum   int
+	// TextStart is the address the function entries of Go 1.18 and later
+	// tables are relative to.
+	TextStart uint64
+	// Funcs lists the functions of the table, sorted by entry address.
+	Funcs []*Func
+
+	data        []byte
+	funcnametab []byte
+	cutab       []byte
+	filetab     []byte
+	pctab       []byte
+	funcdata    []byte
+	nfiletab    uint32
+}
+
+// Parse decodes the pclntab data. For Go 1.18 and later tables, textStart is
+// the address of the start of the module's text (runtime.text); if it is 0
+// the address recorded in the table header is used, which Go 1.26 and later
+// no longer fill in.
+func Parse(data []byte, textStart uint64) (*Table, error) {
+	v, order := detectVersion(data)
+	if v == VersionUnknown {
+		return nil, fmt.Errorf("not a pclntab")
+	}
+
+	t := &Table{
+		Version:   v,
+		ByteOrder: order,
+		Quantum:   int(data[6]),
+		PtrSize:   int(data[7]),
+		data:      data,
+	}
+
+	words := 2 // nfunc and the Go 1.2 functab start
+	switch v {
+	case Version116:
+		words = 7
+	case Version118, Version120:
+		words = 8
+	}
+	if uint64(len(data)) < pcHeaderSize+uint64(words*t.PtrSize) {
+		return nil, fmt.Errorf("pclntab header too short")
+	}
+
+	var (
+		nfunc    = t.word(0)
+		functab  []byte
+		fieldErr error
+	)
+	section := func(word int) []byte {
+		off := t.word(word)
+		if off > uint64(len(data)) {
+			fieldErr = fmt.Errorf("pclntab table offset %#x out of range", off)
+			return nil
+		}
+		return data[off:]
+	}
+
+	switch v {
+	case Version12:
+		functab = data[pcHeaderSize+t.PtrSize:]
+		t.funcnametab, t.pctab, t.funcdata = data, data, data
+	case Version116:
+		t.nfiletab = uint32(t.word(1))
+		t.funcnametab = section(2)
+		t.cutab = section(3)
+		t.filetab = section(4)
+		t.pctab = section(5)
+		t.funcdata = section(6)
+		functab = t.funcdata
+	case Version118, Version120:
+		t.nfiletab = uint32(t.word(1))
+		if textStart == 0 {
+			textStart = t.word(2)
+		}
+		t.TextStart = textStart
+		t.funcnametab = section(3)
+		t.cutab = section(4)
+		t.filetab = section(5)
+		t.pctab = section(6)
+		t.funcdata = section(7)
+		functab = t.funcdata
+	}
+	if fieldErr != nil {
+		return nil, fieldErr
+	}
+
+	size := uint64(t.functabFieldSize())
+	if nfunc > uint64(len(functab))/size/2 || (2*nfunc+1)*size > uint64(len(functab)) {
+		return nil, fmt.Errorf("pclntab function table with %d entries out of range", nfunc)
+	}
+
+	if v == Version12 {
+		// The file table follows the function table and is addressed by a
+		// uint32 offset.
+		fileoff := uint64(order.Uint32(functab[(2*nfunc+1)*size:]))
+		if fileoff+4 > uint64(len(data)) {
+			return nil, fmt.Errorf("pclntab file table offset %#x out of range", fileoff)
+		}
+		t.filetab = data[fileoff:]
+		t.nfiletab = order.Uint32(t.filetab)
+	}
+
+	t.Funcs = make([]*Func, nfunc)
+	for i := uint64(0); i < nfunc; i++ {
+		entry := t.functabField(functab, 2*i)
+		off := t.functabField(functab, 2*i+1)
+		end := t.functabField(functab, 2*i+2)
+		if v >= Version118 {
+			entry += t.TextStart
+			end += t.TextStart
+		}
+
+		f := &Func{Entry: entry, End: end, off: off}
+		nameoff, ok := t.funcField(f, 1)
+		if !ok {
+			return nil, fmt.Errorf("pclntab function %d at %#x out of range", i, off)
+		}
+		f.Name = cstring(t.funcnametab, uint64(nameoff))
+		t.Funcs[i] = f
+	}
+
+	return t, nil
+}
+
+// detectVersion identifies a pclntab by its header.
+func detectVersion(data []byte) (Version, binary.ByteOrder) {
+	if len(data) < pcHeaderSize || data[4] != 0 || data[5] != 0 {
+		return VersionUnknown, nil
+	}
+	if q := data[6]; q != 1 && q != 2 && q != 4 {
+		return VersionUnknown, nil
+	}
+	if p := data[7]; p != 4 && p != 8 {
+		return VersionUnknown, nil
+	}
+
+	if v, ok := magics[binary.LittleEndian.Uint32(data)]; ok {
+		return v, binary.LittleEndian
+	}
+	if v, ok := magics[binary.BigEndian.Uint32(data)]; ok {
+		return v, binary.BigEndian
+	}
+
+	return VersionUnknown, nil
+}
+
+// word returns the i-th pointer-sized word of the header after its fixed
+// part.
+func (t *Table) word(i int) uint64 {
+	return t.uintptr(t.data[pcHeaderSize+i*t.PtrSize:])
+}
+
+func (t *Table) uintptr(b []byte) uint64 {
+	if t.PtrSize == 4 {
+		return uint64(t.ByteOrder.Uint32(b))
+	}
+
+	return t.ByteOrder.Uint64(b)
+}
+
+// functabFieldSize returns the size of the entries of the function table,
+// which Go 1.18 shrank from pointers to offsets from the text start.
+func (t *Table) functabFieldSize() int {
+	if t.Version >= Version118 {
+		return 4
+	}
+
+	return t.PtrSize
+}
+
+func (t *Table) functabField(functab []byte, i uint64) uint64 {
+	size := uint64(t.functabFieldSize())
+	if size == 4 {
+		return uint64(t.ByteOrder.Uint32(functab[i*size:]))
+	}
+
+	return t.ByteOrder.Uint64(functab[i*size:])
+}
+
+// funcField returns the n-th uint32 field of the runtime._func structure of
+// f, counting the entry as field 0. The fields used here keep their place
+// across versions: 1 is the name offset, 5 and 6 the file and line tables
+// and 8 the compilation unit offset.
+func (t *Table) funcField(f *Func, n int) (uint32, bool) {
+	sz0 := uint64(t.PtrSize)
+	if t.Version >= Version118 {
+		sz0 = 4
+	}
+	off := f.off + sz0 + uint64(n-1)*4
+	if off < f.off || off+4 > uint64(len(t.funcdata)) {
+		return 0, false
+	}
+
+	return t.ByteOrder.Uint32(t.funcdata[off:]), true
+}
+
+// LookupFunc returns the function containing pc, or nil.
+func (t *Table) LookupFunc(pc uint64) *Func {
+	i := sort.Search(len(t.Funcs), func(i int) bool { return t.Funcs[i].Entry > pc })
+	if i == 0 {
+		return nil
+	}
+
+	f := t.Funcs[i-1]
+	if pc >= f.End {
+		return nil
+	}
+
+	return f
+}
+
+// PCToLine returns the source file and line of the instruction at pc and the
+// function containing it. It returns a nil function if pc is not covered by
+// the table.
+func (t *Table) PCToLine(pc uint64) (file string, line int, fn *Func) {
+	fn = t.LookupFunc(pc)
+	if fn == nil {
+		return "", 0, nil
+	}
+
+	pcfile, ok1 := t.funcField(fn, 5)
+	pcln, ok2 := t.funcField(fn, 6)
+	if !ok1 || !ok2 {
+		return "", 0, fn
+	}
+
+	fno := t.pcValue(pcfile, fn.Entry, pc)
+	line = int(t.pcValue(pcln, fn.Entry, pc))
+	if fno < 0 || line < 0 {
+		return "", 0, fn
+	}
+
+	return t.fileName(fn, uint32(fno)), line, fn
+}
+
+// fileName resolves the file number fno of function f.
+func (t *Table) fileName(f *Func, fno uint32) string {
+	if t.Version == Version12 {
+		if fno == 0 || fno >= t.nfiletab || uint64(fno)*4+4 > uint64(len(t.filetab)) {
+			return ""
+		}
+		return cstring(t.data, uint64(t.ByteOrder.Uint32(t.filetab[4*fno:])))
+	}
+
+	cuOffset, ok := t.funcField(f, 8)
+	if !ok {
+		return ""
+	}
+	i := (uint64(cuOffset) + uint64(fno)) * 4
+	if i+4 > uint64(len(t.cutab)) {
+		return ""
+	}
+	off := t.ByteOrder.Uint32(t.cutab[i:])
+	if off == ^uint32(0) {
+		return ""
+	}
+
+	return cstring(t.filetab, uint64(off))
+}
+
+// pcValue evaluates the pc-value table at offset off of pctab for the
+// function starting at entry, returning the value at targetpc or -1.
+func (t *Table) pcValue(off uint32, entry, targetpc uint64) int32 {
+	if uint64(off) >= uint64(len(t.pctab)) {
+		return -1
+	}
+
+	p := t.pctab[off:]
+	pc := entry
+	val := int32(-1)
+	for first := true; ; first = false {
+		uvdelta, n := binary.Uvarint(p)
+		if n <= 0 || uvdelta == 0 && !first {
+			return -1
+		}
+		p = p[n:]
+
+		pcdelta, n := binary.Uvarint(p)
+		if n <= 0 {
+			return -1
+		}
+		p = p[n:]
+
+		// The value delta is zig-zag encoded.
+		if uvdelta&1 != 0 {
+			uvdelta = ^(uvdelta >> 1)
+		} else {
+			uvdelta >>= 1
+		}
+		val += int32(uvdelta)
+		pc += pcdelta * uint64(t.Quantum)
+		if targetpc < pc {
+			return val
+		}
+	}
+}
+
+// cstring returns the NUL-terminated string at off of b.
+func cstring(b []byte, off uint64) string {
+	if off >= uint64(len(b)) {
+		return ""
+	}
+	if i := bytes.IndexByte(b[off:], 0); i >= 0 {
+		return string(b[off : off+uint64(i)])
+	}
+
+	return string(b[off:])
+}
@@ -0,0 +1,277 @@
+package gosym_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	stdgosym "debug/gosym"
+	"encoding/binary"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+	"github.com/hnts/goelftools/gosym"
+)
+
+const (
+	go116Binary = "../testdata/elf_linux_amd64"
+	go127Binary = "../testdata/gohello_linux_amd64"
+)
+
+func TestNew(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		version gosym.Version
+		file    string
+	}{
+		{go116Binary, gosym.Version116, "sample.go"},
+		{go127Binary, gosym.Version120, "example.com/hello/main.go"},
+	} {
+		e, tab := mustNew(t, tt.name)
+		if tab.Version != tt.version || tab.PtrSize != 8 || tab.Quantum != 1 || tab.ByteOrder != binary.LittleEndian {
+			t.Errorf("%s: have version %v, pointer size %d, quantum %d", tt.name, tab.Version, tab.PtrSize, tab.Quantum)
+		}
+
+		compareWithStd(t, tt.name, tab, stdTable(t, e))
+
+		var main *gosym.Func
+		for _, f := range tab.Funcs {
+			if f.Name == "main.main" {
+				main = f
+			}
+		}
+		if main == nil {
+			t.Fatalf("%s: main.main not found", tt.name)
+		}
+		if file, line, fn := tab.PCToLine(main.Entry); fn != main || !strings.HasSuffix(file, tt.file) || line == 0 {
+			t.Errorf("%s: main.main entry: have %s:%d in %v", tt.name, file, line, fn)
+		}
+		if file, line, fn := tab.PCToLine(0x10); file != "" || line != 0 || fn != nil {
+			t.Errorf("%s: unmapped pc: have %s:%d in %v", tt.name, file, line, fn)
+		}
+	}
+}
+
+func TestNewStripped(t *testing.T) {
+	for _, name := range []string{go116Binary, go127Binary} {
+		e, want := mustNew(t, name)
+
+		// Without section headers the table is found through the moduledata.
+		e.Sections = nil
+		raw := writeFile(t, e)
+		stripped, err := elf.New(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tab, err := gosym.New(stripped)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		compareFuncs(t, name+" (stripped)", tab, want)
+
+		// Without the moduledata pointing at it, by its magic number. Only
+		// tables before Go 1.18 are usable then, as Go 1.26 and later no
+		// longer record the text start in the table.
+		if want.Version >= gosym.Version118 {
+			continue
+		}
+		ptr := binary.LittleEndian.AppendUint64(nil, pclntabAddr(t, name))
+		for _, sg := range stripped.SegmentsByType(elf.PT_LOAD) {
+			if sg.Header.Flags&elf.PF_W != 0 {
+				end := sg.Header.Offset + sg.Header.Filesz
+				copy(raw[sg.Header.Offset:end], bytes.ReplaceAll(raw[sg.Header.Offset:end], ptr, make([]byte, 8)))
+			}
+		}
+		scanned, err := elf.New(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tab, err = gosym.New(scanned)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		compareFuncs(t, name+" (scanned)", tab, want)
+	}
+}
+
+// TestParse115 decodes the pclntab of a Go 1.15 binary, taken from the
+// testdata of the standard library's debug/gosym package.
+func TestParse115(t *testing.T) {
+	f, err := os.Open("../testdata/pcln115.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tab, err := gosym.Parse(data, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tab.Version != gosym.Version12 {
+		t.Errorf("have version %v, want %v", tab.Version, gosym.Version12)
+	}
+
+	std, err := stdgosym.NewTable(nil, stdgosym.NewLineTable(data, 0x1001000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareWithStd(t, "pcln115", tab, std)
+
+	if file, line, fn := tab.PCToLine(0x105c280); file != "/tmp/hello.go" || line != 3 || fn == nil || fn.Name != "main.main" {
+		t.Errorf("have %s:%d in %v, want /tmp/hello.go:3 in main.main", file, line, fn)
+	}
+}
+
+// TestParse118 decodes a Go 1.20 table relabelled as a Go 1.18 one, as the
+// two layouts only differ in the magic number.
+func TestParse118(t *testing.T) {
+	e, want := mustNew(t, go127Binary)
+	data, err := e.SectionByName(".gopclntab").Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data = append([]byte(nil), data...)
+	binary.LittleEndian.PutUint32(data, 0xfffffff0)
+	tab, err := gosym.Parse(data, want.TextStart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tab.Version != gosym.Version118 {
+		t.Errorf("have version %v, want %v", tab.Version, gosym.Version118)
+	}
+	compareFuncs(t, "go1.18", tab, want)
+}
+
+func TestParseMalformed(t *testing.T) {
+	e, _ := mustNew(t, go127Binary)
+	data, err := e.SectionByName(".gopclntab").Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, mutate := range map[string]func([]byte) []byte{
+		"short header":   func(b []byte) []byte { return b[:20] },
+		"bad magic":      func(b []byte) []byte { b[0] = 0; return b },
+		"bad pointer":    func(b []byte) []byte { b[7] = 3; return b },
+		"too many funcs": func(b []byte) []byte { binary.LittleEndian.PutUint64(b[8:], 1<<40); return b },
+		"bad offset":     func(b []byte) []byte { binary.LittleEndian.PutUint64(b[8+3*8:], 1<<40); return b },
+	} {
+		if _, err := gosym.Parse(mutate(append([]byte(nil), data...)), 0); err == nil {
+			t.Errorf("%s: should fail", name)
+		}
+	}
+
+	raw, err := os.ReadFile("../testdata/hello_linux_amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, err = elf.New(raw); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gosym.New(e); err == nil {
+		t.Errorf("a C binary should not have a pclntab")
+	}
+}
+
+func mustNew(t *testing.T, name string) (*elf.File, *gosym.Table) {
+	t.Helper()
+
+	raw, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tab, err := gosym.New(e)
+	if err != nil {
+		t.Fatalf("%s: %s", name, err)
+	}
+
+	return e, tab
+}
+
+func writeFile(t *testing.T, e *elf.File) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if _, err := e.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func pclntabAddr(t *testing.T, name string) uint64 {
+	t.Helper()
+
+	e, _ := mustNew(t, name)
+
+	return e.SectionByName(".gopclntab").Header.Addr
+}
+
+func stdTable(t *testing.T, e *elf.File) *stdgosym.Table {
+	t.Helper()
+
+	data, err := e.SectionByName(".gopclntab").Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tab, err := stdgosym.NewTable(nil, stdgosym.NewLineTable(data, e.SectionByName(".text").Header.Addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tab
+}
+
+func compareFuncs(t *testing.T, name string, have, want *gosym.Table) {
+	t.Helper()
+
+	if len(have.Funcs) != len(want.Funcs) {
+		t.Fatalf("%s: have %d functions, want %d", name, len(have.Funcs), len(want.Funcs))
+	}
+	for i, f := range have.Funcs {
+		if g := want.Funcs[i]; f.Name != g.Name || f.Entry != g.Entry || f.End != g.End {
+			t.Fatalf("%s: function %d:\n\thave %#v\n\twant %#v\n", name, i, f, g)
+		}
+	}
+}
+
+// compareWithStd checks the functions of tab and the lines of every few
+// instructions against debug/gosym.
+func compareWithStd(t *testing.T, name string, tab *gosym.Table, std *stdgosym.Table) {
+	t.Helper()
+
+	if len(tab.Funcs) != len(std.Funcs) {
+		t.Fatalf("%s: have %d functions, want %d", name, len(tab.Funcs), len(std.Funcs))
+	}
+	for i, sf := range std.Funcs {
+		f := tab.Funcs[i]
+		if f.Name != sf.Name || f.Entry != sf.Entry || f.End != sf.End {
+			t.Fatalf("%s: function %d: have %s [%#x, %#x), want %s [%#x, %#x)",
+				name, i, f.Name, f.Entry, f.End, sf.Name, sf.Entry, sf.End)
+		}
+
+		for pc := sf.Entry; pc < sf.End; pc += 5 {
+			wantFile, wantLine, _ := std.PCToLine(pc)
+			if wantLine < 0 {
+				wantFile, wantLine = "", 0
+			}
+			if file, line, fn := tab.PCToLine(pc); file != wantFile || line != wantLine || fn != f {
+				t.Fatalf("%s: pc %#x: have %s:%d in %v, want %s:%d in %s", name, pc, file, line, fn, wantFile, wantLine, sf.Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package zstd
+
+// loadBits returns the n bits of data starting at bit start, where bit i is
+// bit i%8 of byte i/8. Bits beyond the end of data read as zeros. n must not
+// exceed 56.
+func loadBits(data []byte, start, n int) uint64 {
+	if n == 0 {
+		return 0
+	}
+
+	var w uint64
+	off := start / 8
+	for i := 0; i < 8 && off+i < len(data); i++ {
+		w |= uint64(data[off+i]) << (8 * i)
+	}
+
+	return (w >> (start % 8)) & (1<<n - 1)
+}
+
+// forwardBits reads the little-endian bit stream of FSE table descriptions,
+// starting from the least significant bit of the first byte.
+type forwardBits struct {
+	data []byte
+	pos  int
+}
+
+func (r *forwardBits) read(n int) uint64 {
+	v := loadBits(r.data, r.pos, n)
+	r.pos += n
+	return v
+}
+
+func (r *forwardBits) peek(n int) uint64 {
+	return loadBits(r.data, r.pos, n)
+}
+
+// bytesRead returns the number of bytes the bits read so far span.
+func (r *forwardBits) bytesRead() int {
+	return (r.pos + 7) / 8
+}
+
+// backwardBits reads the bit streams of Huffman and FSE coded data, which
+// are written forwards and read backwards: reading starts below the highest
+// set bit of the last byte and proceeds towards the first bit. Reading past
+// the first bit yields zeros and is detected by overflowed.
+type backwardBits struct {
+	data []byte
+	pos  int
+}
+
+func newBackwardBits(data []byte) (*backwardBits, error) {
+	if len(data) == 0 || data[len(data)-1] == 0 {
+		return nil, corrupt("missing bit stream end marker")
+	}
+
+	last := data[len(data)-1]
+	hb := 7
+	for last&(1<<hb) == 0 {
+		hb--
+	}
+
+	return &backwardBits{data: data, pos: (len(data)-1)*8 + hb}, nil
+}
+
+func (r *backwardBits) peek(n int) uint64 {
+	start := r.pos - n
+	if start >= 0 {
+		return loadBits(r.data, start, n)
+	}
+	if r.pos <= 0 {
+		return 0
+	}
+
+	return loadBits(r.data, 0, r.pos) << -start
+}
+
+func (r *backwardBits) read(n int) uint64 {
+	v := r.peek(n)
+	r.pos -= n
+	return v
+}
+
+func (r *backwardBits) overflowed() bool {
+	return r.pos < 0
+}
+
+func (r *backwardBits) finished() bool {
+	return r.pos == 0
+}
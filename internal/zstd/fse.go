@@ -0,0 +1,178 @@
+package zstd
+
+// fseEntry is a state of an FSE decoding table: the symbol it decodes and
+// how to compute the next state.
+type fseEntry struct {
+	symbol uint8
+	nbBits uint8
+	base   uint16
+}
+
+// fseTable is an FSE decoding table of 1<<accuracyLog states.
+type fseTable struct {
+	accuracyLog int
+	entries     []fseEntry
+}
+
+// readFSETable decodes the normalized probabilities of an FSE table
+// description at the start of data and builds the decoding table. It returns
+// the table and the number of bytes the description takes.
+func readFSETable(data []byte, maxSymbol, maxLog int) (*fseTable, int, error) {
+	r := &forwardBits{data: data}
+	if len(data) == 0 {
+		return nil, 0, corrupt("missing FSE table description")
+	}
+
+	accuracyLog := int(r.read(4)) + 5
+	if accuracyLog > maxLog {
+		return nil, 0, corrupt("FSE accuracy log too large")
+	}
+
+	probs := make([]int16, 0, maxSymbol+1)
+	remaining := (1 << accuracyLog) + 1
+	threshold := 1 << accuracyLog
+	nbBits := accuracyLog + 1
+	for remaining > 1 {
+		if len(probs) > maxSymbol {
+			return nil, 0, corrupt("too many FSE symbols")
+		}
+
+		max := 2*threshold - 1 - remaining
+		var count int
+		if v := int(r.peek(nbBits - 1)); v < max {
+			count = v
+			r.pos += nbBits - 1
+		} else {
+			count = int(r.read(nbBits))
+			if count >= threshold {
+				count -= max
+			}
+		}
+		count--
+
+		if count < 0 {
+			remaining--
+		} else {
+			remaining -= count
+		}
+		probs = append(probs, int16(count))
+
+		if count == 0 {
+			// A zero probability is followed by 2-bit repeat flags
+			// giving the number of further zero probabilities.
+			for {
+				repeat := int(r.read(2))
+				for i := 0; i < repeat; i++ {
+					probs = append(probs, 0)
+				}
+				if repeat != 3 {
+					break
+				}
+			}
+			if len(probs) > maxSymbol+1 {
+				return nil, 0, corrupt("too many FSE symbols")
+			}
+		}
+
+		for remaining < threshold && threshold > 1 {
+			nbBits--
+			threshold >>= 1
+		}
+
+		if r.bytesRead() > len(data) {
+			return nil, 0, corrupt("truncated FSE table description")
+		}
+	}
+	if remaining != 1 {
+		return nil, 0, corrupt("invalid FSE probabilities")
+	}
+
+	t, err := buildFSETable(probs, accuracyLog)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return t, r.bytesRead(), nil
+}
+
+// buildFSETable spreads the symbols over the states of the table according
+// to their normalized probabilities, where -1 stands for "less than 1".
+func buildFSETable(probs []int16, accuracyLog int) (*fseTable, error) {
+	size := 1 << accuracyLog
+	entries := make([]fseEntry, size)
+	next := make([]uint16, len(probs))
+
+	high := size - 1
+	for s, p := range probs {
+		if p == -1 {
+			entries[high].symbol = uint8(s)
+			high--
+			next[s] = 1
+		} else {
+			next[s] = uint16(p)
+		}
+	}
+
+	pos := 0
+	step := size>>1 + size>>3 + 3
+	mask := size - 1
+	for s, p := range probs {
+		for i := 0; i < int(p); i++ {
+			entries[pos].symbol = uint8(s)
+			pos = (pos + step) & mask
+			for pos > high {
+				pos = (pos + step) & mask
+			}
+		}
+	}
+	if pos != 0 {
+		return nil, corrupt("invalid FSE probabilities")
+	}
+
+	for i := range entries {
+		s := entries[i].symbol
+		n := next[s]
+		next[s]++
+		nb := accuracyLog - highBit(uint32(n))
+		entries[i].nbBits = uint8(nb)
+		entries[i].base = uint16(int(n)<<nb - size)
+	}
+
+	return &fseTable{accuracyLog: accuracyLog, entries: entries}, nil
+}
+
+// rleFSETable returns a table that always decodes symbol without reading
+// any bits.
+func rleFSETable(symbol uint8) *fseTable {
+	return &fseTable{entries: []fseEntry{{symbol: symbol}}}
+}
+
+func highBit(v uint32) int {
+	n := -1
+	for v != 0 {
+		v >>= 1
+		n++
+	}
+	return n
+}
+
+// fseState is the state of an FSE decoder reading from a backward bit
+// stream.
+type fseState struct {
+	table *fseTable
+	state uint64
+}
+
+func (s *fseState) init(t *fseTable, r *backwardBits) {
+	s.table = t
+	s.state = r.read(t.accuracyLog)
+}
+
+func (s *fseState) symbol() uint8 {
+	return s.table.entries[s.state].symbol
+}
+
+func (s *fseState) update(r *backwardBits) {
+	e := s.table.entries[s.state]
+	s.state = uint64(e.base) + r.read(int(e.nbBits))
+}
@@ -0,0 +1,181 @@
+package zstd
+
+const maxHuffmanBits = 11
+
+// huffEntry is an entry of a Huffman decoding table indexed by the next
+// maxBits bits of the stream.
+type huffEntry struct {
+	symbol uint8
+	nbBits uint8
+}
+
+type huffTable struct {
+	maxBits int
+	entries []huffEntry
+}
+
+// readHuffTable decodes the Huffman tree description at the start of data.
+// It returns the table and the number of bytes the description takes.
+func readHuffTable(data []byte) (*huffTable, int, error) {
+	if len(data) == 0 {
+		return nil, 0, corrupt("missing Huffman tree description")
+	}
+
+	var weights []uint8
+	hdr := int(data[0])
+	var size int
+	if hdr >= 128 {
+		// Weights stored directly as 4-bit values.
+		n := hdr - 127
+		size = 1 + (n+1)/2
+		if len(data) < size {
+			return nil, 0, corrupt("truncated Huffman weights")
+		}
+		weights = make([]uint8, n)
+		for i := range weights {
+			b := data[1+i/2]
+			if i%2 == 0 {
+				weights[i] = b >> 4
+			} else {
+				weights[i] = b & 0xf
+			}
+		}
+	} else {
+		size = 1 + hdr
+		if len(data) < size {
+			return nil, 0, corrupt("truncated Huffman weights")
+		}
+		var err error
+		weights, err = decodeHuffWeights(data[1:size])
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	t, err := buildHuffTable(weights)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return t, size, nil
+}
+
+// decodeHuffWeights decodes FSE compressed Huffman weights, which use two
+// interleaved states sharing one bit stream.
+func decodeHuffWeights(data []byte) ([]uint8, error) {
+	t, n, err := readFSETable(data, 255, 6)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := newBackwardBits(data[n:])
+	if err != nil {
+		return nil, err
+	}
+
+	var s1, s2 fseState
+	s1.init(t, r)
+	s2.init(t, r)
+
+	var weights []uint8
+	for {
+		if len(weights) > 255 {
+			return nil, corrupt("too many Huffman weights")
+		}
+
+		weights = append(weights, s1.symbol())
+		s1.update(r)
+		if r.overflowed() {
+			weights = append(weights, s2.symbol())
+			break
+		}
+
+		weights = append(weights, s2.symbol())
+		s2.update(r)
+		if r.overflowed() {
+			weights = append(weights, s1.symbol())
+			break
+		}
+	}
+
+	return weights, nil
+}
+
+// buildHuffTable builds the decoding table for the weights of the symbols
+// 0 to len(weights)-1. The weight of the last symbol is implied by the
+// others: it completes the sum of 2^(w-1) to a power of two.
+func buildHuffTable(weights []uint8) (*huffTable, error) {
+	if len(weights) > 255 {
+		return nil, corrupt("too many Huffman weights")
+	}
+
+	var total uint32
+	for _, w := range weights {
+		if w > maxHuffmanBits {
+			return nil, corrupt("invalid Huffman weight")
+		}
+		if w > 0 {
+			total += 1 << (w - 1)
+		}
+	}
+	if total == 0 {
+		return nil, corrupt("invalid Huffman weights")
+	}
+
+	maxBits := highBit(total) + 1
+	if maxBits > maxHuffmanBits {
+		return nil, corrupt("Huffman table too deep")
+	}
+	rest := uint32(1)<<maxBits - total
+	if rest&(rest-1) != 0 {
+		return nil, corrupt("invalid Huffman weights")
+	}
+	weights = append(weights, uint8(highBit(rest)+1))
+
+	// Symbols take table ranges in order of increasing weight, then of
+	// increasing value.
+	var rankStart [maxHuffmanBits + 2]uint32
+	var counts [maxHuffmanBits + 2]uint32
+	for _, w := range weights {
+		counts[w]++
+	}
+	var next uint32
+	for w := 1; w <= maxBits; w++ {
+		rankStart[w] = next
+		next += counts[w] << (w - 1)
+	}
+
+	entries := make([]huffEntry, 1<<maxBits)
+	for s, w := range weights {
+		if w == 0 {
+			continue
+		}
+		n := uint32(1) << (w - 1)
+		e := huffEntry{symbol: uint8(s), nbBits: uint8(maxBits + 1 - int(w))}
+		for i := rankStart[w]; i < rankStart[w]+n; i++ {
+			entries[i] = e
+		}
+		rankStart[w] += n
+	}
+
+	return &huffTable{maxBits: maxBits, entries: entries}, nil
+}
+
+// decodeHuffStream decodes n literals from a single Huffman coded stream.
+func (t *huffTable) decodeStream(dst []byte, data []byte, n int) ([]byte, error) {
+	r, err := newBackwardBits(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < n; i++ {
+		e := t.entries[r.peek(t.maxBits)]
+		dst = append(dst, e.symbol)
+		r.pos -= int(e.nbBits)
+	}
+	if !r.finished() {
+		return nil, corrupt("Huffman stream not fully consumed")
+	}
+
+	return dst, nil
+}
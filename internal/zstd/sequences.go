@@ -0,0 +1,234 @@
+package zstd
+
+// Baselines and numbers of extra bits of the literals length and match
+// length codes.
+var (
+	llBase = [36]uint32{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+		16, 18, 20, 22, 24, 28, 32, 40, 48, 64, 128, 256, 512, 1024, 2048, 4096,
+		8192, 16384, 32768, 65536,
+	}
+	llBits = [36]uint8{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		1, 1, 1, 1, 2, 2, 3, 3, 4, 6, 7, 8, 9, 10, 11, 12,
+		13, 14, 15, 16,
+	}
+	mlBase = [53]uint32{
+		3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18,
+		19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34,
+		35, 37, 39, 41, 43, 47, 51, 59, 67, 83, 99, 131, 259, 515, 1027, 2051,
+		4099, 8195, 16387, 32771, 65539,
+	}
+	mlBits = [53]uint8{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		1, 1, 1, 1, 2, 2, 3, 3, 4, 4, 5, 7, 8, 9, 10, 11,
+		12, 13, 14, 15, 16,
+	}
+)
+
+// Predefined distributions of the literals length, match length and offset
+// codes.
+var (
+	llDefault = []int16{
+		4, 3, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 1, 1, 1,
+		2, 2, 2, 2, 2, 2, 2, 2, 2, 3, 2, 1, 1, 1, 1, 1,
+		-1, -1, -1, -1,
+	}
+	mlDefault = []int16{
+		1, 4, 3, 2, 2, 2, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, -1,
+		-1, -1, -1, -1, -1,
+	}
+	ofDefault = []int16{
+		1, 1, 1, 1, 1, 1, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1,
+	}
+
+	llDefaultTable = mustBuildFSETable(llDefault, 6)
+	mlDefaultTable = mustBuildFSETable(mlDefault, 6)
+	ofDefaultTable = mustBuildFSETable(ofDefault, 5)
+)
+
+func mustBuildFSETable(probs []int16, accuracyLog int) *fseTable {
+	t, err := buildFSETable(probs, accuracyLog)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// sequences decodes the sequences section of a compressed block and
+// executes the sequences against d.literals.
+func (d *decoder) sequences(data []byte) error {
+	if len(data) < 1 {
+		return corrupt("missing sequences section")
+	}
+
+	var n, pos int
+	switch b0 := int(data[0]); {
+	case b0 < 128:
+		n, pos = b0, 1
+	case b0 < 255:
+		if len(data) < 2 {
+			return corrupt("truncated sequences header")
+		}
+		n, pos = (b0-128)<<8+int(data[1]), 2
+	default:
+		if len(data) < 3 {
+			return corrupt("truncated sequences header")
+		}
+		n, pos = int(data[1])+int(data[2])<<8+0x7f00, 3
+	}
+
+	if n == 0 {
+		if err := d.grow(len(d.literals)); err != nil {
+			return err
+		}
+		d.out = append(d.out, d.literals...)
+		return nil
+	}
+
+	if len(data) < pos+1 {
+		return corrupt("truncated sequences header")
+	}
+	modes := data[pos]
+	pos++
+	if modes&3 != 0 {
+		return corrupt("reserved sequence compression mode bits set")
+	}
+
+	tables := []struct {
+		mode      uint8
+		table     **fseTable
+		def       *fseTable
+		maxSymbol int
+		maxLog    int
+	}{
+		{modes >> 6, &d.llTable, llDefaultTable, 35, 9},
+		{(modes >> 4) & 3, &d.ofTable, ofDefaultTable, 31, 8},
+		{(modes >> 2) & 3, &d.mlTable, mlDefaultTable, 52, 9},
+	}
+	for _, t := range tables {
+		switch t.mode {
+		case 0: // predefined
+			*t.table = t.def
+		case 1: // RLE
+			if len(data) < pos+1 {
+				return corrupt("truncated RLE sequence table")
+			}
+			if int(data[pos]) > t.maxSymbol {
+				return corrupt("invalid RLE sequence symbol")
+			}
+			*t.table = rleFSETable(data[pos])
+			pos++
+		case 2: // FSE compressed
+			table, size, err := readFSETable(data[pos:], t.maxSymbol, t.maxLog)
+			if err != nil {
+				return err
+			}
+			*t.table = table
+			pos += size
+		case 3: // repeat
+			if *t.table == nil {
+				return corrupt("repeated sequence table without a previous table")
+			}
+		}
+	}
+
+	r, err := newBackwardBits(data[pos:])
+	if err != nil {
+		return err
+	}
+
+	var ll, of, ml fseState
+	ll.init(d.llTable, r)
+	of.init(d.ofTable, r)
+	ml.init(d.mlTable, r)
+
+	lits := d.literals
+	for i := 0; i < n; i++ {
+		ofCode := of.symbol()
+		mlCode := ml.symbol()
+		llCode := ll.symbol()
+		if ofCode > 31 || int(mlCode) >= len(mlBase) || int(llCode) >= len(llBase) {
+			return corrupt("invalid sequence code")
+		}
+
+		offset := uint64(1)<<ofCode + r.read(int(ofCode))
+		matchLen := uint64(mlBase[mlCode]) + r.read(int(mlBits[mlCode]))
+		litLen := uint64(llBase[llCode]) + r.read(int(llBits[llCode]))
+
+		offset = d.resolveOffset(offset, litLen)
+
+		if i != n-1 {
+			ll.update(r)
+			ml.update(r)
+			of.update(r)
+		}
+		if r.overflowed() {
+			return corrupt("sequences bit stream overflow")
+		}
+
+		if litLen > uint64(len(lits)) {
+			return corrupt("literals length exceeds the literals")
+		}
+		if err := d.grow(int(litLen + matchLen)); err != nil {
+			return err
+		}
+		d.out = append(d.out, lits[:litLen]...)
+		lits = lits[litLen:]
+
+		if offset == 0 || offset > uint64(len(d.out)-d.start) {
+			return corrupt("match offset out of range")
+		}
+		from := len(d.out) - int(offset)
+		for j := 0; j < int(matchLen); j++ {
+			d.out = append(d.out, d.out[from+j])
+		}
+	}
+	if !r.finished() {
+		return corrupt("sequences bit stream not fully consumed")
+	}
+
+	if err := d.grow(len(lits)); err != nil {
+		return err
+	}
+	d.out = append(d.out, lits...)
+
+	return nil
+}
+
+// resolveOffset turns an offset value into a match offset, using and
+// updating the repeated offsets.
+func (d *decoder) resolveOffset(value, litLen uint64) uint64 {
+	rep := &d.repeats
+	if value > 3 {
+		offset := value - 3
+		rep[2], rep[1], rep[0] = rep[1], rep[0], offset
+		return offset
+	}
+
+	idx := value - 1
+	if litLen == 0 {
+		idx++
+	}
+
+	var offset uint64
+	switch idx {
+	case 0:
+		return rep[0]
+	case 1:
+		offset = rep[1]
+	case 2:
+		offset = rep[2]
+		rep[2] = rep[1]
+	case 3:
+		offset = rep[0] - 1
+		rep[2] = rep[1]
+	}
+	rep[1], rep[0] = rep[0], offset
+
+	return offset
+}
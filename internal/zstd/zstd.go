@@ -0,0 +1,324 @@
+// Package zstd implements a decoder for the Zstandard compression format
+// described in RFC 8878, as used by ELFCOMPRESS_ZSTD sections. Dictionaries
+// are not supported and content checksums are not verified.
+package zstd
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	frameMagic         = 0xfd2fb528
+	skippableMagicMask = 0xfffffff0
+	skippableMagic     = 0x184d2a50
+	maxBlockSize       = 128 << 10
+)
+
+func corrupt(msg string) error {
+	return fmt.Errorf("zstd: corrupt input: %s", msg)
+}
+
+// Decompress decompresses the concatenated frames of src. It fails if the
+// output would exceed limit bytes.
+func Decompress(src []byte, limit uint64) ([]byte, error) {
+	var out []byte
+	for len(src) > 0 {
+		if len(src) < 4 {
+			return nil, corrupt("truncated frame")
+		}
+
+		magic := binary.LittleEndian.Uint32(src)
+		if magic&skippableMagicMask == skippableMagic {
+			if len(src) < 8 {
+				return nil, corrupt("truncated skippable frame")
+			}
+			n := uint64(binary.LittleEndian.Uint32(src[4:]))
+			if n > uint64(len(src)-8) {
+				return nil, corrupt("truncated skippable frame")
+			}
+			src = src[8+n:]
+			continue
+		}
+		if magic != frameMagic {
+			return nil, fmt.Errorf("zstd: invalid magic number: %#x", magic)
+		}
+
+		d := &decoder{out: out, start: len(out), limit: limit}
+		n, err := d.frame(src[4:])
+		if err != nil {
+			return nil, err
+		}
+		out = d.out
+		src = src[4+n:]
+	}
+
+	return out, nil
+}
+
+// decoder holds the state kept across the blocks of a frame.
+type decoder struct {
+	out   []byte
+	start int
+	limit uint64
+
+	huff     *huffTable
+	llTable  *fseTable
+	ofTable  *fseTable
+	mlTable  *fseTable
+	repeats  [3]uint64
+	literals []byte
+}
+
+// frame decodes the frame following the magic number in data and returns
+// its size.
+func (d *decoder) frame(data []byte) (int, error) {
+	if len(data) < 1 {
+		return 0, corrupt("truncated frame header")
+	}
+
+	desc := data[0]
+	fcsFlag := desc >> 6
+	singleSegment := desc&0x20 != 0
+	checksum := desc&0x04 != 0
+	dictIDFlag := desc & 0x03
+	if desc&0x08 != 0 {
+		return 0, corrupt("reserved frame header bit set")
+	}
+
+	pos := 1
+	if !singleSegment {
+		pos++ // window descriptor
+	}
+	dictIDSize := [4]int{0, 1, 2, 4}[dictIDFlag]
+	if len(data) < pos+dictIDSize {
+		return 0, corrupt("truncated frame header")
+	}
+	for i := 0; i < dictIDSize; i++ {
+		if data[pos+i] != 0 {
+			return 0, fmt.Errorf("zstd: dictionaries are not supported")
+		}
+	}
+	pos += dictIDSize
+
+	fcsSize := [4]int{0, 2, 4, 8}[fcsFlag]
+	if fcsFlag == 0 && singleSegment {
+		fcsSize = 1
+	}
+	if len(data) < pos+fcsSize {
+		return 0, corrupt("truncated frame header")
+	}
+	pos += fcsSize
+
+	d.repeats = [3]uint64{1, 4, 8}
+	for {
+		if len(data) < pos+3 {
+			return 0, corrupt("truncated block header")
+		}
+		hdr := uint32(data[pos]) | uint32(data[pos+1])<<8 | uint32(data[pos+2])<<16
+		pos += 3
+
+		last := hdr&1 != 0
+		typ := (hdr >> 1) & 3
+		size := int(hdr >> 3)
+		if size > maxBlockSize {
+			return 0, corrupt("block too large")
+		}
+
+		switch typ {
+		case 0: // raw
+			if len(data) < pos+size {
+				return 0, corrupt("truncated raw block")
+			}
+			if err := d.grow(size); err != nil {
+				return 0, err
+			}
+			d.out = append(d.out, data[pos:pos+size]...)
+			pos += size
+		case 1: // RLE
+			if len(data) < pos+1 {
+				return 0, corrupt("truncated RLE block")
+			}
+			if err := d.grow(size); err != nil {
+				return 0, err
+			}
+			for i := 0; i < size; i++ {
+				d.out = append(d.out, data[pos])
+			}
+			pos++
+		case 2: // compressed
+			if len(data) < pos+size {
+				return 0, corrupt("truncated compressed block")
+			}
+			if err := d.compressedBlock(data[pos : pos+size]); err != nil {
+				return 0, err
+			}
+			pos += size
+		default:
+			return 0, corrupt("reserved block type")
+		}
+
+		if last {
+			break
+		}
+	}
+
+	if checksum {
+		if len(data) < pos+4 {
+			return 0, corrupt("truncated checksum")
+		}
+		pos += 4
+	}
+
+	return pos, nil
+}
+
+func (d *decoder) grow(n int) error {
+	if uint64(len(d.out))+uint64(n) > d.limit {
+		return fmt.Errorf("zstd: decompressed data exceeds %d bytes", d.limit)
+	}
+	return nil
+}
+
+func (d *decoder) compressedBlock(data []byte) error {
+	n, err := d.readLiterals(data)
+	if err != nil {
+		return err
+	}
+
+	return d.sequences(data[n:])
+}
+
+// readLiterals decodes the literals section at the start of data into
+// d.literals and returns its size.
+func (d *decoder) readLiterals(data []byte) (int, error) {
+	if len(data) < 1 {
+		return 0, corrupt("missing literals section")
+	}
+
+	typ := data[0] & 3
+	sizeFormat := (data[0] >> 2) & 3
+
+	if typ == 0 || typ == 1 {
+		var regenerated, hdrSize int
+		switch sizeFormat {
+		case 0, 2:
+			regenerated, hdrSize = int(data[0]>>3), 1
+		case 1:
+			if len(data) < 2 {
+				return 0, corrupt("truncated literals header")
+			}
+			regenerated, hdrSize = int(data[0]>>4)|int(data[1])<<4, 2
+		case 3:
+			if len(data) < 3 {
+				return 0, corrupt("truncated literals header")
+			}
+			regenerated, hdrSize = int(data[0]>>4)|int(data[1])<<4|int(data[2])<<12, 3
+		}
+		if regenerated > maxBlockSize {
+			return 0, corrupt("too many literals")
+		}
+
+		if typ == 0 {
+			if len(data) < hdrSize+regenerated {
+				return 0, corrupt("truncated raw literals")
+			}
+			d.literals = append(d.literals[:0], data[hdrSize:hdrSize+regenerated]...)
+			return hdrSize + regenerated, nil
+		}
+
+		if len(data) < hdrSize+1 {
+			return 0, corrupt("truncated RLE literals")
+		}
+		d.literals = d.literals[:0]
+		for i := 0; i < regenerated; i++ {
+			d.literals = append(d.literals, data[hdrSize])
+		}
+		return hdrSize + 1, nil
+	}
+
+	var regenerated, compressed, hdrSize int
+	streams := 4
+	switch sizeFormat {
+	case 0, 1:
+		if len(data) < 3 {
+			return 0, corrupt("truncated literals header")
+		}
+		v := int(data[0]) | int(data[1])<<8 | int(data[2])<<16
+		regenerated, compressed, hdrSize = (v>>4)&0x3ff, (v>>14)&0x3ff, 3
+		if sizeFormat == 0 {
+			streams = 1
+		}
+	case 2:
+		if len(data) < 4 {
+			return 0, corrupt("truncated literals header")
+		}
+		v := int(binary.LittleEndian.Uint32(data))
+		regenerated, compressed, hdrSize = (v>>4)&0x3fff, (v>>18)&0x3fff, 4
+	case 3:
+		if len(data) < 5 {
+			return 0, corrupt("truncated literals header")
+		}
+		v := uint64(binary.LittleEndian.Uint32(data)) | uint64(data[4])<<32
+		regenerated, compressed, hdrSize = int(v>>4)&0x3ffff, int(v>>22)&0x3ffff, 5
+	}
+	if regenerated > maxBlockSize {
+		return 0, corrupt("too many literals")
+	}
+	if len(data) < hdrSize+compressed {
+		return 0, corrupt("truncated compressed literals")
+	}
+	lit := data[hdrSize : hdrSize+compressed]
+
+	if typ == 2 {
+		t, n, err := readHuffTable(lit)
+		if err != nil {
+			return 0, err
+		}
+		d.huff = t
+		lit = lit[n:]
+	} else if d.huff == nil {
+		return 0, corrupt("treeless literals without a previous Huffman table")
+	}
+
+	d.literals = d.literals[:0]
+	var err error
+	if streams == 1 {
+		d.literals, err = d.huff.decodeStream(d.literals, lit, regenerated)
+		if err != nil {
+			return 0, err
+		}
+		return hdrSize + compressed, nil
+	}
+
+	if len(lit) < 6 {
+		return 0, corrupt("truncated jump table")
+	}
+	sizes := [4]int{
+		int(binary.LittleEndian.Uint16(lit)),
+		int(binary.LittleEndian.Uint16(lit[2:])),
+		int(binary.LittleEndian.Uint16(lit[4:])),
+	}
+	sizes[3] = len(lit) - 6 - sizes[0] - sizes[1] - sizes[2]
+	if sizes[3] < 0 {
+		return 0, corrupt("invalid jump table")
+	}
+	lit = lit[6:]
+	per := (regenerated + 3) / 4
+	for i, size := range sizes {
+		n := per
+		if i == 3 {
+			n = regenerated - 3*per
+		}
+		if n < 0 {
+			return 0, corrupt("invalid literals size")
+		}
+		d.literals, err = d.huff.decodeStream(d.literals, lit[:size], n)
+		if err != nil {
+			return 0, err
+		}
+		lit = lit[size:]
+	}
+
+	return hdrSize + compressed, nil
+}
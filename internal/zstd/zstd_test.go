@@ -0,0 +1,93 @@
+package zstd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// words returns n bytes of text with enough repetition to exercise every
+// kind of sequence. testdata/words.*.zst hold it compressed by the zstd
+// command at the level in the name.
+func words(n int) []byte {
+	vocab := []string{
+		"section", "segment", "symbol", "header", "offset", "address", "string", "table",
+		"dynamic", "relocation", "note", "version", "hash", "frame", "unwind", "\n",
+	}
+
+	var b []byte
+	x := uint32(1)
+	for len(b) < n {
+		x = x*1664525 + 1013904223
+		b = append(b, vocab[x>>28]...)
+		b = append(b, ' ')
+		if x&0xff < 13 {
+			b = fmt.Appendf(b, "%x ", x)
+		}
+	}
+
+	return b[:n]
+}
+
+func TestDecompress(t *testing.T) {
+	hello, err := os.ReadFile("../../testdata/hello_linux_amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want []byte
+	}{
+		{"testdata/words.1.zst", words(150000)},
+		{"testdata/words.19.zst", words(150000)},
+		{"testdata/hello_linux_amd64.zst", hello},
+	}
+
+	for _, tt := range tests {
+		src, err := os.ReadFile(tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out, err := Decompress(src, uint64(len(tt.want)))
+		if err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+			continue
+		}
+		if !bytes.Equal(out, tt.want) {
+			t.Errorf("%s: output differs (%d bytes, want %d)", tt.name, len(out), len(tt.want))
+		}
+
+		if _, err := Decompress(src, uint64(len(tt.want)-1)); err == nil {
+			t.Errorf("%s: exceeding the limit should fail", tt.name)
+		}
+		for _, n := range []int{0, 3, 10, len(src) / 2, len(src) - 1} {
+			if _, err := Decompress(src[:n], uint64(len(tt.want))); err == nil && n > 0 {
+				t.Errorf("%s: truncated to %d bytes should fail", tt.name, n)
+			}
+		}
+	}
+}
+
+func TestDecompressFrames(t *testing.T) {
+	// A raw block frame, a skippable frame and an RLE block frame.
+	src := []byte{
+		0x28, 0xb5, 0x2f, 0xfd, 0x20, 0x03, 0x19, 0x00, 0x00, 'a', 'b', 'c',
+		0x50, 0x2a, 0x4d, 0x18, 0x02, 0x00, 0x00, 0x00, 0xff, 0xff,
+		0x28, 0xb5, 0x2f, 0xfd, 0x20, 0x04, 0x23, 0x00, 0x00, 'z',
+	}
+
+	out, err := Decompress(src, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "abczzzz" {
+		t.Errorf("have %q, want %q", out, "abczzzz")
+	}
+
+	if _, err := Decompress([]byte("ZLIB\x00\x00\x00\x00"), 16); err == nil {
+		t.Errorf("invalid magic should fail")
+	}
+}
@@ -0,0 +1,117 @@
+package sbom
+
+import (
+	"strconv"
+	"time"
+)
+
+// The subset of the CycloneDX 1.5 JSON schema Document is written as.
+type cdxBOM struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []*cdxComponent `json:"components,omitempty"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+type cdxMetadata struct {
+	Timestamp string        `json:"timestamp"`
+	Tools     cdxTools      `json:"tools"`
+	Component *cdxComponent `json:"component"`
+}
+
+type cdxTools struct {
+	Components []*cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type       string        `json:"type"`
+	BOMRef     string        `json:"bom-ref,omitempty"`
+	Name       string        `json:"name"`
+	Version    string        `json:"version,omitempty"`
+	Scope      string        `json:"scope,omitempty"`
+	PURL       string        `json:"purl,omitempty"`
+	Properties []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// CycloneDX returns d as a CycloneDX 1.5 JSON document. Shared libraries
+// loaded at run time are given the "excluded" scope, and the compilers are
+// recorded as properties of the binary.
+func (d *Document) CycloneDX(created time.Time) ([]byte, error) {
+	id, err := d.documentID(created)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := cdxComponentOf(d.Subject, "subject")
+	for _, c := range d.Compilers {
+		subject.Properties = append(subject.Properties, cdxProperty{Name: toolName + ":compiler", Value: c})
+	}
+
+	bom := &cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + id,
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: created.UTC().Format(time.RFC3339),
+			Tools:     cdxTools{Components: []*cdxComponent{{Type: "application", Name: toolName}}},
+			Component: subject,
+		},
+	}
+
+	// Components are referred to by their PURL, unless it is taken already:
+	// every bom-ref of a document has to be unique.
+	refs := map[string]bool{subject.BOMRef: true}
+	dep := cdxDependency{Ref: subject.BOMRef}
+	for i, c := range d.Components {
+		ref := "component-" + strconv.Itoa(i+1)
+		cc := cdxComponentOf(c, ref)
+		if refs[cc.BOMRef] {
+			cc.BOMRef = ref
+		}
+		refs[cc.BOMRef] = true
+		bom.Components = append(bom.Components, cc)
+		dep.DependsOn = append(dep.DependsOn, cc.BOMRef)
+	}
+	bom.Dependencies = []cdxDependency{dep}
+
+	return marshal(bom)
+}
+
+func cdxComponentOf(c *Component, ref string) *cdxComponent {
+	cc := &cdxComponent{
+		Type:    "library",
+		BOMRef:  ref,
+		Name:    c.Name,
+		Version: c.Version,
+		PURL:    c.PURL,
+	}
+	if c.Type == Application {
+		cc.Type = "application"
+	}
+	if c.PURL != "" {
+		cc.BOMRef = c.PURL
+	}
+	if c.Dynamic {
+		cc.Scope = "excluded"
+		cc.Properties = append(cc.Properties, cdxProperty{Name: toolName + ":linkage", Value: "dynamic"})
+	}
+	if c.Sum != "" {
+		cc.Properties = append(cc.Properties, cdxProperty{Name: toolName + ":go-sum", Value: c.Sum})
+	}
+
+	return cc
+}
@@ -0,0 +1,269 @@
+// Package sbom describes the software components inside an ELF binary as a
+// CycloneDX or SPDX software bill of materials. Components are gathered from
+// the Go build information, the DT_NEEDED shared libraries, the FDO packaging
+// metadata note (.note.package) and the compiler strings of .comment.
+package sbom
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hnts/goelftools/buildinfo"
+	"github.com/hnts/goelftools/elf"
+)
+
+// Format is the document format Generate emits.
+type Format int
+
+const (
+	CycloneDX Format = iota // CycloneDX 1.5 JSON
+	SPDX                    // SPDX 2.3 JSON
+)
+
+// ComponentType classifies a component.
+type ComponentType int
+
+const (
+	Application ComponentType = iota
+	Library
+)
+
+// Component is a piece of software the binary is or contains.
+type Component struct {
+	Type    ComponentType
+	Name    string
+	Version string
+	// PURL is the package URL of the component, if it can be named by one.
+	PURL string
+	// Sum is the go.sum checksum of Go modules.
+	Sum string
+	// Dynamic is set for shared libraries the binary loads at run time
+	// rather than contains.
+	Dynamic bool
+}
+
+// Document is the inventory of a binary.
+type Document struct {
+	// Subject is the binary itself.
+	Subject    *Component
+	Components []*Component
+	// Compilers lists the identification strings compilers left in .comment.
+	Compilers []string
+}
+
+// Options control Generate.
+type Options struct {
+	Format Format
+	// Name names the binary when its metadata does not.
+	Name string
+	// Created is the creation time of the document. It defaults to now.
+	Created time.Time
+}
+
+// toolName identifies this library as the author of the documents.
+const toolName = "goelftools"
+
+// Generate inspects e and returns the bill of materials describing it in the
+// format of opts.
+func Generate(e *elf.File, opts Options) ([]byte, error) {
+	d, err := Inspect(e, opts.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	created := opts.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+
+	switch opts.Format {
+	case CycloneDX:
+		return d.CycloneDX(created)
+	case SPDX:
+		return d.SPDX(created)
+	}
+
+	return nil, fmt.Errorf("unknown sbom format: %d", opts.Format)
+}
+
+// Inspect gathers the components of e. The binary is named after its
+// package metadata, its Go main module or its DT_SONAME, in that order, and
+// otherwise after name.
+func Inspect(e *elf.File, name string) (*Document, error) {
+	d := &Document{Subject: &Component{Type: Application}}
+
+	pm, err := e.PackageMetadata()
+	if err != nil {
+		return nil, err
+	}
+	if pm != nil {
+		d.Subject.Name, d.Subject.Version = pm.Name, pm.Version
+		d.Subject.PURL = packagePURL(pm)
+	}
+
+	bi, err := buildinfo.Read(e)
+	if err != nil {
+		return nil, err
+	}
+	if bi != nil {
+		main := goModule(&bi.Main)
+		if d.Subject.Name == "" {
+			d.Subject.Name, d.Subject.Version, d.Subject.PURL = main.Name, main.Version, main.PURL
+		} else if main.Name != "" {
+			d.Components = append(d.Components, main)
+		}
+		for _, dep := range bi.Deps {
+			d.Components = append(d.Components, goModule(dep))
+		}
+		std := &Component{Type: Library, Name: "stdlib", Version: strings.TrimPrefix(bi.GoVersion, "go")}
+		std.PURL = purl("golang", "", std.Name, std.Version)
+		d.Components = append(d.Components, std)
+	}
+
+	soname, err := e.Soname()
+	if err != nil {
+		return nil, err
+	}
+	if d.Subject.Name == "" && soname != "" {
+		d.Subject.Type, d.Subject.Name = Library, soname
+	}
+	if d.Subject.Name == "" {
+		d.Subject.Name = name
+	}
+	if d.Subject.Name == "" {
+		return nil, fmt.Errorf("no name found for the binary")
+	}
+
+	needed, err := e.Needed()
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, lib := range needed {
+		if !seen[lib] {
+			seen[lib] = true
+			d.Components = append(d.Components, &Component{Type: Library, Name: lib, Dynamic: true})
+		}
+	}
+
+	if s := e.SectionByName(".comment"); s != nil && s.Header.Type == elf.SHT_PROGBITS {
+		data, err := s.Data()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read .comment: %w", err)
+		}
+		for _, c := range bytes.Split(data, []byte{0}) {
+			if len(c) > 0 && !slices.Contains(d.Compilers, string(c)) {
+				d.Compilers = append(d.Compilers, string(c))
+			}
+		}
+	}
+
+	return d, nil
+}
+
+// goModule describes a Go module, or its replacement if it has one.
+func goModule(m *buildinfo.Module) *Component {
+	name := m.Path
+	if m.Replace != nil {
+		m = m.Replace
+		// A replacement by a local directory has no module path.
+		if !strings.HasPrefix(m.Path, ".") && !strings.HasPrefix(m.Path, "/") {
+			name = m.Path
+		}
+	}
+
+	c := &Component{Type: Library, Name: name, Version: m.Version, Sum: m.Sum}
+	if c.Version == "(devel)" {
+		c.Version = ""
+	}
+	namespace, base := path.Split(name)
+	c.PURL = purl("golang", strings.TrimSuffix(namespace, "/"), base, c.Version)
+
+	return c
+}
+
+// packagePURL returns the package URL of the distribution package described
+// by the packaging metadata.
+func packagePURL(pm *elf.PackageMetadata) string {
+	if pm.Type == "" || pm.Name == "" {
+		return ""
+	}
+
+	u := purl(strings.ToLower(pm.Type), strings.ToLower(pm.OS), pm.Name, pm.Version)
+	var qs []string
+	if pm.Architecture != "" {
+		qs = append(qs, "arch="+escapePURL(pm.Architecture))
+	}
+	if pm.OS != "" && pm.OSVersion != "" {
+		qs = append(qs, "distro="+escapePURL(pm.OS+"-"+pm.OSVersion))
+	}
+	if len(qs) > 0 {
+		u += "?" + strings.Join(qs, "&")
+	}
+
+	return u
+}
+
+// purl formats a package URL without qualifiers. The namespace may hold
+// several segments separated by slashes.
+func purl(typ, namespace, name, version string) string {
+	var b strings.Builder
+	b.WriteString("pkg:" + typ + "/")
+	if namespace != "" {
+		for _, seg := range strings.Split(namespace, "/") {
+			b.WriteString(escapePURL(seg) + "/")
+		}
+	}
+	b.WriteString(escapePURL(name))
+	if version != "" {
+		b.WriteString("@" + escapePURL(version))
+	}
+
+	return b.String()
+}
+
+// escapePURL percent-encodes every character of s but the unreserved ones.
+func escapePURL(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("-._~", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+// documentID derives a UUID from the contents of the document and its
+// creation time, so that the same binary and time give the same document.
+func (d *Document) documentID(created time.Time) (string, error) {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.Sum256(append(b, created.UTC().Format(time.RFC3339)...))
+	h[6] = h[6]&0x0f | 0x80 // version 8, custom
+	h[8] = h[8]&0x3f | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", h[0:4], h[4:6], h[6:8], h[8:10], h[10:16]), nil
+}
+
+// marshal encodes documents the way both formats are written.
+func marshal(v any) ([]byte, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
@@ -0,0 +1,300 @@
+package sbom_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hnts/goelftools/elf"
+	"github.com/hnts/goelftools/sbom"
+)
+
+const gcc = "GCC: (Debian 12.2.0-14+deb12u1) 12.2.0"
+
+var created = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+func TestInspect(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		want *sbom.Document
+	}{
+		{
+			"../testdata/hello_linux_amd64",
+			&sbom.Document{
+				Subject: &sbom.Component{Type: sbom.Application, Name: "hello", Version: "1.0-1", PURL: "pkg:deb/debian/hello@1.0-1?arch=amd64&distro=debian-12"},
+				Components: []*sbom.Component{
+					{Type: sbom.Library, Name: "libc.so.6", Dynamic: true},
+				},
+				Compilers: []string{gcc},
+			},
+		},
+		{
+			"../testdata/libsample_linux_amd64.so",
+			&sbom.Document{
+				Subject: &sbom.Component{Type: sbom.Library, Name: "libsample.so.1"},
+				Components: []*sbom.Component{
+					{Type: sbom.Library, Name: "libm.so.6", Dynamic: true},
+					{Type: sbom.Library, Name: "libc.so.6", Dynamic: true},
+				},
+				Compilers: []string{gcc},
+			},
+		},
+		{
+			"../testdata/gohello_linux_amd64",
+			&sbom.Document{
+				Subject: &sbom.Component{Type: sbom.Application, Name: "example.com/hello", PURL: "pkg:golang/example.com/hello"},
+				Components: []*sbom.Component{
+					{Type: sbom.Library, Name: "golang.org/x/sync", Version: "v0.10.0", PURL: "pkg:golang/golang.org/x/sync@v0.10.0", Sum: "h1:3NQrjDixjgGwUOCaF8w2+VYHv0Ve/vGYSbdkTa98gmQ="},
+					{Type: sbom.Library, Name: "stdlib", Version: "1.27.1", PURL: "pkg:golang/stdlib@1.27.1"},
+				},
+			},
+		},
+	} {
+		d, err := sbom.Inspect(mustNew(t, tt.name), "")
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if !reflect.DeepEqual(d, tt.want) {
+			t.Errorf("%s:\n\thave %#v\n\twant %#v\n", tt.name, d, tt.want)
+		}
+	}
+}
+
+func TestInspectName(t *testing.T) {
+	b := elf.NewBuilder(elf.ELFCLASS64, binary.LittleEndian, elf.EM_X86_64, elf.ET_EXEC)
+	b.AddSection(".text", elf.SectionHeader{Type: elf.SHT_PROGBITS, Flags: elf.SHF_ALLOC | elf.SHF_EXECINSTR, Addr: 0x401000}, []byte{0xc3})
+	raw, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sbom.Inspect(e, ""); err == nil {
+		t.Errorf("a binary without a name should fail")
+	}
+	d, err := sbom.Inspect(e, "tool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&sbom.Component{Type: sbom.Application, Name: "tool"}); !reflect.DeepEqual(d.Subject, want) || d.Components != nil {
+		t.Errorf("have %#v with %d components", d.Subject, len(d.Components))
+	}
+}
+
+func TestGenerateCycloneDX(t *testing.T) {
+	e := mustNew(t, "../testdata/hello_linux_amd64")
+	raw := mustGenerate(t, e, sbom.CycloneDX)
+
+	var bom struct {
+		BOMFormat    string `json:"bomFormat"`
+		SpecVersion  string `json:"specVersion"`
+		SerialNumber string `json:"serialNumber"`
+		Metadata     struct {
+			Timestamp string   `json:"timestamp"`
+			Component cdxEntry `json:"component"`
+		} `json:"metadata"`
+		Components   []cdxEntry `json:"components"`
+		Dependencies []struct {
+			Ref       string   `json:"ref"`
+			DependsOn []string `json:"dependsOn"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(raw, &bom); err != nil {
+		t.Fatal(err)
+	}
+
+	if bom.BOMFormat != "CycloneDX" || bom.SpecVersion != "1.5" || bom.Metadata.Timestamp != "2026-10-16T12:00:00Z" {
+		t.Errorf("have header %q %q %q", bom.BOMFormat, bom.SpecVersion, bom.Metadata.Timestamp)
+	}
+	if !regexp.MustCompile(`^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-8[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(bom.SerialNumber) {
+		t.Errorf("have serial number %q", bom.SerialNumber)
+	}
+
+	purl := "pkg:deb/debian/hello@1.0-1?arch=amd64&distro=debian-12"
+	wantSubject := cdxEntry{
+		Type: "application", BOMRef: purl, Name: "hello", Version: "1.0-1", PURL: purl,
+		Properties: []cdxProperty{{"goelftools:compiler", gcc}},
+	}
+	if !reflect.DeepEqual(bom.Metadata.Component, wantSubject) {
+		t.Errorf("subject:\n\thave %#v\n\twant %#v\n", bom.Metadata.Component, wantSubject)
+	}
+	wantComponents := []cdxEntry{{
+		Type: "library", BOMRef: "component-1", Name: "libc.so.6", Scope: "excluded",
+		Properties: []cdxProperty{{"goelftools:linkage", "dynamic"}},
+	}}
+	if !reflect.DeepEqual(bom.Components, wantComponents) {
+		t.Errorf("components:\n\thave %#v\n\twant %#v\n", bom.Components, wantComponents)
+	}
+	if len(bom.Dependencies) != 1 || bom.Dependencies[0].Ref != purl || !reflect.DeepEqual(bom.Dependencies[0].DependsOn, []string{"component-1"}) {
+		t.Errorf("have dependencies %#v", bom.Dependencies)
+	}
+
+	if again := mustGenerate(t, e, sbom.CycloneDX); !bytes.Equal(raw, again) {
+		t.Errorf("documents generated at the same time differ")
+	}
+}
+
+func TestCycloneDXDuplicatePURLs(t *testing.T) {
+	sync := "pkg:golang/golang.org/x/sync@v0.10.0"
+	d := &sbom.Document{
+		Subject: &sbom.Component{Type: sbom.Application, Name: "example.com/hello", PURL: "pkg:golang/example.com/hello"},
+		Components: []*sbom.Component{
+			{Type: sbom.Library, Name: "example.com/hello", PURL: "pkg:golang/example.com/hello"},
+			{Type: sbom.Library, Name: "golang.org/x/sync", Version: "v0.10.0", PURL: sync},
+			{Type: sbom.Library, Name: "golang.org/x/sync", Version: "v0.10.0", PURL: sync},
+		},
+	}
+	raw, err := d.CycloneDX(created)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var bom struct {
+		Components []cdxEntry `json:"components"`
+	}
+	if err := json.Unmarshal(raw, &bom); err != nil {
+		t.Fatal(err)
+	}
+	var refs []string
+	for _, c := range bom.Components {
+		refs = append(refs, c.BOMRef)
+	}
+	if want := []string{"component-1", sync, "component-3"}; !reflect.DeepEqual(refs, want) {
+		t.Errorf("have refs %q, want %q", refs, want)
+	}
+}
+
+func TestGenerateSPDX(t *testing.T) {
+	raw := mustGenerate(t, mustNew(t, "../testdata/gohello_linux_amd64"), sbom.SPDX)
+
+	var doc struct {
+		SPDXVersion       string `json:"spdxVersion"`
+		DataLicense       string `json:"dataLicense"`
+		SPDXID            string `json:"SPDXID"`
+		Name              string `json:"name"`
+		DocumentNamespace string `json:"documentNamespace"`
+		CreationInfo      struct {
+			Created  string   `json:"created"`
+			Creators []string `json:"creators"`
+		} `json:"creationInfo"`
+		Packages []struct {
+			Name         string `json:"name"`
+			SPDXID       string `json:"SPDXID"`
+			VersionInfo  string `json:"versionInfo"`
+			ExternalRefs []struct {
+				ReferenceType    string `json:"referenceType"`
+				ReferenceLocator string `json:"referenceLocator"`
+			} `json:"externalRefs"`
+			PrimaryPackagePurpose string `json:"primaryPackagePurpose"`
+		} `json:"packages"`
+		Relationships []struct {
+			SPDXElementID      string `json:"spdxElementId"`
+			RelationshipType   string `json:"relationshipType"`
+			RelatedSPDXElement string `json:"relatedSpdxElement"`
+		} `json:"relationships"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.SPDXVersion != "SPDX-2.3" || doc.DataLicense != "CC0-1.0" || doc.SPDXID != "SPDXRef-DOCUMENT" || doc.Name != "example.com/hello" {
+		t.Errorf("have header %q %q %q %q", doc.SPDXVersion, doc.DataLicense, doc.SPDXID, doc.Name)
+	}
+	if !regexp.MustCompile(`^https://spdx\.org/spdxdocs/example\.com%2Fhello-[0-9a-f-]{36}$`).MatchString(doc.DocumentNamespace) {
+		t.Errorf("have namespace %q", doc.DocumentNamespace)
+	}
+	if doc.CreationInfo.Created != "2026-10-16T12:00:00Z" || !reflect.DeepEqual(doc.CreationInfo.Creators, []string{"Tool: goelftools"}) {
+		t.Errorf("have creation info %#v", doc.CreationInfo)
+	}
+
+	var names, purls, purposes []string
+	for _, p := range doc.Packages {
+		names = append(names, p.SPDXID+" "+p.Name+" "+p.VersionInfo)
+		purposes = append(purposes, p.PrimaryPackagePurpose)
+		for _, r := range p.ExternalRefs {
+			purls = append(purls, r.ReferenceType+" "+r.ReferenceLocator)
+		}
+	}
+	wantNames := []string{
+		"SPDXRef-Package-0 example.com/hello ",
+		"SPDXRef-Package-1 golang.org/x/sync v0.10.0",
+		"SPDXRef-Package-2 stdlib 1.27.1",
+	}
+	wantPURLs := []string{
+		"purl pkg:golang/example.com/hello",
+		"purl pkg:golang/golang.org/x/sync@v0.10.0",
+		"purl pkg:golang/stdlib@1.27.1",
+	}
+	if !reflect.DeepEqual(names, wantNames) || !reflect.DeepEqual(purls, wantPURLs) ||
+		!reflect.DeepEqual(purposes, []string{"APPLICATION", "LIBRARY", "LIBRARY"}) {
+		t.Errorf("have packages %q, %q, %q", names, purls, purposes)
+	}
+
+	var rels []string
+	for _, r := range doc.Relationships {
+		rels = append(rels, r.SPDXElementID+" "+r.RelationshipType+" "+r.RelatedSPDXElement)
+	}
+	wantRels := []string{
+		"SPDXRef-DOCUMENT DESCRIBES SPDXRef-Package-0",
+		"SPDXRef-Package-0 STATIC_LINK SPDXRef-Package-1",
+		"SPDXRef-Package-0 STATIC_LINK SPDXRef-Package-2",
+	}
+	if !reflect.DeepEqual(rels, wantRels) {
+		t.Errorf("relationships:\n\thave %#v\n\twant %#v\n", rels, wantRels)
+	}
+
+	// Shared libraries are linked dynamically.
+	raw = mustGenerate(t, mustNew(t, "../testdata/libsample_linux_amd64.so"), sbom.SPDX)
+	if !bytes.Contains(raw, []byte(`"relationshipType": "DYNAMIC_LINK"`)) {
+		t.Errorf("missing DYNAMIC_LINK relationship:\n%s", raw)
+	}
+}
+
+type cdxEntry struct {
+	Type       string        `json:"type"`
+	BOMRef     string        `json:"bom-ref"`
+	Name       string        `json:"name"`
+	Version    string        `json:"version"`
+	Scope      string        `json:"scope"`
+	PURL       string        `json:"purl"`
+	Properties []cdxProperty `json:"properties"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func mustNew(t *testing.T, name string) *elf.File {
+	t.Helper()
+
+	raw, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return e
+}
+
+func mustGenerate(t *testing.T, e *elf.File, f sbom.Format) []byte {
+	t.Helper()
+
+	raw, err := sbom.Generate(e, sbom.Options{Format: f, Created: created})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return raw
+}
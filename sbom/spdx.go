@@ -0,0 +1,118 @@
+package sbom
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The subset of the SPDX 2.3 JSON schema Document is written as.
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []*spdxPackage     `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name                  string            `json:"name"`
+	SPDXID                string            `json:"SPDXID"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose"`
+	Comment               string            `json:"comment,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// spdxNamespace prefixes the document namespaces, which only need to be
+// unique URIs.
+const spdxNamespace = "https://spdx.org/spdxdocs/"
+
+// SPDX returns d as an SPDX 2.3 JSON document. The binary is the package the
+// document describes; it is related to shared libraries loaded at run time
+// by DYNAMIC_LINK and to the other components by STATIC_LINK. The compilers
+// are recorded in the comment of the binary.
+func (d *Document) SPDX(created time.Time) ([]byte, error) {
+	id, err := d.documentID(created)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := spdxPackageOf(d.Subject, "SPDXRef-Package-0")
+	if len(d.Compilers) > 0 {
+		subject.Comment = "Compilers: " + strings.Join(d.Compilers, "; ")
+	}
+
+	doc := &spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              d.Subject.Name,
+		DocumentNamespace: spdxNamespace + escapePURL(d.Subject.Name) + "-" + id,
+		CreationInfo: spdxCreationInfo{
+			Created:  created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: " + toolName},
+		},
+		Packages: []*spdxPackage{subject},
+		Relationships: []spdxRelationship{
+			{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: subject.SPDXID},
+		},
+	}
+
+	for i, c := range d.Components {
+		p := spdxPackageOf(c, "SPDXRef-Package-"+strconv.Itoa(i+1))
+		doc.Packages = append(doc.Packages, p)
+
+		rel := "STATIC_LINK"
+		if c.Dynamic {
+			rel = "DYNAMIC_LINK"
+		}
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      subject.SPDXID,
+			RelationshipType:   rel,
+			RelatedSPDXElement: p.SPDXID,
+		})
+	}
+
+	return marshal(doc)
+}
+
+func spdxPackageOf(c *Component, id string) *spdxPackage {
+	p := &spdxPackage{
+		Name:                  c.Name,
+		SPDXID:                id,
+		VersionInfo:           c.Version,
+		DownloadLocation:      "NOASSERTION",
+		PrimaryPackagePurpose: "LIBRARY",
+	}
+	if c.Type == Application {
+		p.PrimaryPackagePurpose = "APPLICATION"
+	}
+	if c.PURL != "" {
+		p.ExternalRefs = []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: c.PURL}}
+	}
+
+	return p
+}
@@ -0,0 +1,22 @@
+// crash_linux_amd64 is built from this file with:
+//
+//	gcc -O0 -pthread -o crash_linux_amd64 crash.c
+//
+// and core_linux_amd64.gz is its core dump, compressed with gzip -9. The main
+// thread faults reading address 0x10 while a second thread waits in pause.
+#include <pthread.h>
+#include <unistd.h>
+
+static void *idle(void *arg) {
+	for (;;)
+		pause();
+	return arg;
+}
+
+int main(void) {
+	pthread_t t;
+	pthread_create(&t, NULL, idle, NULL);
+	usleep(10000);
+	volatile int *p = (int *)0x10;
+	return *p;
+}
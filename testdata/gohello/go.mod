@@ -0,0 +1,5 @@
+module example.com/hello
+
+go 1.27
+
+require golang.org/x/sync v0.10.0
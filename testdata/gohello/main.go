@@ -0,0 +1,15 @@
+// gohello_linux_amd64 is built from this module with Go 1.27.1:
+//
+//	CGO_ENABLED=0 GOOS=linux GOARCH=amd64 go build -trimpath -ldflags='-s -w' -o ../gohello_linux_amd64 .
+package main
+
+import "golang.org/x/sync/errgroup"
+
+func main() {
+	var g errgroup.Group
+	g.Go(func() error {
+		println("Hello World")
+		return nil
+	})
+	g.Wait()
+}
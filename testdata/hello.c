@@ -0,0 +1,16 @@
+// hello_linux_amd64 is built from this file with:
+//
+//	gcc -O2 -fPIE -pie -fstack-protector-strong -D_FORTIFY_SOURCE=2 -fcf-protection=full \
+//	    -Wl,-z,relro,-z,now,-z,ibt,-z,shstk -Wl,--hash-style=both -Wl,--build-id=sha1 \
+//	    -Xlinker '--package-metadata={"type":"deb","name":"hello","version":"1.0-1","architecture":"amd64","os":"debian","osVersion":"12"}' \
+//	    -o hello_linux_amd64 hello.c
+#include <stdio.h>
+#include <string.h>
+
+int main(int argc, char **argv) {
+	char buf[32];
+
+	strcpy(buf, argc > 1 ? argv[1] : "world");
+	printf("Hello %s\n", buf);
+	return 0;
+}
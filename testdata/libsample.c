@@ -0,0 +1,25 @@
+// libsample_linux_amd64.so is built from this file with:
+//
+//	gcc -O2 -shared -fPIC -o libsample_linux_amd64.so -Wl,-soname,libsample.so.1 \
+//	    -Wl,-rpath,'$ORIGIN/../lib' -Wl,--enable-new-dtags -Wl,-z,now libsample.c -lm
+//
+// libsample_linux_amd64_sysv.so is built the same way with -Wl,--hash-style=sysv added,
+// and libsample_linux_amd64.o with:
+//
+//	gcc -c -O2 -fPIC -o libsample_linux_amd64.o libsample.c
+//
+// The objects with debug information are built with:
+//
+//	gcc -c -g -O2 -fPIC -o libsample_linux_amd64_debug.o libsample.c
+//	objcopy --compress-debug-sections=zlib-gabi libsample_linux_amd64_debug.o libsample_linux_amd64_debug_zlib.o
+//	objcopy --compress-debug-sections=zstd libsample_linux_amd64_debug.o libsample_linux_amd64_debug_zstd.o
+#include <math.h>
+#include <stdio.h>
+
+static int initialized;
+
+__attribute__((constructor)) static void sample_init(void) { initialized = 1; }
+
+double sample_hypot(double x, double y) { return sqrt(x * x + y * y); }
+
+void sample_print(const char *s) { printf("%s %d\n", s, initialized); }
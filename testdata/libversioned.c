@@ -0,0 +1,19 @@
+// libversioned_linux_amd64.so is built from this file and libversioned.map with:
+//
+//	gcc -O2 -shared -fPIC -o libversioned_linux_amd64.so -Wl,-soname,libversioned.so.1 \
+//	    -Wl,--version-script=libversioned.map libversioned.c -lm
+//
+// counter is defined in two versions, the old one being hidden.
+#include <math.h>
+#include <stdio.h>
+
+__asm__(".symver counter_v1, counter@LIBV_1.0");
+__asm__(".symver counter_v2, counter@@LIBV_2.0");
+
+int counter_v1(void) { return 1; }
+
+int counter_v2(void) { return 2; }
+
+void greet(const char *who, double x) {
+	printf("hello %s, %f\n", who, sqrt(x));
+}
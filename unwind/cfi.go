@@ -0,0 +1,498 @@
+// Package unwind unwinds stacks with the call frame information of an ELF
+// file: the CIEs and FDEs of .eh_frame and .debug_frame, found through the
+// binary search table of .eh_frame_hdr when there is one. The rules of an
+// FDE give the canonical frame address (CFA) of a frame and where the
+// registers of its caller are saved, which Step evaluates for x86-64 and
+// AArch64.
+package unwind
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// CIE is a common information entry, the part of the call frame information
+// shared by the FDEs referring to it.
+type CIE struct {
+	Version      uint8
+	Augmentation string
+	// AddressSize is the size of target addresses, recorded by version 4
+	// CIEs of .debug_frame.
+	AddressSize uint8
+	CodeAlign   uint64
+	DataAlign   int64
+	// RA is the column of the return address.
+	RA uint64
+	// FDEEncoding and LSDAEncoding are the DW_EH_PE pointer encodings of the
+	// addresses of the FDEs and of their language specific data areas.
+	FDEEncoding  uint8
+	LSDAEncoding uint8
+	// Personality is the address of the personality routine, or of the
+	// pointer to it when the encoding is indirect.
+	Personality uint64
+	// SignalFrame is set for the frames of signal handlers, whose program
+	// counter is not a return address.
+	SignalFrame bool
+	// Instructions are the initial call frame instructions of every FDE.
+	Instructions []byte
+}
+
+// FDE is a frame description entry, the call frame information of the
+// addresses from Begin up to End.
+type FDE struct {
+	CIE        *CIE
+	Begin, End uint64
+	// LSDA is the address of the language specific data area, such as the
+	// exception table of C++ functions.
+	LSDA         uint64
+	Instructions []byte
+
+	sec *section
+	// insnOff is the offset of Instructions in the section, which
+	// DW_CFA_set_loc operands are relative to.
+	insnOff uint64
+}
+
+// Pointer encodings of .eh_frame and .eh_frame_hdr. The low bits give the
+// format of the value and the high bits what it is relative to.
+const (
+	ptrAbs     = 0x00
+	ptrULEB128 = 0x01
+	ptrUData2  = 0x02
+	ptrUData4  = 0x03
+	ptrUData8  = 0x04
+	ptrSLEB128 = 0x09
+	ptrSData2  = 0x0a
+	ptrSData4  = 0x0b
+	ptrSData8  = 0x0c
+
+	ptrPCRel    = 0x10
+	ptrTextRel  = 0x20
+	ptrDataRel  = 0x30
+	ptrFuncRel  = 0x40
+	ptrAligned  = 0x50
+	ptrIndirect = 0x80
+	ptrOmit     = 0xff
+)
+
+// section is a .eh_frame or .debug_frame section.
+type section struct {
+	data    []byte
+	addr    uint64
+	eh      bool
+	order   binary.ByteOrder
+	ptrSize int
+	cies    map[uint64]*CIE
+}
+
+func newSection(data []byte, addr uint64, eh bool, order binary.ByteOrder, ptrSize int) *section {
+	return &section{data: data, addr: addr, eh: eh, order: order, ptrSize: ptrSize, cies: map[uint64]*CIE{}}
+}
+
+// ParseEHFrame decodes the FDEs of an .eh_frame section loaded at addr, in
+// a file of the given byte order and pointer size.
+func ParseEHFrame(data []byte, addr uint64, order binary.ByteOrder, ptrSize int) ([]*FDE, error) {
+	return newSection(data, addr, true, order, ptrSize).fdes()
+}
+
+// ParseDebugFrame decodes the FDEs of a .debug_frame section.
+func ParseDebugFrame(data []byte, order binary.ByteOrder, ptrSize int) ([]*FDE, error) {
+	return newSection(data, 0, false, order, ptrSize).fdes()
+}
+
+func (s *section) fdes() ([]*FDE, error) {
+	var fdes []*FDE
+	for off := uint64(0); off < uint64(len(s.data)); {
+		fde, next, err := s.entryAt(off)
+		if err != nil {
+			return nil, err
+		}
+		// The terminator of .eh_frame ends the section, whatever follows.
+		if next == 0 {
+			break
+		}
+		if fde != nil {
+			fdes = append(fdes, fde)
+		}
+		off = next
+	}
+
+	return fdes, nil
+}
+
+// entryAt decodes the entry at off, returning it if it is an FDE, and the
+// offset of the next entry. A next offset of 0 is returned for the
+// zero-length entry terminating .eh_frame.
+func (s *section) entryAt(off uint64) (*FDE, uint64, error) {
+	r := s.reader(off)
+	length, dwarf64 := uint64(r.u32()), false
+	if length == 0xffffffff {
+		length, dwarf64 = r.u64(), true
+	}
+	if r.err != nil {
+		return nil, 0, fmt.Errorf("invalid entry at offset %#x: %w", off, r.err)
+	}
+	if length == 0 && s.eh {
+		return nil, 0, nil
+	}
+	start := r.off
+	if length > uint64(len(s.data))-start {
+		return nil, 0, fmt.Errorf("entry at offset %#x with length %d exceeds the section", off, length)
+	}
+	end := start + length
+	r.data = s.data[:end]
+
+	idOff := r.off
+	var id uint64
+	if dwarf64 {
+		id = r.u64()
+	} else {
+		id = uint64(r.u32())
+	}
+	if r.err != nil {
+		return nil, 0, fmt.Errorf("invalid entry at offset %#x: %w", off, r.err)
+	}
+
+	var cieOff uint64
+	switch {
+	case s.eh && id == 0, !s.eh && (id == 0xffffffff || dwarf64 && id == ^uint64(0)):
+		if _, err := s.cieAt(off); err != nil {
+			return nil, 0, err
+		}
+		return nil, end, nil
+	case s.eh:
+		// The CIE pointer of .eh_frame is relative to its own position.
+		cieOff = idOff - id
+	default:
+		cieOff = id
+	}
+
+	cie, err := s.cieAt(cieOff)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid cie of fde at offset %#x: %w", off, err)
+	}
+	fde, err := s.parseFDE(r, cie)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid fde at offset %#x: %w", off, err)
+	}
+
+	return fde, end, nil
+}
+
+func (s *section) parseFDE(r *reader, cie *CIE) (*FDE, error) {
+	fde := &FDE{CIE: cie, sec: s}
+	if s.eh {
+		fde.Begin = s.pointer(r, cie.FDEEncoding, 0)
+		// The range is a length, only sharing the format of the address.
+		fde.End = fde.Begin + s.pointer(r, cie.FDEEncoding&0x0f, 0)
+	} else {
+		fde.Begin = r.addr(s.addrSize(cie))
+		fde.End = fde.Begin + r.addr(s.addrSize(cie))
+	}
+
+	if strings.HasPrefix(cie.Augmentation, "z") {
+		n := r.uleb()
+		augEnd := r.off + n
+		if strings.Contains(cie.Augmentation, "L") && cie.LSDAEncoding != ptrOmit {
+			fde.LSDA = s.pointer(r, cie.LSDAEncoding, 0)
+		}
+		r.off = augEnd
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.off > uint64(len(r.data)) {
+		return nil, fmt.Errorf("augmentation data exceeds the entry")
+	}
+
+	fde.insnOff = r.off
+	fde.Instructions = r.data[r.off:]
+
+	return fde, nil
+}
+
+// cieAt returns the CIE at off, decoding it the first time.
+func (s *section) cieAt(off uint64) (*CIE, error) {
+	if cie, ok := s.cies[off]; ok {
+		return cie, nil
+	}
+
+	r := s.reader(off)
+	length, dwarf64 := uint64(r.u32()), false
+	if length == 0xffffffff {
+		length, dwarf64 = r.u64(), true
+	}
+	if r.err != nil || length == 0 || length > uint64(len(s.data))-r.off {
+		return nil, fmt.Errorf("invalid cie at offset %#x", off)
+	}
+	r.data = s.data[:r.off+length]
+
+	var id uint64
+	if dwarf64 {
+		id = r.u64()
+	} else {
+		id = uint64(r.u32())
+	}
+	if s.eh && id != 0 || !s.eh && id != 0xffffffff && id != ^uint64(0) {
+		return nil, fmt.Errorf("entry at offset %#x is not a cie", off)
+	}
+
+	cie, err := s.parseCIE(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cie at offset %#x: %w", off, err)
+	}
+	s.cies[off] = cie
+
+	return cie, nil
+}
+
+func (s *section) parseCIE(r *reader) (*CIE, error) {
+	cie := &CIE{Version: r.u8(), Augmentation: r.cstring()}
+	switch cie.Version {
+	case 1, 3, 4:
+	default:
+		return nil, fmt.Errorf("unsupported cie version: %d", cie.Version)
+	}
+
+	if strings.Contains(cie.Augmentation, "eh") {
+		// The address of the exception table of old GCC versions.
+		r.addr(s.ptrSize)
+	}
+	if cie.Version >= 4 {
+		cie.AddressSize = r.u8()
+		r.u8() // segment selector size
+	}
+	cie.CodeAlign = r.uleb()
+	cie.DataAlign = r.sleb()
+	if cie.Version == 1 {
+		cie.RA = uint64(r.u8())
+	} else {
+		cie.RA = r.uleb()
+	}
+
+	if strings.HasPrefix(cie.Augmentation, "z") {
+		n := r.uleb()
+		augEnd := r.off + n
+	aug:
+		for _, c := range cie.Augmentation[1:] {
+			switch c {
+			case 'L':
+				cie.LSDAEncoding = r.u8()
+			case 'P':
+				enc := r.u8()
+				cie.Personality = s.pointer(r, enc, 0)
+			case 'R':
+				cie.FDEEncoding = r.u8()
+			case 'S':
+				cie.SignalFrame = true
+			case 'B', 'G':
+				// AArch64 pointer authentication with the B key and
+				// memory tagged stack frames, which take no data.
+			default:
+				// The data of unknown augmentations is skipped.
+				break aug
+			}
+		}
+		r.off = augEnd
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.off > uint64(len(r.data)) {
+		return nil, fmt.Errorf("augmentation data exceeds the entry")
+	}
+	cie.Instructions = r.data[r.off:]
+
+	return cie, nil
+}
+
+func (s *section) addrSize(cie *CIE) int {
+	if cie.AddressSize != 0 {
+		return int(cie.AddressSize)
+	}
+	return s.ptrSize
+}
+
+func (s *section) reader(off uint64) *reader {
+	return &reader{data: s.data, off: off, order: s.order}
+}
+
+// pointer decodes a pointer encoded with enc at the position of r. Data
+// relative pointers are relative to dataBase. Indirect pointers are
+// returned as the address holding the pointer.
+func (s *section) pointer(r *reader, enc uint8, dataBase uint64) uint64 {
+	if enc == ptrOmit {
+		return 0
+	}
+
+	pos := s.addr + r.off
+	if enc&0x70 == ptrAligned {
+		r.off = (r.off + uint64(s.ptrSize) - 1) &^ (uint64(s.ptrSize) - 1)
+	}
+
+	var v uint64
+	switch enc & 0x0f {
+	case ptrAbs:
+		v = r.addr(s.ptrSize)
+	case ptrULEB128:
+		v = r.uleb()
+	case ptrUData2:
+		v = uint64(r.u16())
+	case ptrUData4:
+		v = uint64(r.u32())
+	case ptrUData8:
+		v = r.u64()
+	case ptrSLEB128:
+		v = uint64(r.sleb())
+	case ptrSData2:
+		v = uint64(int16(r.u16()))
+	case ptrSData4:
+		v = uint64(int32(r.u32()))
+	case ptrSData8:
+		v = r.u64()
+	default:
+		r.fail(fmt.Errorf("unsupported pointer encoding: %#x", enc))
+		return 0
+	}
+
+	switch enc & 0x70 {
+	case ptrAbs, ptrAligned:
+	case ptrPCRel:
+		v += pos
+	case ptrDataRel:
+		v += dataBase
+	default:
+		r.fail(fmt.Errorf("unsupported pointer encoding: %#x", enc))
+		return 0
+	}
+	if s.ptrSize == 4 {
+		v = uint64(uint32(v))
+	}
+
+	return v
+}
+
+// reader decodes the fields of call frame information. The first error is
+// kept in err and the values read after it are zero.
+type reader struct {
+	data  []byte
+	off   uint64
+	order binary.ByteOrder
+	err   error
+}
+
+func (r *reader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *reader) bytes(n uint64) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if r.off > uint64(len(r.data)) || n > uint64(len(r.data))-r.off {
+		r.fail(fmt.Errorf("unexpected end of data at offset %#x", r.off))
+		return nil
+	}
+	b := r.data[r.off : r.off+n]
+	r.off += n
+
+	return b
+}
+
+func (r *reader) u8() uint8 {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *reader) u16() uint16 {
+	if b := r.bytes(2); b != nil {
+		return r.order.Uint16(b)
+	}
+	return 0
+}
+
+func (r *reader) u32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return r.order.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) u64() uint64 {
+	if b := r.bytes(8); b != nil {
+		return r.order.Uint64(b)
+	}
+	return 0
+}
+
+func (r *reader) addr(size int) uint64 {
+	switch size {
+	case 2:
+		return uint64(r.u16())
+	case 4:
+		return uint64(r.u32())
+	case 8:
+		return r.u64()
+	}
+
+	r.fail(fmt.Errorf("unsupported address size: %d", size))
+	return 0
+}
+
+func (r *reader) uleb() uint64 {
+	var v uint64
+	for shift := uint(0); ; shift += 7 {
+		b := r.u8()
+		if r.err != nil {
+			return 0
+		}
+		if shift < 64 {
+			v |= uint64(b&0x7f) << shift
+		}
+		if b&0x80 == 0 {
+			return v
+		}
+	}
+}
+
+func (r *reader) sleb() int64 {
+	var v int64
+	shift := uint(0)
+	for {
+		b := r.u8()
+		if r.err != nil {
+			return 0
+		}
+		if shift < 64 {
+			v |= int64(b&0x7f) << shift
+		}
+		shift += 7
+		if b&0x80 == 0 {
+			if shift < 64 && b&0x40 != 0 {
+				v |= -1 << shift
+			}
+			return v
+		}
+	}
+}
+
+func (r *reader) cstring() string {
+	if r.err != nil {
+		return ""
+	}
+	for i := r.off; i < uint64(len(r.data)); i++ {
+		if r.data[i] == 0 {
+			s := string(r.data[r.off:i])
+			r.off = i + 1
+			return s
+		}
+	}
+
+	r.fail(fmt.Errorf("unterminated string at offset %#x", r.off))
+	return ""
+}
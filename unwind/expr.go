@@ -0,0 +1,254 @@
+package unwind
+
+import (
+	"fmt"
+	"io"
+)
+
+// DW_OP operations of the DWARF expressions used by call frame
+// information.
+const (
+	opAddr       = 0x03
+	opDeref      = 0x06
+	opConst1u    = 0x08
+	opConst1s    = 0x09
+	opConst2u    = 0x0a
+	opConst2s    = 0x0b
+	opConst4u    = 0x0c
+	opConst4s    = 0x0d
+	opConst8u    = 0x0e
+	opConst8s    = 0x0f
+	opConstu     = 0x10
+	opConsts     = 0x11
+	opDup        = 0x12
+	opDrop       = 0x13
+	opOver       = 0x14
+	opPick       = 0x15
+	opSwap       = 0x16
+	opRot        = 0x17
+	opAbs        = 0x19
+	opAnd        = 0x1a
+	opDiv        = 0x1b
+	opMinus      = 0x1c
+	opMod        = 0x1d
+	opMul        = 0x1e
+	opNeg        = 0x1f
+	opNot        = 0x20
+	opOr         = 0x21
+	opPlus       = 0x22
+	opPlusUconst = 0x23
+	opShl        = 0x24
+	opShr        = 0x25
+	opShra       = 0x26
+	opXor        = 0x27
+	opBra        = 0x28
+	opEq         = 0x29
+	opGe         = 0x2a
+	opGt         = 0x2b
+	opLe         = 0x2c
+	opLt         = 0x2d
+	opNe         = 0x2e
+	opSkip       = 0x2f
+	opLit0       = 0x30
+	opLit31      = 0x4f
+	opBreg0      = 0x70
+	opBreg31     = 0x8f
+	opBregx      = 0x92
+	opDerefSize  = 0x94
+	opNop        = 0x96
+)
+
+// maxExprSteps bounds the operations an expression may execute, since
+// branches can loop.
+const maxExprSteps = 10000
+
+// eval evaluates the DWARF expression expr with initial on the stack, reading
+// registers from regs and memory from mem, and returns the value on top of
+// the stack.
+func (s *section) eval(expr []byte, regs Registers, mem io.ReaderAt, initial ...uint64) (uint64, error) {
+	r := &reader{data: expr, order: s.order}
+	stack := append([]uint64(nil), initial...)
+
+	pop := func() uint64 {
+		if len(stack) == 0 {
+			r.fail(fmt.Errorf("expression stack underflow"))
+			return 0
+		}
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return v
+	}
+	push := func(v uint64) { stack = append(stack, v) }
+	deref := func(addr uint64, size int) uint64 {
+		v, err := readWord(mem, s.order, addr, size)
+		if err != nil {
+			r.fail(err)
+		}
+		return v
+	}
+	reg := func(n uint64) uint64 {
+		v, ok := regs[n]
+		if !ok {
+			r.fail(fmt.Errorf("expression reads unknown register %d", n))
+		}
+		return v
+	}
+	bool2 := func(b bool) uint64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	for steps := 0; r.off < uint64(len(expr)) && r.err == nil; steps++ {
+		if steps == maxExprSteps {
+			return 0, fmt.Errorf("expression does not terminate")
+		}
+
+		op := r.u8()
+		switch {
+		case op >= opLit0 && op <= opLit31:
+			push(uint64(op - opLit0))
+			continue
+		case op >= opBreg0 && op <= opBreg31:
+			push(reg(uint64(op-opBreg0)) + uint64(r.sleb()))
+			continue
+		}
+
+		switch op {
+		case opAddr:
+			push(r.addr(s.ptrSize))
+		case opDeref:
+			push(deref(pop(), s.ptrSize))
+		case opDerefSize:
+			n := int(r.u8())
+			push(deref(pop(), n))
+		case opConst1u:
+			push(uint64(r.u8()))
+		case opConst1s:
+			push(uint64(int8(r.u8())))
+		case opConst2u:
+			push(uint64(r.u16()))
+		case opConst2s:
+			push(uint64(int16(r.u16())))
+		case opConst4u:
+			push(uint64(r.u32()))
+		case opConst4s:
+			push(uint64(int32(r.u32())))
+		case opConst8u, opConst8s:
+			push(r.u64())
+		case opConstu:
+			push(r.uleb())
+		case opConsts:
+			push(uint64(r.sleb()))
+		case opDup:
+			v := pop()
+			push(v)
+			push(v)
+		case opDrop:
+			pop()
+		case opOver:
+			if len(stack) < 2 {
+				return 0, fmt.Errorf("expression stack underflow")
+			}
+			push(stack[len(stack)-2])
+		case opPick:
+			i := int(r.u8())
+			if i >= len(stack) {
+				return 0, fmt.Errorf("expression stack underflow")
+			}
+			push(stack[len(stack)-1-i])
+		case opSwap:
+			a, b := pop(), pop()
+			push(a)
+			push(b)
+		case opRot:
+			a, b, c := pop(), pop(), pop()
+			push(a)
+			push(c)
+			push(b)
+		case opAbs:
+			if v := int64(pop()); v < 0 {
+				push(uint64(-v))
+			} else {
+				push(uint64(v))
+			}
+		case opNeg:
+			push(uint64(-int64(pop())))
+		case opNot:
+			push(^pop())
+		case opPlusUconst:
+			push(pop() + r.uleb())
+		case opAnd, opDiv, opMinus, opMod, opMul, opOr, opPlus, opShl, opShr, opShra, opXor,
+			opEq, opGe, opGt, opLe, opLt, opNe:
+			b, a := pop(), pop()
+			var v uint64
+			switch op {
+			case opAnd:
+				v = a & b
+			case opDiv:
+				if b == 0 {
+					return 0, fmt.Errorf("division by zero in expression")
+				}
+				v = uint64(int64(a) / int64(b))
+			case opMinus:
+				v = a - b
+			case opMod:
+				if b == 0 {
+					return 0, fmt.Errorf("division by zero in expression")
+				}
+				v = a % b
+			case opMul:
+				v = a * b
+			case opOr:
+				v = a | b
+			case opPlus:
+				v = a + b
+			case opShl:
+				v = a << b
+			case opShr:
+				v = a >> b
+			case opShra:
+				v = uint64(int64(a) >> b)
+			case opXor:
+				v = a ^ b
+			case opEq:
+				v = bool2(a == b)
+			case opGe:
+				v = bool2(int64(a) >= int64(b))
+			case opGt:
+				v = bool2(int64(a) > int64(b))
+			case opLe:
+				v = bool2(int64(a) <= int64(b))
+			case opLt:
+				v = bool2(int64(a) < int64(b))
+			case opNe:
+				v = bool2(a != b)
+			}
+			push(v)
+		case opSkip, opBra:
+			off := int64(int16(r.u16()))
+			if op == opSkip || pop() != 0 {
+				to := int64(r.off) + off
+				if to < 0 || to > int64(len(expr)) {
+					return 0, fmt.Errorf("expression branches out of bounds")
+				}
+				r.off = uint64(to)
+			}
+		case opBregx:
+			n := r.uleb()
+			push(reg(n) + uint64(r.sleb()))
+		case opNop:
+		default:
+			return 0, fmt.Errorf("unsupported expression operation %#x", op)
+		}
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+	if len(stack) == 0 {
+		return 0, fmt.Errorf("expression leaves an empty stack")
+	}
+
+	return stack[len(stack)-1], nil
+}
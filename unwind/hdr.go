@@ -0,0 +1,69 @@
+package unwind
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// EHFrameHdr is the .eh_frame_hdr section, found through the
+// PT_GNU_EH_FRAME segment. It locates .eh_frame and lists its FDEs sorted
+// by address for binary search.
+type EHFrameHdr struct {
+	// EHFrame is the address of the .eh_frame section.
+	EHFrame uint64
+	// Entries is the search table, which the linker may leave out.
+	Entries []HdrEntry
+}
+
+// HdrEntry is an entry of the search table of .eh_frame_hdr: the address of
+// the FDE whose range starts at Begin.
+type HdrEntry struct {
+	Begin uint64
+	FDE   uint64
+}
+
+// ParseEHFrameHdr decodes an .eh_frame_hdr section loaded at addr, in a
+// file of the given byte order and pointer size.
+func ParseEHFrameHdr(data []byte, addr uint64, order binary.ByteOrder, ptrSize int) (*EHFrameHdr, error) {
+	s := newSection(data, addr, true, order, ptrSize)
+	r := s.reader(0)
+	if v := r.u8(); v != 1 {
+		if r.err != nil {
+			return nil, fmt.Errorf("invalid eh_frame_hdr: %w", r.err)
+		}
+		return nil, fmt.Errorf("unsupported eh_frame_hdr version: %d", v)
+	}
+	ptrEnc, countEnc, tableEnc := r.u8(), r.u8(), r.u8()
+
+	h := &EHFrameHdr{EHFrame: s.pointer(r, ptrEnc, addr)}
+	if countEnc != ptrOmit && tableEnc != ptrOmit {
+		n := s.pointer(r, countEnc, addr)
+		// Every entry takes two values of at least one byte.
+		if r.err == nil && n > uint64(len(data))/2 {
+			return nil, fmt.Errorf("eh_frame_hdr with %d entries exceeds %d bytes", n, len(data))
+		}
+		for i := uint64(0); i < n && r.err == nil; i++ {
+			h.Entries = append(h.Entries, HdrEntry{
+				Begin: s.pointer(r, tableEnc, addr),
+				FDE:   s.pointer(r, tableEnc, addr),
+			})
+		}
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("invalid eh_frame_hdr: %w", r.err)
+	}
+
+	return h, nil
+}
+
+// lookup returns the entry of the FDE that may cover pc: the last one
+// starting at or before it.
+func (h *EHFrameHdr) lookup(pc uint64) (HdrEntry, bool) {
+	i := sort.Search(len(h.Entries), func(i int) bool { return h.Entries[i].Begin > pc })
+	if i == 0 {
+		return HdrEntry{}, false
+	}
+
+	return h.Entries[i-1], true
+}
@@ -0,0 +1,265 @@
+package unwind
+
+import (
+	"fmt"
+	"maps"
+)
+
+// RuleKind is the way a rule recovers a value.
+type RuleKind int
+
+const (
+	// RuleUndefined marks a register whose value is lost, such as the
+	// return address of the outermost frame.
+	RuleUndefined RuleKind = iota
+	// RuleSameValue marks a register the frame leaves unchanged.
+	RuleSameValue
+	// RuleOffset saves a register at the address CFA+Offset.
+	RuleOffset
+	// RuleValOffset gives a register the value CFA+Offset.
+	RuleValOffset
+	// RuleRegister saves a register in register Reg.
+	RuleRegister
+	// RuleExpression saves a register at the address Expr computes from
+	// the CFA.
+	RuleExpression
+	// RuleValExpression gives a register the value Expr computes from the
+	// CFA. A CFA defined by an expression uses it too, with nothing pushed.
+	RuleValExpression
+	// RuleCFA defines the CFA as the value of register Reg plus Offset.
+	RuleCFA
+)
+
+// Rule recovers the CFA or the value a register had in the caller.
+type Rule struct {
+	Kind   RuleKind
+	Reg    uint64
+	Offset int64
+	Expr   []byte
+}
+
+// Row holds the rules in effect at an address.
+type Row struct {
+	CFA Rule
+	// Regs holds the rules of the registers by DWARF register number.
+	// Registers without one keep their value, like with RuleSameValue.
+	Regs map[uint64]Rule
+	// RASigned is set when the return address is signed with the AArch64
+	// pointer authentication, as toggled by DW_CFA_AARCH64_negate_ra_state.
+	RASigned bool
+}
+
+// DW_CFA instructions. The first three are in the high two bits of the
+// opcode, with their operand in the low six.
+const (
+	cfaAdvanceLoc = 0x40
+	cfaOffset     = 0x80
+	cfaRestore    = 0xc0
+
+	cfaNop                  = 0x00
+	cfaSetLoc               = 0x01
+	cfaAdvanceLoc1          = 0x02
+	cfaAdvanceLoc2          = 0x03
+	cfaAdvanceLoc4          = 0x04
+	cfaOffsetExtended       = 0x05
+	cfaRestoreExtended      = 0x06
+	cfaUndefined            = 0x07
+	cfaSameValue            = 0x08
+	cfaRegister             = 0x09
+	cfaRememberState        = 0x0a
+	cfaRestoreState         = 0x0b
+	cfaDefCFA               = 0x0c
+	cfaDefCFARegister       = 0x0d
+	cfaDefCFAOffset         = 0x0e
+	cfaDefCFAExpression     = 0x0f
+	cfaExpression           = 0x10
+	cfaOffsetExtendedSF     = 0x11
+	cfaDefCFASF             = 0x12
+	cfaDefCFAOffsetSF       = 0x13
+	cfaValOffset            = 0x14
+	cfaValOffsetSF          = 0x15
+	cfaValExpression        = 0x16
+	cfaAArch64NegateRAState = 0x2d
+	cfaGNUArgsSize          = 0x2e
+	cfaGNUNegOffsetExtended = 0x2f
+)
+
+// Row returns the rules in effect at pc, running the instructions of the
+// CIE and then those of f up to pc.
+func (f *FDE) Row(pc uint64) (*Row, error) {
+	if pc < f.Begin || pc >= f.End {
+		return nil, fmt.Errorf("address %#x is outside the fde [%#x, %#x)", pc, f.Begin, f.End)
+	}
+
+	cie := f.CIE
+	initial := &Row{Regs: map[uint64]Rule{}}
+	if err := f.run(cie.Instructions, 0, initial, nil, ^uint64(0)); err != nil {
+		return nil, fmt.Errorf("invalid initial instructions: %w", err)
+	}
+
+	row := initial.clone()
+	if err := f.run(f.Instructions, f.insnOff, row, initial, pc); err != nil {
+		return nil, err
+	}
+
+	return row, nil
+}
+
+func (r *Row) clone() *Row {
+	c := *r
+	c.Regs = maps.Clone(r.Regs)
+	return &c
+}
+
+// run executes the call frame instructions insns, starting at offset off of
+// the section, on row until the location passes pc. initial is the row the
+// restore instructions go back to, nil for the instructions of the CIE.
+func (f *FDE) run(insns []byte, off uint64, row, initial *Row, pc uint64) error {
+	cie, s := f.CIE, f.sec
+	r := &reader{data: insns, order: s.order}
+	loc := f.Begin
+	var stack []*Row
+
+	advance := func(delta uint64) bool {
+		loc += delta * cie.CodeAlign
+		return loc > pc
+	}
+	restore := func(reg uint64) error {
+		if initial == nil {
+			return fmt.Errorf("restore instruction in cie")
+		}
+		if rule, ok := initial.Regs[reg]; ok {
+			row.Regs[reg] = rule
+		} else {
+			delete(row.Regs, reg)
+		}
+		return nil
+	}
+	offset := func(v int64) int64 { return v * cie.DataAlign }
+
+	for r.off < uint64(len(insns)) {
+		op := r.u8()
+		switch op & 0xc0 {
+		case cfaAdvanceLoc:
+			if advance(uint64(op & 0x3f)) {
+				return nil
+			}
+			continue
+		case cfaOffset:
+			row.Regs[uint64(op&0x3f)] = Rule{Kind: RuleOffset, Offset: offset(int64(r.uleb()))}
+			continue
+		case cfaRestore:
+			if err := restore(uint64(op & 0x3f)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch op {
+		case cfaNop:
+		case cfaSetLoc:
+			// The operand is encoded like the addresses of the FDE and
+			// relative to its own position in the section.
+			if s.eh {
+				sr := s.reader(off + r.off)
+				loc = s.pointer(sr, cie.FDEEncoding, 0)
+				r.off = sr.off - off
+				if sr.err != nil {
+					r.fail(sr.err)
+				}
+			} else {
+				loc = r.addr(s.addrSize(cie))
+			}
+			if loc > pc {
+				return r.err
+			}
+		case cfaAdvanceLoc1:
+			if advance(uint64(r.u8())) {
+				return r.err
+			}
+		case cfaAdvanceLoc2:
+			if advance(uint64(r.u16())) {
+				return r.err
+			}
+		case cfaAdvanceLoc4:
+			if advance(uint64(r.u32())) {
+				return r.err
+			}
+		case cfaOffsetExtended:
+			reg := r.uleb()
+			row.Regs[reg] = Rule{Kind: RuleOffset, Offset: offset(int64(r.uleb()))}
+		case cfaOffsetExtendedSF:
+			reg := r.uleb()
+			row.Regs[reg] = Rule{Kind: RuleOffset, Offset: offset(r.sleb())}
+		case cfaGNUNegOffsetExtended:
+			reg := r.uleb()
+			row.Regs[reg] = Rule{Kind: RuleOffset, Offset: -offset(int64(r.uleb()))}
+		case cfaValOffset:
+			reg := r.uleb()
+			row.Regs[reg] = Rule{Kind: RuleValOffset, Offset: offset(int64(r.uleb()))}
+		case cfaValOffsetSF:
+			reg := r.uleb()
+			row.Regs[reg] = Rule{Kind: RuleValOffset, Offset: offset(r.sleb())}
+		case cfaRestoreExtended:
+			if err := restore(r.uleb()); err != nil {
+				return err
+			}
+		case cfaUndefined:
+			row.Regs[r.uleb()] = Rule{Kind: RuleUndefined}
+		case cfaSameValue:
+			row.Regs[r.uleb()] = Rule{Kind: RuleSameValue}
+		case cfaRegister:
+			reg := r.uleb()
+			row.Regs[reg] = Rule{Kind: RuleRegister, Reg: r.uleb()}
+		case cfaRememberState:
+			stack = append(stack, row.clone())
+		case cfaRestoreState:
+			if len(stack) == 0 {
+				return fmt.Errorf("restore state without remembered state")
+			}
+			// The CFA is part of the state too.
+			*row = *stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+		case cfaDefCFA:
+			reg := r.uleb()
+			row.CFA = Rule{Kind: RuleCFA, Reg: reg, Offset: int64(r.uleb())}
+		case cfaDefCFASF:
+			reg := r.uleb()
+			row.CFA = Rule{Kind: RuleCFA, Reg: reg, Offset: offset(r.sleb())}
+		case cfaDefCFARegister:
+			if row.CFA.Kind != RuleCFA {
+				return fmt.Errorf("cfa register set without a register rule")
+			}
+			row.CFA.Reg = r.uleb()
+		case cfaDefCFAOffset:
+			if row.CFA.Kind != RuleCFA {
+				return fmt.Errorf("cfa offset set without a register rule")
+			}
+			row.CFA.Offset = int64(r.uleb())
+		case cfaDefCFAOffsetSF:
+			if row.CFA.Kind != RuleCFA {
+				return fmt.Errorf("cfa offset set without a register rule")
+			}
+			row.CFA.Offset = offset(r.sleb())
+		case cfaDefCFAExpression:
+			row.CFA = Rule{Kind: RuleValExpression, Expr: r.bytes(r.uleb())}
+		case cfaExpression:
+			reg := r.uleb()
+			row.Regs[reg] = Rule{Kind: RuleExpression, Expr: r.bytes(r.uleb())}
+		case cfaValExpression:
+			reg := r.uleb()
+			row.Regs[reg] = Rule{Kind: RuleValExpression, Expr: r.bytes(r.uleb())}
+		case cfaGNUArgsSize:
+			r.uleb()
+		case cfaAArch64NegateRAState:
+			row.RASigned = !row.RASigned
+		default:
+			return fmt.Errorf("unknown call frame instruction %#x", op)
+		}
+		if r.err != nil {
+			return r.err
+		}
+	}
+
+	return r.err
+}
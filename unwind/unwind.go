@@ -0,0 +1,387 @@
+package unwind
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/hnts/goelftools/elf"
+)
+
+// Registers holds the registers of a frame by DWARF register number.
+// Registers whose value is unknown are absent.
+type Registers map[uint64]uint64
+
+// Arch gives the DWARF numbers of the registers an architecture unwinds
+// with.
+type Arch struct {
+	// PC is the register holding the program counter, SP the stack pointer
+	// and RA the default return address column.
+	PC, SP, RA uint64
+	// names maps the names CoreThread gives registers to their numbers.
+	names map[string]uint64
+}
+
+var (
+	// AMD64 is x86-64, whose return address column 16 stands for rip.
+	AMD64 = &Arch{PC: 16, SP: 7, RA: 16, names: map[string]uint64{
+		"rax": 0, "rdx": 1, "rcx": 2, "rbx": 3, "rsi": 4, "rdi": 5, "rbp": 6, "rsp": 7,
+		"r8": 8, "r9": 9, "r10": 10, "r11": 11, "r12": 12, "r13": 13, "r14": 14, "r15": 15,
+		"rip": 16,
+	}}
+	// ARM64 is AArch64, which returns to the link register x30. The
+	// program counter is given number 32.
+	ARM64 = &Arch{PC: 32, SP: 31, RA: 30, names: map[string]uint64{
+		"x0": 0, "x1": 1, "x2": 2, "x3": 3, "x4": 4, "x5": 5, "x6": 6, "x7": 7,
+		"x8": 8, "x9": 9, "x10": 10, "x11": 11, "x12": 12, "x13": 13, "x14": 14, "x15": 15,
+		"x16": 16, "x17": 17, "x18": 18, "x19": 19, "x20": 20, "x21": 21, "x22": 22, "x23": 23,
+		"x24": 24, "x25": 25, "x26": 26, "x27": 27, "x28": 28, "x29": 29, "x30": 30,
+		"sp": 31, "pc": 32,
+	}}
+)
+
+// ArchFor returns the Arch of machine m, or nil if it is not supported.
+func ArchFor(m elf.Machine) *Arch {
+	switch m {
+	case elf.EM_X86_64:
+		return AMD64
+	case elf.EM_AARCH64:
+		return ARM64
+	}
+
+	return nil
+}
+
+// CoreRegisters returns the general registers of thread t of the core file
+// c by DWARF register number.
+func CoreRegisters(c *elf.CoreFile, t *elf.CoreThread) (Registers, error) {
+	arch := ArchFor(c.Header.Machine)
+	if arch == nil {
+		return nil, fmt.Errorf("unsupported machine: %d", c.Header.Machine)
+	}
+
+	regs := Registers{}
+	for _, r := range t.Registers {
+		if n, ok := arch.names[r.Name]; ok {
+			regs[n] = r.Value
+		}
+	}
+
+	return regs, nil
+}
+
+// Table is the call frame information of an ELF file.
+type Table struct {
+	Arch *Arch
+	// Bias is added to the addresses of the file to give the addresses it
+	// is loaded at, for position independent files.
+	Bias uint64
+
+	hdr *EHFrameHdr
+	eh  *section
+	// fdes holds the FDEs sorted by address: those of .debug_frame, and
+	// those of .eh_frame when there is no search table.
+	fdes []*FDE
+}
+
+// New reads the call frame information of e from .eh_frame_hdr, .eh_frame
+// and .debug_frame. Files without section headers are handled through the
+// PT_GNU_EH_FRAME segment.
+func New(e *elf.File) (*Table, error) {
+	ptrSize := 8
+	if e.Header.Class() == elf.ELFCLASS32 {
+		ptrSize = 4
+	}
+	t := &Table{Arch: ArchFor(e.Header.Machine)}
+
+	var hdrData []byte
+	var hdrAddr uint64
+	if s := e.SectionByName(".eh_frame_hdr"); s != nil {
+		data, err := s.Data()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read .eh_frame_hdr: %w", err)
+		}
+		hdrData, hdrAddr = data, s.Header.Addr
+	} else if sgs := e.SegmentsByType(elf.PT_GNU_EH_FRAME); len(sgs) > 0 {
+		data, err := sgs[0].Data()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read eh_frame_hdr segment: %w", err)
+		}
+		hdrData, hdrAddr = data, sgs[0].Header.Vaddr
+	}
+	if hdrData != nil {
+		hdr, err := ParseEHFrameHdr(hdrData, hdrAddr, e.Endianness, ptrSize)
+		if err != nil {
+			return nil, err
+		}
+		t.hdr = hdr
+	}
+
+	if s := e.SectionByName(".eh_frame"); s != nil && s.Header.Type != elf.SHT_NOBITS {
+		data, err := s.Data()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read .eh_frame: %w", err)
+		}
+		t.eh = newSection(data, s.Header.Addr, true, e.Endianness, ptrSize)
+	} else if t.hdr != nil && t.hdr.EHFrame != 0 {
+		// Without section headers, .eh_frame runs at most to the end of
+		// the segment holding it.
+		sg := e.SegmentForAddr(t.hdr.EHFrame)
+		if sg == nil {
+			return nil, fmt.Errorf("eh_frame address %#x is not mapped", t.hdr.EHFrame)
+		}
+		end := sg.Header.Vaddr + sg.Header.Filesz
+		if t.hdr.EHFrame >= end {
+			return nil, fmt.Errorf("eh_frame address %#x is not backed by the file", t.hdr.EHFrame)
+		}
+		data, err := e.ReadAddr(t.hdr.EHFrame, end-t.hdr.EHFrame)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read eh_frame: %w", err)
+		}
+		t.eh = newSection(data, t.hdr.EHFrame, true, e.Endianness, ptrSize)
+	}
+
+	if t.eh != nil && (t.hdr == nil || len(t.hdr.Entries) == 0) {
+		fdes, err := t.eh.fdes()
+		if err != nil {
+			return nil, fmt.Errorf("invalid .eh_frame: %w", err)
+		}
+		t.fdes = append(t.fdes, fdes...)
+	}
+
+	if s := e.SectionByName(".debug_frame"); s != nil && s.Header.Type != elf.SHT_NOBITS {
+		data, err := s.Data()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read .debug_frame: %w", err)
+		}
+		fdes, err := ParseDebugFrame(data, e.Endianness, ptrSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid .debug_frame: %w", err)
+		}
+		t.fdes = append(t.fdes, fdes...)
+	}
+
+	sort.SliceStable(t.fdes, func(i, j int) bool { return t.fdes[i].Begin < t.fdes[j].Begin })
+
+	return t, nil
+}
+
+// FDE returns the FDE covering the address pc of the file, or nil if there
+// is none. The search table of .eh_frame_hdr is used if there is one.
+func (t *Table) FDE(pc uint64) (*FDE, error) {
+	if t.hdr != nil && t.eh != nil {
+		if ent, ok := t.hdr.lookup(pc); ok {
+			if ent.FDE < t.eh.addr || ent.FDE-t.eh.addr >= uint64(len(t.eh.data)) {
+				return nil, fmt.Errorf("fde address %#x is outside eh_frame", ent.FDE)
+			}
+			fde, _, err := t.eh.entryAt(ent.FDE - t.eh.addr)
+			if err != nil {
+				return nil, err
+			}
+			if fde != nil && pc >= fde.Begin && pc < fde.End {
+				return fde, nil
+			}
+		}
+	}
+
+	i := sort.Search(len(t.fdes), func(i int) bool { return t.fdes[i].Begin > pc })
+	if i > 0 && pc < t.fdes[i-1].End {
+		return t.fdes[i-1], nil
+	}
+
+	return nil, nil
+}
+
+// frameFDE returns the FDE of the frame whose program counter is pc, and
+// the address of the file it is looked up at.
+func (t *Table) frameFDE(pc uint64, ret bool) (*FDE, uint64, error) {
+	lookup := pc - t.Bias
+	if ret {
+		lookup--
+	}
+	fde, err := t.FDE(lookup)
+
+	return fde, lookup, err
+}
+
+// Step computes the registers of the caller of the frame whose registers
+// are regs, reading the registers saved on the stack from mem. When ret is
+// set, the program counter of regs is a return address, and the call
+// before it is looked up instead: a call to a function that does not
+// return may be the last instruction of a function. It returns nil at the
+// outermost frame, whose return address is undefined.
+func (t *Table) Step(regs Registers, mem io.ReaderAt, ret bool) (Registers, error) {
+	if t.Arch == nil {
+		return nil, fmt.Errorf("unsupported architecture")
+	}
+	pc, ok := regs[t.Arch.PC]
+	if !ok {
+		return nil, fmt.Errorf("program counter unknown")
+	}
+
+	fde, lookup, err := t.frameFDE(pc, ret)
+	if err != nil {
+		return nil, err
+	}
+	if fde == nil {
+		return nil, fmt.Errorf("no call frame information for address %#x", pc)
+	}
+	row, err := fde.Row(lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfa uint64
+	switch row.CFA.Kind {
+	case RuleCFA:
+		v, ok := regs[row.CFA.Reg]
+		if !ok {
+			return nil, fmt.Errorf("cfa register %d unknown at %#x", row.CFA.Reg, pc)
+		}
+		cfa = v + uint64(row.CFA.Offset)
+	case RuleValExpression:
+		if cfa, err = fde.sec.eval(row.CFA.Expr, regs, mem); err != nil {
+			return nil, fmt.Errorf("invalid cfa expression at %#x: %w", pc, err)
+		}
+	default:
+		return nil, fmt.Errorf("no cfa rule at %#x", pc)
+	}
+
+	caller := Registers{}
+	for n, v := range regs {
+		if _, ok := row.Regs[n]; !ok && n != t.Arch.PC {
+			caller[n] = v
+		}
+	}
+	for n, rule := range row.Regs {
+		v, ok, err := recoverRegister(fde.sec, n, rule, cfa, regs, mem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to recover register %d at %#x: %w", n, pc, err)
+		}
+		if ok {
+			caller[n] = v
+		} else {
+			delete(caller, n)
+		}
+	}
+
+	ra, ok := caller[fde.CIE.RA]
+	if !ok {
+		return nil, nil
+	}
+	if row.RASigned {
+		// Strip the pointer authentication code from the top bits.
+		ra &= 1<<48 - 1
+	}
+	caller[t.Arch.PC] = ra
+	caller[t.Arch.SP] = cfa
+
+	return caller, nil
+}
+
+// recoverRegister returns the value rule gives register n in the caller,
+// and whether it is known.
+func recoverRegister(s *section, n uint64, rule Rule, cfa uint64, regs Registers, mem io.ReaderAt) (uint64, bool, error) {
+	switch rule.Kind {
+	case RuleUndefined:
+		return 0, false, nil
+	case RuleSameValue:
+		v, ok := regs[n]
+		return v, ok, nil
+	case RuleOffset:
+		v, err := readWord(mem, s.order, cfa+uint64(rule.Offset), s.ptrSize)
+		return v, err == nil, err
+	case RuleValOffset:
+		return cfa + uint64(rule.Offset), true, nil
+	case RuleRegister:
+		v, ok := regs[rule.Reg]
+		return v, ok, nil
+	case RuleExpression:
+		addr, err := s.eval(rule.Expr, regs, mem, cfa)
+		if err != nil {
+			return 0, false, err
+		}
+		v, err := readWord(mem, s.order, addr, s.ptrSize)
+		return v, err == nil, err
+	case RuleValExpression:
+		v, err := s.eval(rule.Expr, regs, mem, cfa)
+		return v, err == nil, err
+	}
+
+	return 0, false, fmt.Errorf("invalid rule kind %d", rule.Kind)
+}
+
+// Unwind walks the stack whose innermost frame has the registers regs and
+// returns the program counter of every frame, innermost first. tables hold
+// the call frame information of the files mapped into the process, each
+// with the Bias it is loaded at. Unwinding stops at the outermost frame, at
+// an address no table covers, when the stack pointer stops growing, or
+// after max frames.
+func Unwind(tables []*Table, regs Registers, mem io.ReaderAt, max int) ([]uint64, error) {
+	if len(tables) == 0 || tables[0].Arch == nil {
+		return nil, fmt.Errorf("unsupported architecture")
+	}
+	arch := tables[0].Arch
+
+	var pcs []uint64
+	ret := false
+	for len(pcs) < max {
+		pc, ok := regs[arch.PC]
+		if !ok || pc == 0 {
+			break
+		}
+		pcs = append(pcs, pc)
+
+		var t *Table
+		var fde *FDE
+		for _, tt := range tables {
+			f, _, err := tt.frameFDE(pc, ret)
+			if err != nil {
+				return pcs, err
+			}
+			if f != nil {
+				t, fde = tt, f
+				break
+			}
+		}
+		if t == nil {
+			break
+		}
+
+		caller, err := t.Step(regs, mem, ret)
+		if err != nil {
+			return pcs, err
+		}
+		if caller == nil || caller[arch.SP] <= regs[arch.SP] {
+			break
+		}
+		// The frame interrupted by a signal resumes at its program
+		// counter rather than returning to it.
+		regs, ret = caller, !fde.CIE.SignalFrame
+	}
+
+	return pcs, nil
+}
+
+// readWord reads a word of size bytes at addr.
+func readWord(mem io.ReaderAt, order binary.ByteOrder, addr uint64, size int) (uint64, error) {
+	var b [8]byte
+	if size != 1 && size != 2 && size != 4 && size != 8 {
+		return 0, fmt.Errorf("invalid read size: %d", size)
+	}
+	if _, err := mem.ReadAt(b[:size], int64(addr)); err != nil {
+		return 0, fmt.Errorf("failed to read memory at %#x: %w", addr, err)
+	}
+
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(order.Uint16(b[:])), nil
+	case 4:
+		return uint64(order.Uint32(b[:])), nil
+	}
+
+	return order.Uint64(b[:]), nil
+}
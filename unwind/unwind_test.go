@@ -0,0 +1,365 @@
+package unwind_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hnts/goelftools/elf"
+	"github.com/hnts/goelftools/unwind"
+)
+
+const crash = "../testdata/crash_linux_amd64"
+
+func mustNew(t *testing.T, raw []byte) *elf.File {
+	t.Helper()
+
+	e, err := elf.New(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return e
+}
+
+// crashTable returns the unwind table of the crash binary.
+func crashTable(t *testing.T) *unwind.Table {
+	t.Helper()
+
+	raw, err := os.ReadFile(crash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tab, err := unwind.New(mustNew(t, raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tab
+}
+
+// memory is the stack of a synthesized process, mapped at base.
+type memory struct {
+	base uint64
+	data []byte
+}
+
+func (m *memory) ReadAt(p []byte, off int64) (int, error) {
+	addr := uint64(off)
+	if addr < m.base || addr-m.base+uint64(len(p)) > uint64(len(m.data)) {
+		return 0, io.EOF
+	}
+
+	return copy(p, m.data[addr-m.base:]), nil
+}
+
+func TestEHFrame(t *testing.T) {
+	raw, err := os.ReadFile(crash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := mustNew(t, raw)
+	s := e.SectionByName(".eh_frame")
+	data, err := s.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fdes, err := unwind.ParseEHFrame(data, s.Header.Addr, binary.LittleEndian, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ranges [][2]uint64
+	for _, f := range fdes {
+		ranges = append(ranges, [2]uint64{f.Begin, f.End})
+	}
+	want := [][2]uint64{{0x1070, 0x1092}, {0x1020, 0x1060}, {0x1060, 0x1068}, {0x1159, 0x116c}, {0x116c, 0x11ab}}
+	if !reflect.DeepEqual(ranges, want) {
+		t.Errorf("FDE ranges:\n\thave %#v\n\twant %#v\n", ranges, want)
+	}
+	if cie := fdes[0].CIE; cie.Augmentation != "zR" || cie.CodeAlign != 1 || cie.DataAlign != -8 || cie.RA != 16 || cie.FDEEncoding != 0x1b {
+		t.Errorf("have CIE %#v", cie)
+	}
+
+	// Without section headers, the FDEs are found through PT_GNU_EH_FRAME.
+	stripped := append([]byte(nil), raw...)
+	copy(stripped[40:48], make([]byte, 8))
+	copy(stripped[60:64], make([]byte, 4))
+
+	for _, raw := range [][]byte{raw, stripped} {
+		tab, err := unwind.New(mustNew(t, raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range fdes {
+			for _, pc := range []uint64{want.Begin, want.End - 1} {
+				f, err := tab.FDE(pc)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if f == nil || f.Begin != want.Begin || f.End != want.End || !bytes.Equal(f.Instructions, want.Instructions) {
+					t.Errorf("FDE(%#x):\n\thave %#v\n\twant %#v\n", pc, f, want)
+				}
+			}
+		}
+		if f, err := tab.FDE(0x11ab); f != nil || err != nil {
+			t.Errorf("FDE(0x11ab): have %#v, %v", f, err)
+		}
+	}
+}
+
+func TestRow(t *testing.T) {
+	tab := crashTable(t)
+
+	rbp := unwind.Rule{Kind: unwind.RuleOffset, Offset: -16}
+	rip := unwind.Rule{Kind: unwind.RuleOffset, Offset: -8}
+	for _, tt := range []struct {
+		pc  uint64
+		cfa unwind.Rule
+		rbp bool
+	}{
+		{0x116c, unwind.Rule{Kind: unwind.RuleCFA, Reg: 7, Offset: 8}, false},
+		{0x116d, unwind.Rule{Kind: unwind.RuleCFA, Reg: 7, Offset: 16}, true},
+		{0x1170, unwind.Rule{Kind: unwind.RuleCFA, Reg: 6, Offset: 16}, true},
+		{0x11a9, unwind.Rule{Kind: unwind.RuleCFA, Reg: 6, Offset: 16}, true},
+		// The epilogue pops rbp, which keeps the rule of the prologue.
+		{0x11aa, unwind.Rule{Kind: unwind.RuleCFA, Reg: 7, Offset: 8}, true},
+	} {
+		f, err := tab.FDE(tt.pc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		row, err := f.Row(tt.pc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &unwind.Row{CFA: tt.cfa, Regs: map[uint64]unwind.Rule{16: rip}}
+		if tt.rbp {
+			want.Regs[6] = rbp
+		}
+		if !reflect.DeepEqual(row, want) {
+			t.Errorf("Row(%#x):\n\thave %#v\n\twant %#v\n", tt.pc, row, want)
+		}
+	}
+
+	f, err := tab.FDE(0x1070)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Row(0x1092); err == nil {
+		t.Errorf("Row(0x1092): want error")
+	}
+}
+
+func TestStepExpression(t *testing.T) {
+	tab := crashTable(t)
+
+	// The CFA of the PLT entries is rsp+8, plus 8 once the entry has pushed
+	// the index of its relocation at offset 11.
+	mem := &memory{base: 0x7000, data: make([]byte, 0x20)}
+	binary.LittleEndian.PutUint64(mem.data[0x00:], 0x1111)
+	binary.LittleEndian.PutUint64(mem.data[0x08:], 0x2222)
+	for _, tt := range []struct {
+		pc, ra, sp uint64
+	}{
+		{0x1035, 0x1111, 0x7008},
+		{0x103b, 0x2222, 0x7010},
+	} {
+		caller, err := tab.Step(unwind.Registers{16: tt.pc, 7: 0x7000}, mem, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := unwind.Registers{16: tt.ra, 7: tt.sp}
+		if !reflect.DeepEqual(caller, want) {
+			t.Errorf("Step at %#x:\n\thave %#v\n\twant %#v\n", tt.pc, caller, want)
+		}
+	}
+
+	// The return address of _start is undefined.
+	caller, err := tab.Step(unwind.Registers{16: 0x1070, 7: 0x7000}, mem, false)
+	if caller != nil || err != nil {
+		t.Errorf("Step at _start: have %#v, %v", caller, err)
+	}
+}
+
+func TestUnwindCore(t *testing.T) {
+	gz, err := os.ReadFile("../testdata/core_linux_amd64.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := elf.NewCoreFile(mustNew(t, raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tab := crashTable(t)
+	tab.Bias = c.Mappings[0].Start
+
+	regs, err := unwind.CoreRegisters(c, c.Threads[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if regs[16] != 0x5638279aa1a7 || regs[7] != 0x7fffb5ddbfb0 {
+		t.Fatalf("have rip %#x and rsp %#x", regs[16], regs[7])
+	}
+
+	// main keeps a frame pointer, which the caller is checked against.
+	rbp := regs[6]
+	savedRBP, err := c.ReadAddr(rbp, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	savedRIP, err := c.ReadAddr(rbp+8, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caller, err := tab.Step(regs, c, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if caller[16] != binary.LittleEndian.Uint64(savedRIP) || caller[7] != rbp+16 || caller[6] != binary.LittleEndian.Uint64(savedRBP) {
+		t.Errorf("have caller rip %#x, rsp %#x and rbp %#x", caller[16], caller[7], caller[6])
+	}
+	if caller[3] != regs[3] {
+		t.Errorf("have caller rbx %#x, want %#x", caller[3], regs[3])
+	}
+
+	pcs, err := unwind.Unwind([]*unwind.Table{tab}, regs, c, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint64{regs[16], caller[16]}; !reflect.DeepEqual(pcs, want) {
+		t.Fatalf("Unwind:\n\thave %#x\n\twant %#x\n", pcs, want)
+	}
+	var lib string
+	for _, m := range c.Mappings {
+		if pcs[1] >= m.Start && pcs[1] < m.End {
+			lib = m.Path
+		}
+	}
+	if !strings.Contains(lib, "libc.so") {
+		t.Errorf("return address of main %#x is in %q", pcs[1], lib)
+	}
+}
+
+// arm64DebugFrame is a .debug_frame section with the FDE of a function at
+// 0x1000 that signs its return address and keeps a frame pointer.
+var arm64DebugFrame = []byte{
+	// CIE: version 4, address size 8, code alignment 4, data alignment -8,
+	// return address x30, DW_CFA_def_cfa: sp ofs 0.
+	0x10, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+	0x04, 0x00, 0x08, 0x00, 0x04, 0x78, 0x1e,
+	0x0c, 0x1f, 0x00, 0x00, 0x00,
+	// FDE of [0x1000, 0x1040).
+	0x20, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x10, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x41, 0x2d, // paciasp
+	0x41, 0x0e, 0x10, 0x9d, 0x02, 0x9e, 0x01, // stp x29, x30, [sp, #-16]!
+	0x41, 0x0d, 0x1d, // mov x29, sp
+}
+
+func TestDebugFrameARM64(t *testing.T) {
+	fdes, err := unwind.ParseDebugFrame(arm64DebugFrame, binary.LittleEndian, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fdes) != 1 || fdes[0].Begin != 0x1000 || fdes[0].End != 0x1040 || fdes[0].CIE.AddressSize != 8 {
+		t.Fatalf("have FDEs %#v", fdes)
+	}
+
+	row, err := fdes[0].Row(0x1010)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &unwind.Row{
+		CFA: unwind.Rule{Kind: unwind.RuleCFA, Reg: 29, Offset: 16},
+		Regs: map[uint64]unwind.Rule{
+			29: {Kind: unwind.RuleOffset, Offset: -16},
+			30: {Kind: unwind.RuleOffset, Offset: -8},
+		},
+		RASigned: true,
+	}
+	if !reflect.DeepEqual(row, want) {
+		t.Errorf("Row(0x1010):\n\thave %#v\n\twant %#v\n", row, want)
+	}
+
+	b := elf.NewBuilder(elf.ELFCLASS64, binary.LittleEndian, elf.EM_AARCH64, elf.ET_EXEC)
+	b.AddSection(".text", elf.SectionHeader{Type: elf.SHT_PROGBITS, Flags: elf.SHF_ALLOC | elf.SHF_EXECINSTR, Addr: 0x1000, Addralign: 4}, make([]byte, 0x40))
+	b.AddSection(".debug_frame", elf.SectionHeader{Type: elf.SHT_PROGBITS, Addralign: 8}, arm64DebugFrame)
+	out, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tab, err := unwind.New(mustNew(t, out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tab.Arch != unwind.ARM64 {
+		t.Fatalf("have arch %#v", tab.Arch)
+	}
+
+	mem := &memory{base: 0x7000, data: make([]byte, 0x10)}
+	binary.LittleEndian.PutUint64(mem.data[0:], 0x7100)
+	binary.LittleEndian.PutUint64(mem.data[8:], 0x002a000000002000)
+	caller, err := tab.Step(unwind.Registers{32: 0x1010, 31: 0x7000, 29: 0x7000, 19: 7}, mem, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRegs := unwind.Registers{32: 0x2000, 31: 0x7010, 29: 0x7100, 30: 0x002a000000002000, 19: 7}
+	if !reflect.DeepEqual(caller, wantRegs) {
+		t.Errorf("Step:\n\thave %#v\n\twant %#v\n", caller, wantRegs)
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	badVersion := append([]byte(nil), arm64DebugFrame...)
+	badVersion[8] = 2
+	badCIE := append([]byte(nil), arm64DebugFrame...)
+	badCIE[24] = 0x40
+	for _, tt := range []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"truncated length", arm64DebugFrame[:2], "unexpected end of data"},
+		{"length beyond section", arm64DebugFrame[:30], "exceeds the section"},
+		{"cie version", badVersion, "unsupported cie version"},
+		{"cie pointer", badCIE, "invalid cie of fde"},
+	} {
+		_, err := unwind.ParseDebugFrame(tt.data, binary.LittleEndian, 8)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: have error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+
+	if _, err := unwind.ParseEHFrameHdr([]byte{2, 0x1b, 0x03, 0x3b}, 0, binary.LittleEndian, 8); err == nil {
+		t.Errorf("ParseEHFrameHdr: want error for version 2")
+	}
+	if _, err := unwind.ParseEHFrameHdr([]byte{1, 0x1b, 0x03, 0x3b, 0, 0, 0, 0, 0xff, 0xff, 0, 0}, 0, binary.LittleEndian, 8); err == nil {
+		t.Errorf("ParseEHFrameHdr: want error for entry count")
+	}
+
+	badInsn := append([]byte(nil), arm64DebugFrame...)
+	badInsn[len(badInsn)-3] = 0x3f
+	fdes, err := unwind.ParseDebugFrame(badInsn, binary.LittleEndian, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fdes[0].Row(0x1010); err == nil || !strings.Contains(err.Error(), "unknown call frame instruction") {
+		t.Errorf("Row: have error %v", err)
+	}
+}